@@ -110,6 +110,13 @@ type KluctlDeploymentSpec struct {
 	// +optional
 	HelmCredentials []HelmCredentials `json:"helmCredentials,omitempty"`
 
+	// HelmRequireVerification instructs kluctl to require all non-local Helm Charts to have verification configured
+	// in their helm-chart.yaml.
+	// Equivalent to using '--helm-require-verification' when calling kluctl.
+	// +kubebuilder:default:=false
+	// +optional
+	HelmRequireVerification bool `json:"helmRequireVerification,omitempty"`
+
 	// The name of the Kubernetes service account to use while deploying.
 	// If not specified, the default service account is used.
 	// +optional
@@ -185,6 +192,33 @@ type KluctlDeploymentSpec struct {
 	// +optional
 	AbortOnError bool `json:"abortOnError,omitempty"`
 
+	// ApplyConcurrency specifies the maximum number of deployment items to apply in parallel.
+	// Equivalent to using '--apply-concurrency' when calling kluctl.
+	// +kubebuilder:default:=8
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ApplyConcurrency int `json:"applyConcurrency,omitempty"`
+
+	// ApplyItemConcurrency specifies the maximum number of objects to apply in parallel inside a single deployment item.
+	// If greater than 1, Namespaces and CustomResourceDefinitions of the item are applied first, one after another.
+	// Equivalent to using '--apply-item-concurrency' when calling kluctl.
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ApplyItemConcurrency int `json:"applyItemConcurrency,omitempty"`
+
+	// K8sQps limits the number of requests per second sent to the Kubernetes API server.
+	// Equivalent to using '--k8s-qps' when calling kluctl.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	K8sQps int `json:"k8sQps,omitempty"`
+
+	// K8sBurst specifies the maximum burst of requests allowed by K8sQps.
+	// Equivalent to using '--k8s-burst' when calling kluctl.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	K8sBurst int `json:"k8sBurst,omitempty"`
+
 	// IncludeTags instructs kluctl to only include deployments with given tags.
 	// Equivalent to using '--include-tag' when calling kluctl.
 	// +optional
@@ -218,6 +252,13 @@ type KluctlDeploymentSpec struct {
 	// +optional
 	Validate bool `json:"validate"`
 
+	// HelmTest enables running the test hooks of all Helm Charts after each deployment, the same way as with
+	// 'kluctl helm-test -t <target>'. The results are added to the validation result, so this requires Validate to
+	// be enabled.
+	// +kubebuilder:default:=false
+	// +optional
+	HelmTest bool `json:"helmTest,omitempty"`
+
 	// Prune enables pruning after deploying.
 	// +kubebuilder:default:=false
 	// +optional
@@ -12,6 +12,16 @@ type ManualRequest struct {
 
 	// +optional
 	OverridesPatch *runtime.RawExtension `json:"overridesPatch,omitempty"`
+
+	// RequestedBy is the user that created the request, as claimed by the requester. It is recorded in audit events,
+	// but not verified.
+	// +optional
+	RequestedBy string `json:"requestedBy,omitempty"`
+
+	// Source is the component that created the request, e.g. "webui" or "cli", as claimed by the requester. It is
+	// recorded in audit events, but not verified.
+	// +optional
+	Source string `json:"source,omitempty"`
 }
 
 type ManualRequestResult struct {
@@ -27,6 +37,11 @@ type ManualRequestResult struct {
 	// +required
 	ReconcileId string `json:"reconcileId"`
 
+	// RequestFieldManager is the field manager that wrote the request annotation, as recorded in the managedFields of
+	// the KluctlDeployment. It is recorded in audit events.
+	// +optional
+	RequestFieldManager string `json:"requestFieldManager,omitempty"`
+
 	// +optional
 	ResultId string `json:"resultId,omitempty"`
 
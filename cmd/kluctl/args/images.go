@@ -27,10 +27,10 @@ func (args *ImageFlags) LoadFixedImagesFromArgs() ([]types.FixedImage, error) {
 		if err != nil {
 			return nil, err
 		}
-		ret.Images = append(ret.Images, *e)
+		ret.Images = append(ret.Images, types.UpdatableFixedImage{FixedImage: *e})
 	}
 
-	return ret.Images, nil
+	return types.ToFixedImages(ret.Images), nil
 }
 
 func buildFixedImageEntryFromArg(arg string) (*types.FixedImage, error) {
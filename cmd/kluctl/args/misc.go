@@ -30,6 +30,16 @@ type HookFlags struct {
 	ReadinessTimeout time.Duration `group:"misc" help:"Maximum time to wait for object readiness. The timeout is meant per-object. Timeouts are in the duration format (1s, 1m, 1h, ...). If not specified, a default timeout of 5m is used." default:"5m"`
 }
 
+type ApplyConcurrencyFlags struct {
+	ApplyConcurrency     int `group:"misc" help:"Maximum number of deployment items to apply in parallel." default:"8"`
+	ApplyItemConcurrency int `group:"misc" help:"Maximum number of objects to apply in parallel inside a single deployment item. The default of 1 applies objects one after another in the order they were rendered. With higher values, Namespaces and CustomResourceDefinitions are still applied first and one after another." default:"1"`
+}
+
+type K8sRateLimitFlags struct {
+	K8sQps   int `group:"misc" help:"Limits the number of requests per second sent to the Kubernetes API server. The limit is shared between all parallel clients. If not specified, each client is limited to 10 requests per second."`
+	K8sBurst int `group:"misc" help:"Maximum burst of requests allowed by --k8s-qps. Defaults to twice the value of --k8s-qps."`
+}
+
 type IgnoreFlags struct {
 	IgnoreTags        bool `group:"misc" help:"Ignores changes in tags when diffing"`
 	IgnoreLabels      bool `group:"misc" help:"Ignores changes in labels when diffing"`
@@ -41,7 +51,7 @@ type AbortOnErrorFlags struct {
 }
 
 type OutputFormatFlags struct {
-	OutputFormat []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text' or 'yaml'. Can be specified multiple times. The actual format for yaml is currently not documented and subject to change."`
+	OutputFormat []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text', 'yaml' or 'json'. Can be specified multiple times. The actual format for yaml and json is currently not documented and subject to change."`
 	NoObfuscate  bool     `group:"misc" help:"Disable obfuscation of sensitive/secret data"`
 	ShortOutput  bool     `group:"misc" help:"When using the 'text' output format (which is the default), only names of changes objects are shown instead of showing all changes."`
 }
@@ -53,3 +63,31 @@ type OutputFlags struct {
 type RenderOutputDirFlags struct {
 	RenderOutputDir string `group:"misc" help:"Specifies the target directory to render the project into. If omitted, a temporary directory is used."`
 }
+
+type SopsDecryptionFlags struct {
+	DecryptionSecret string `group:"misc" help:"Additionally use the keys found in the given secret (in the format <namespace>/<name>). The secret must have the same format as the decryption secrets referenced via 'spec.decryption.secretRef' in KluctlDeployments."`
+	Context          string `group:"misc" help:"Override the context to use when reading the decryption secret."`
+}
+
+type WebuiApiTokenFlags struct {
+	Context             string `group:"misc" help:"Override the context to use."`
+	ControllerNamespace string `group:"misc" help:"The namespace where the controller and the Webui run in." default:"kluctl-system"`
+	SecretName          string `group:"misc" help:"Specify the name of the Secret that stores the API tokens." default:"webui-api-tokens"`
+}
+
+type ResultsFlags struct {
+	CommandResultReadOnlyFlags
+
+	Context string `group:"results" help:"Override the context to use."`
+}
+
+type ResultsFilterFlags struct {
+	Project         string        `group:"misc" help:"Filter results by the project's repository, e.g. 'github.com/my-org/my-repo'."`
+	SubDir          string        `group:"misc" help:"Filter results by the project's sub directory. Only used in combination with --project."`
+	TargetName      string        `group:"misc" help:"Filter results by target name."`
+	ClusterId       string        `group:"misc" help:"Filter results by cluster ID."`
+	Command         string        `group:"misc" help:"Filter command results by command, e.g. 'deploy' or 'prune'. Can not be used with --validate-results."`
+	NewerThan       time.Duration `group:"misc" help:"Only include results that were started within the given duration, e.g. '24h'."`
+	OlderThan       time.Duration `group:"misc" help:"Only include results that were started before the given duration, e.g. '720h'."`
+	ValidateResults bool          `group:"misc" help:"Operate on validate results instead of command results."`
+}
@@ -77,10 +77,14 @@ type CommandResultReadOnlyFlags struct {
 }
 
 type CommandResultWriteFlags struct {
-	WriteCommandResult       bool `group:"results" help:"Enable writing of command results into the cluster. This is enabled by default." default:"true"`
-	ForceWriteCommandResult  bool `group:"results" help:"Force writing of command results, even if the command is run in dry-run mode."`
-	KeepCommandResultsCount  int  `group:"results" help:"Configure how many old command results to keep." default:"5"`
-	KeepValidateResultsCount int  `group:"results" help:"Configure how many old validate results to keep." default:"2"`
+	WriteCommandResult       bool          `group:"results" help:"Enable writing of command results into the cluster. This is enabled by default." default:"true"`
+	ForceWriteCommandResult  bool          `group:"results" help:"Force writing of command results, even if the command is run in dry-run mode."`
+	KeepCommandResultsCount  int           `group:"results" help:"Configure how many old command results to keep." default:"5"`
+	KeepValidateResultsCount int           `group:"results" help:"Configure how many old validate results to keep." default:"2"`
+	KeepCommandResultsAge    time.Duration `group:"results" help:"Configure the maximum age of command results to keep, e.g. '720h'. Older command results are deleted even if --keep-command-results-count is not reached, except for the newest command result of each target. Set to 0 to disable age based retention."`
+	KeepValidateResultsAge   time.Duration `group:"results" help:"Configure the maximum age of validate results to keep, e.g. '720h'. Older validate results are deleted even if --keep-validate-results-count is not reached, except for the newest validate result of each target. Set to 0 to disable age based retention."`
+	MaxResultsSize           string        `group:"results" help:"Configure the maximum total size of all command and validate results inside the command result namespace, e.g. '500Mi'. The oldest results are deleted when the size is exceeded, except for pinned results and the newest results of each target. Leave empty to disable the size limit."`
+	KeepLastSuccessfulDeploy bool          `group:"results" help:"Never delete the newest successful deployment result of each target when cleaning up old command results. This is enabled by default." default:"true"`
 }
 
 type CommandResultFlags struct {
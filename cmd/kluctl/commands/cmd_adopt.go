@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/prompts"
+	"strings"
+)
+
+type adoptCmd struct {
+	args.ProjectFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.ImageFlags
+	args.InclusionFlags
+	args.HelmCredentials
+	args.RegistryCredentials
+	args.YesFlags
+	args.DryRunFlags
+	args.OutputFormatFlags
+	args.RenderOutputDirFlags
+	args.CommandResultFlags
+
+	FieldManager       []string `group:"misc" help:"Transfer ownership of fields owned by the given field manager to kluctl. Can be specified multiple times. Defaults to the field managers used by Helm and kubectl (helm, kubectl-client-side-apply, kubectl-create, kubectl-edit, kubectl-patch and kubectl-replace)."`
+	RemoveHelmMetadata bool     `group:"misc" help:"Remove the Helm release annotations and the 'app.kubernetes.io/managed-by: Helm' label from adopted objects."`
+}
+
+func (cmd *adoptCmd) Help() string {
+	return `This command will fully render the target and then search the cluster for already existing
+objects that match the rendered objects but are not yet managed by the target (the
+discriminator label is missing or different). These objects are then adopted by
+transferring field ownership to kluctl and adding the discriminator label.
+
+A report with all objects to be adopted and their current field managers is shown first.
+Use --dry-run to only show the report and the resulting changes.`
+}
+
+func (cmd *adoptCmd) Run(ctx context.Context) error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		imageFlags:           cmd.ImageFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		helmCredentials:      cmd.HelmCredentials,
+		registryCredentials:  cmd.RegistryCredentials,
+		dryRunArgs:           &cmd.DryRunFlags,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+		commandResultFlags:   &cmd.CommandResultFlags,
+	}
+	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		return cmd.runCmdAdopt(cmdCtx)
+	})
+}
+
+func (cmd *adoptCmd) runCmdAdopt(cmdCtx *commandCtx) error {
+	cmd2 := commands.NewAdoptCommand(cmdCtx.targetCtx)
+	cmd2.FieldManagers = cmd.FieldManager
+	cmd2.RemoveHelmMetadata = cmd.RemoveHelmMetadata
+
+	result := cmd2.Run(func(candidates []commands.AdoptCandidate) error {
+		return cmd.confirmAdoption(cmdCtx.ctx, candidates)
+	})
+	err := outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
+	if err != nil {
+		return err
+	}
+	if len(result.Errors) != 0 {
+		return fmt.Errorf("command failed")
+	}
+	return nil
+}
+
+func (cmd *adoptCmd) confirmAdoption(ctx context.Context, candidates []commands.AdoptCandidate) error {
+	if len(candidates) == 0 {
+		_, _ = getStderr(ctx).WriteString("No objects to adopt found.\n")
+		return nil
+	}
+
+	_, _ = getStderr(ctx).WriteString("The following objects will be adopted:\n")
+	for _, c := range candidates {
+		_, _ = getStderr(ctx).WriteString(fmt.Sprintf("  %s\n", c.Ref.String()))
+		if len(c.FieldManagers) != 0 {
+			_, _ = getStderr(ctx).WriteString(fmt.Sprintf("    field managers: %s\n", strings.Join(c.FieldManagers, ", ")))
+		}
+		if len(c.TransferManagers) != 0 {
+			_, _ = getStderr(ctx).WriteString(fmt.Sprintf("    transferring ownership from: %s\n", strings.Join(c.TransferManagers, ", ")))
+		}
+	}
+	if !cmd.Yes && !cmd.DryRun {
+		if !prompts.AskForConfirmation(ctx, fmt.Sprintf("Do you really want to adopt %d objects?", len(candidates))) {
+			return fmt.Errorf("aborted")
+		}
+	}
+	return nil
+}
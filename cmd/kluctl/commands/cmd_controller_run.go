@@ -6,6 +6,7 @@ import (
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/controllers"
+	controller_metrics "github.com/kluctl/kluctl/v2/pkg/controllers/metrics"
 	ssh_pool "github.com/kluctl/kluctl/v2/pkg/git/ssh-pool"
 	"github.com/kluctl/kluctl/v2/pkg/sourceoverride"
 	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/metrics"
@@ -49,13 +50,21 @@ type controllerRunCmd struct {
 	HealthProbeBindAddress    string `group:"misc" help:"The address the probe endpoint binds to." default:":8081"`
 	SourceOverrideBindAddress string `group:"misc" help:"The address the source override manager endpoint binds to." default:":8082"`
 
+	MetricsObjectLabels string `group:"misc" help:"Configure which labels of affected objects are added to per-object metrics, e.g. validation errors and drifted objects. Can be 'none' (totals per KluctlDeployment only), 'kind' (group and kind), 'namespace' (group, kind and namespace) or 'name' (group, kind, namespace and name). More labels allow more specific alerts but increase the cardinality of the metrics." default:"namespace"`
+
+	StatusEvents bool `group:"misc" help:"Log status and progress output of reconciliations as structured events (the same events as emitted by --status-format=jsonl) instead of plain messages."`
+
 	LeaderElect bool `group:"misc" help:"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager."`
 	Concurrency int  `group:"misc" help:"Configures how many KluctlDeployments can be be reconciled concurrently." default:"4"`
 
 	DefaultServiceAccount string `group:"misc" help:"Default service account used for impersonation."`
 	DryRun                bool   `group:"misc" help:"Run all deployments in dryRun=true mode."`
 
+	HelmAllowExecPostRenderers bool `group:"misc" help:"Allow exec post-renderers configured in helm-chart.yaml files. Exec post-renderers can run arbitrary commands inside the controller, so this should only be enabled when all deployed projects are trusted."`
+
 	args.CommandResultFlags
+
+	KeepAuditEventsCount int `group:"results" help:"Configure how many audit events to keep per KluctlDeployment. Set to 0 to keep all audit events." default:"100"`
 }
 
 func (cmd *controllerRunCmd) Help() string {
@@ -77,6 +86,11 @@ func (cmd *controllerRunCmd) initScheme() {
 func (cmd *controllerRunCmd) Run(ctx context.Context) error {
 	cmd.initScheme()
 
+	objectMetricsLabels, err := controller_metrics.ParseObjectLabels(cmd.MetricsObjectLabels)
+	if err != nil {
+		return err
+	}
+
 	metricsRecorder := metrics.NewRecorder()
 	if cmd.MetricsBindAddress != "0" {
 		metricsRecorder = metrics.NewRecorder()
@@ -172,20 +186,23 @@ func (cmd *controllerRunCmd) Run(ctx context.Context) error {
 	}
 
 	r := controllers.KluctlDeploymentReconciler{
-		ControllerName:        controllerName,
-		ControllerNamespace:   cmd.ControllerNamespace,
-		DefaultServiceAccount: cmd.DefaultServiceAccount,
-		DryRun:                cmd.DryRun,
-		RestConfig:            restConfig,
-		ApiReader:             mgr.GetAPIReader(),
-		Client:                mgr.GetClient(),
-		Scheme:                mgr.GetScheme(),
-		EventRecorder:         eventRecorder,
-		MetricsRecorder:       metricsRecorder,
-		SshPool:               sshPool,
+		ControllerName:             controllerName,
+		ControllerNamespace:        cmd.ControllerNamespace,
+		DefaultServiceAccount:      cmd.DefaultServiceAccount,
+		DryRun:                     cmd.DryRun,
+		ObjectMetricsLabels:        objectMetricsLabels,
+		StatusEvents:               cmd.StatusEvents,
+		HelmAllowExecPostRenderers: cmd.HelmAllowExecPostRenderers,
+		RestConfig:                 restConfig,
+		ApiReader:                  mgr.GetAPIReader(),
+		Client:                     mgr.GetClient(),
+		Scheme:                     mgr.GetScheme(),
+		EventRecorder:              eventRecorder,
+		MetricsRecorder:            metricsRecorder,
+		SshPool:                    sshPool,
 	}
 
-	r.ResultStore, err = buildResultStoreRW(ctx, restConfig, mgr.GetRESTMapper(), &cmd.CommandResultFlags, true)
+	r.ResultStore, err = buildResultStoreRW(ctx, restConfig, mgr.GetRESTMapper(), &cmd.CommandResultFlags, cmd.KeepAuditEventsCount, true)
 	if err != nil {
 		return err
 	}
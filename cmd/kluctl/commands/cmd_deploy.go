@@ -1,13 +1,16 @@
 package commands
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
 	"github.com/kluctl/kluctl/v2/pkg/prompts"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
 )
 
 type deployCmd struct {
@@ -24,12 +27,16 @@ type deployCmd struct {
 	args.ReplaceOnErrorFlags
 	args.AbortOnErrorFlags
 	args.HookFlags
+	args.ApplyConcurrencyFlags
+	args.K8sRateLimitFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
 	args.CommandResultFlags
 
 	DeployExtraFlags
 
+	Interactive bool `group:"misc" short:"i" help:"Show the diff for every new or changed object and ask if it should be applied, skipped or if deployment should be aborted."`
+
 	internal bool
 }
 
@@ -42,10 +49,21 @@ func (cmd *deployCmd) Help() string {
 	return `This command will also output a diff between the initial state and the state after
 deployment. The format of this diff is the same as for the 'diff' command.
 It will also output a list of prunable objects (without actually deleting them).
+
+When --interactive is used, kluctl will ask for every new or changed object if it should be applied or skipped.
+Skipped objects are not applied and are marked as skipped in the command result. --interactive can not be
+combined with --yes or --dry-run.
 `
 }
 
 func (cmd *deployCmd) Run(ctx context.Context) error {
+	if cmd.Interactive && cmd.Yes {
+		return fmt.Errorf("--interactive can not be combined with --yes")
+	}
+	if cmd.Interactive && cmd.DryRun {
+		return fmt.Errorf("--interactive can not be combined with --dry-run")
+	}
+
 	ptArgs := projectTargetCommandArgs{
 		projectFlags:         cmd.ProjectFlags,
 		targetFlags:          cmd.TargetFlags,
@@ -57,6 +75,7 @@ func (cmd *deployCmd) Run(ctx context.Context) error {
 		dryRunArgs:           &cmd.DryRunFlags,
 		renderOutputDirFlags: cmd.RenderOutputDirFlags,
 		commandResultFlags:   &cmd.CommandResultFlags,
+		k8sRateLimitFlags:    cmd.K8sRateLimitFlags,
 		internalDeploy:       cmd.internal,
 	}
 	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
@@ -77,8 +96,10 @@ func (cmd *deployCmd) runCmdDeploy(cmdCtx *commandCtx) error {
 	cmd2.NoWait = cmd.NoWait
 	cmd2.Prune = cmd.Prune
 	cmd2.WaitPrune = !cmd.NoWait
+	cmd2.ApplyConcurrency = cmd.ApplyConcurrency
+	cmd2.ApplyItemConcurrency = cmd.ApplyItemConcurrency
 
-	cb := func(diffResult *result.CommandResult) error {
+	cb := func(diffResult *result.CommandResult) ([]k8s.ObjectRef, error) {
 		return cmd.diffResultCb(cmdCtx, diffResult)
 	}
 	if cmd.Yes || cmd.DryRun {
@@ -96,25 +117,71 @@ func (cmd *deployCmd) runCmdDeploy(cmdCtx *commandCtx) error {
 	return nil
 }
 
-func (cmd *deployCmd) diffResultCb(ctx *commandCtx, diffResult *result.CommandResult) error {
+func (cmd *deployCmd) diffResultCb(ctx *commandCtx, diffResult *result.CommandResult) ([]k8s.ObjectRef, error) {
 	flags := cmd.OutputFormatFlags
 	flags.OutputFormat = nil // use default output format
+	if cmd.Interactive {
+		// the full diff is shown per object later
+		flags.ShortOutput = true
+	}
 
 	err := outputCommandResult(ctx, flags, diffResult, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if cmd.Yes || cmd.DryRun {
-		return nil
+		return nil, nil
 	}
 	if len(diffResult.Errors) != 0 {
 		if !prompts.AskForConfirmation(ctx.ctx, "The diff resulted in errors, do you still want to proceed?") {
-			return fmt.Errorf("aborted")
+			return nil, fmt.Errorf("aborted")
 		}
-	} else {
+	} else if !cmd.Interactive {
 		if !prompts.AskForConfirmation(ctx.ctx, "The diff succeeded, do you want to proceed?") {
-			return fmt.Errorf("aborted")
+			return nil, fmt.Errorf("aborted")
 		}
 	}
-	return nil
+	if cmd.Interactive {
+		return cmd.askForSkippedObjects(ctx, diffResult)
+	}
+	return nil, nil
+}
+
+func (cmd *deployCmd) askForSkippedObjects(ctx *commandCtx, diffResult *result.CommandResult) ([]k8s.ObjectRef, error) {
+	var choices utils.OrderedMap[string, string]
+	choices.Set("a", "Apply")
+	choices.Set("s", "Skip")
+	choices.Set("q", "Quit")
+
+	var skipped []k8s.ObjectRef
+	for _, o := range diffResult.Objects {
+		if o.Hook || o.Rendered == nil || (!o.New && len(o.Changes) == 0) {
+			continue
+		}
+
+		buf := bytes.NewBuffer(nil)
+		buf.WriteString("\n")
+		if o.New {
+			buf.WriteString(fmt.Sprintf("New object %s\n", o.Ref.String()))
+		} else {
+			prettyChanges(buf, o.Ref, o.Changes)
+		}
+		err := outputResult(ctx.ctx, nil, buf.String())
+		if err != nil {
+			return nil, err
+		}
+
+		choice, err := prompts.AskForChoice(ctx.ctx, fmt.Sprintf("What do you want to do with %s?", o.Ref.String()), &choices)
+		if err != nil {
+			return nil, err
+		}
+		switch choice {
+		case "s":
+			// we need the real ref here, as o.Ref might be the diff-name based ref
+			skipped = append(skipped, o.Rendered.GetK8sRef())
+		case "q":
+			return nil, fmt.Errorf("aborted")
+		}
+	}
+	return skipped, nil
 }
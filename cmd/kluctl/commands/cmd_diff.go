@@ -18,6 +18,8 @@ type diffCmd struct {
 	args.ForceApplyFlags
 	args.ReplaceOnErrorFlags
 	args.IgnoreFlags
+	args.ApplyConcurrencyFlags
+	args.K8sRateLimitFlags
 	args.OutputFormatFlags
 	args.RenderOutputDirFlags
 }
@@ -39,6 +41,7 @@ func (cmd *diffCmd) Run(ctx context.Context) error {
 		helmCredentials:      cmd.HelmCredentials,
 		registryCredentials:  cmd.RegistryCredentials,
 		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+		k8sRateLimitFlags:    cmd.K8sRateLimitFlags,
 	}
 	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
 		cmd2 := commands.NewDiffCommand(cmdCtx.targetCtx)
@@ -48,6 +51,8 @@ func (cmd *diffCmd) Run(ctx context.Context) error {
 		cmd2.IgnoreTags = cmd.IgnoreTags
 		cmd2.IgnoreLabels = cmd.IgnoreLabels
 		cmd2.IgnoreAnnotations = cmd.IgnoreAnnotations
+		cmd2.ApplyConcurrency = cmd.ApplyConcurrency
+		cmd2.ApplyItemConcurrency = cmd.ApplyItemConcurrency
 		result := cmd2.Run()
 		err := outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, false)
 		if err != nil {
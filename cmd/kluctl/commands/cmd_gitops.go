@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	json_patch "github.com/evanphx/json-patch/v5"
+	"github.com/google/uuid"
 	"github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/controllers/logs"
@@ -20,6 +21,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
 	flag "github.com/spf13/pflag"
+	authv1 "k8s.io/api/authentication/v1"
 	v12 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -28,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	authenticationv1 "k8s.io/client-go/kubernetes/typed/authentication/v1"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -48,6 +51,7 @@ type gitopsCmd struct {
 	Prune     gitopsPruneCmd     `cmd:"" help:"Trigger a GitOps prune"`
 	Validate  gitopsValidateCmd  `cmd:"" help:"Trigger a GitOps validate"`
 	Logs      gitopsLogsCmd      `cmd:"" help:"Show logs from controller"`
+	Audit     gitopsAuditCmd     `cmd:"" help:"Show audit events"`
 }
 
 type gitopsCmdHelper struct {
@@ -63,6 +67,7 @@ type gitopsCmdHelper struct {
 
 	kds []v1beta1.KluctlDeployment
 
+	clientConfig clientcmd.ClientConfig
 	restConfig   *rest.Config
 	restMapper   meta.RESTMapper
 	client       client.Client
@@ -73,6 +78,8 @@ type gitopsCmdHelper struct {
 
 	resultStore results.ResultStore
 
+	auditUser *string
+
 	logsMutex          sync.Mutex
 	logsBufs           map[logsKey]*logsBuf
 	lastFlushedLogsKey *logsKey
@@ -126,6 +133,7 @@ func (g *gitopsCmdHelper) init(ctx context.Context) error {
 		return err
 	}
 
+	g.clientConfig = clientConfig
 	g.restConfig = restConfig
 	g.restMapper = mapper
 
@@ -431,6 +439,8 @@ func (g *gitopsCmdHelper) patchManualRequest(ctx context.Context, key client.Obj
 
 		mr := v1beta1.ManualRequest{
 			RequestValue: value,
+			RequestedBy:  g.getAuditUser(ctx),
+			Source:       result.AuditSourceCli,
 		}
 		if len(overridePatch) != 0 && !bytes.Equal(overridePatch, []byte("{}")) {
 			mr.OverridesPatch = &runtime.RawExtension{Raw: overridePatch}
@@ -457,6 +467,68 @@ func (g *gitopsCmdHelper) patchManualRequest(ctx context.Context, key client.Obj
 	return nil
 }
 
+// getAuditUser returns the user to record in audit events. It asks the API server via a SelfSubjectReview and falls
+// back to the name of the user configured in the kubeconfig if the API server does not support this.
+func (g *gitopsCmdHelper) getAuditUser(ctx context.Context) string {
+	if g.auditUser != nil {
+		return *g.auditUser
+	}
+
+	user := ""
+	ssr, err := authenticationv1.NewForConfig(g.restConfig)
+	if err == nil {
+		var review *authv1.SelfSubjectReview
+		review, err = ssr.SelfSubjectReviews().Create(ctx, &authv1.SelfSubjectReview{}, metav1.CreateOptions{})
+		if err == nil {
+			user = review.Status.UserInfo.Username
+		}
+	}
+	if user == "" {
+		rawConfig, err := g.clientConfig.RawConfig()
+		if err == nil {
+			currentContext := rawConfig.CurrentContext
+			if g.args.Context != "" {
+				currentContext = g.args.Context
+			}
+			if kc, ok := rawConfig.Contexts[currentContext]; ok {
+				user = kc.AuthInfo
+			}
+		}
+	}
+
+	g.auditUser = &user
+	return user
+}
+
+// writeAuditEvent records an action that was performed directly by the CLI, without involving the controller. Failures
+// are only reported as warnings, as the action itself has already been performed.
+func (g *gitopsCmdHelper) writeAuditEvent(ctx context.Context, kd *v1beta1.KluctlDeployment, action string) {
+	clusterId, err := k8s.GetClusterId(ctx, g.client)
+	if err != nil {
+		status.Warningf(ctx, "Failed to write audit event: %s", err.Error())
+		return
+	}
+
+	e := &result.AuditEvent{
+		Id:     uuid.NewString(),
+		Time:   metav1.Now(),
+		User:   g.getAuditUser(ctx),
+		Source: result.AuditSourceCli,
+		Action: action,
+		KluctlDeployment: result.KluctlDeploymentInfo{
+			Name:      kd.Name,
+			Namespace: kd.Namespace,
+			ClusterId: clusterId,
+		},
+		ProjectKey: kd.Status.ProjectKey,
+		TargetKey:  kd.Status.TargetKey,
+	}
+	err = results.WriteAuditEventSecret(ctx, g.client, g.args.CommandResultNamespace, e)
+	if err != nil {
+		status.Warningf(ctx, "Failed to write audit event: %s", err.Error())
+	}
+}
+
 func (g *gitopsCmdHelper) buildOverridePatch(ctx context.Context, kdIn *v1beta1.KluctlDeployment) ([]byte, error) {
 	cobraCmd := getCobraCommand(ctx)
 	if cobraCmd == nil {
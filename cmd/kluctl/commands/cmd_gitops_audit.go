@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+)
+
+type gitopsAuditCmd struct {
+	args.GitOpsArgs
+	args.OutputFlags
+
+	Limit int  `group:"misc" help:"Limit the number of audit events to show. Set to 0 to show all audit events." default:"20"`
+	All   bool `group:"misc" help:"Show audit events of all deployments in the cluster, including deleted deployments."`
+}
+
+func (cmd *gitopsAuditCmd) Help() string {
+	return `This command outputs a yaml list of audit events for the specified KluctlDeployments, ordered
+from newest to oldest.
+
+Audit events are recorded for manual requests (e.g. deploy or prune requests), suspension, resumption and approvals.
+The recorded user is authenticated by the component that performed the action, e.g. the webui or the kluctl CLI.
+Manual requests are recorded by the controller, which can only record the user claimed by the requester and the field
+manager that wrote the request annotation.
+`
+}
+
+func (cmd *gitopsAuditCmd) Run(ctx context.Context) error {
+	g := gitopsCmdHelper{
+		args:        cmd.GitOpsArgs,
+		noArgsReact: noArgsAutoDetectProject,
+	}
+	if cmd.All {
+		g.noArgsReact = noArgsNoDeployments
+	}
+	err := g.init(ctx)
+	if err != nil {
+		return err
+	}
+
+	clusterId, err := k8s.GetClusterId(ctx, g.client)
+	if err != nil {
+		return err
+	}
+
+	var events []result.AuditEvent
+	if cmd.All {
+		events, err = g.resultStore.ListAuditEvents(results.ListAuditEventsOptions{
+			KluctlDeployment: &result.KluctlDeploymentInfo{
+				ClusterId: clusterId,
+			},
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		for _, kd := range g.kds {
+			l, err := g.resultStore.ListAuditEvents(results.ListAuditEventsOptions{
+				KluctlDeployment: &result.KluctlDeploymentInfo{
+					Name:      kd.Name,
+					Namespace: kd.Namespace,
+					ClusterId: clusterId,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			events = append(events, l...)
+		}
+		results.SortAuditEvents(events)
+	}
+
+	if cmd.Limit > 0 && len(events) > cmd.Limit {
+		events = events[:cmd.Limit]
+	}
+	if events == nil {
+		events = []result.AuditEvent{}
+	}
+
+	return outputYamlResult(ctx, cmd.Output, events, false)
+}
@@ -79,7 +79,7 @@ func (cmd *gitopsDiffCmd) cleanupDiffResult(ctx context.Context, g *gitopsCmdHel
 			WriteCommandResult: true,
 		},
 	}
-	rwRS, err := buildResultStoreRW(ctx, g.restConfig, g.restMapper, &flags, false)
+	rwRS, err := buildResultStoreRW(ctx, g.restConfig, g.restMapper, &flags, 0, false)
 	if err != nil {
 		return err
 	}
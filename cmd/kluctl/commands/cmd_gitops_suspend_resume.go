@@ -5,6 +5,7 @@ import (
 	"github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"time"
 )
@@ -57,6 +58,11 @@ func (cmd *GitopsSuspendCmd) Run(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		if cmd.forResume {
+			g.writeAuditEvent(ctx, patchedKd, result.AuditActionResume)
+		} else {
+			g.writeAuditEvent(ctx, patchedKd, result.AuditActionSuspend)
+		}
 		err = func() error {
 			// modifying the spec causes a reconciliation loop and we should really wait for it to finish before we consider
 			// suspension to be done (otherwise you'd be surprised for some last-second deployments...)
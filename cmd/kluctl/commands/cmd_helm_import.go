@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/prompts"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"strings"
+)
+
+type helmImportCmd struct {
+	args.ProjectFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.ImageFlags
+	args.InclusionFlags
+	args.HelmCredentials
+	args.RegistryCredentials
+	args.YesFlags
+	args.DryRunFlags
+	args.OutputFormatFlags
+	args.RenderOutputDirFlags
+	args.CommandResultFlags
+}
+
+func (cmd *helmImportCmd) Help() string {
+	return `This command will fully render the target and then search the cluster for Helm releases
+(the sh.helm.release.v1.* Secrets) that were installed via the Helm CLI and match a
+helm-chart.yaml of the project. The chart name, chart version and values of the installed
+releases are compared with the project and all differences are reported. This includes
+objects of the installed releases that are not rendered by the project anymore, e.g. because
+they were removed in a newer chart version.
+
+After confirmation, the Helm release Secrets are deleted so that the releases are not
+managed by Helm anymore. This is only done for releases where all objects have already
+been adopted by kluctl, e.g. via 'kluctl adopt', and where no object of the installed release
+would be orphaned. Such objects must be deleted or added to the project before importing.`
+}
+
+func (cmd *helmImportCmd) Run(ctx context.Context) error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		imageFlags:           cmd.ImageFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		helmCredentials:      cmd.HelmCredentials,
+		registryCredentials:  cmd.RegistryCredentials,
+		dryRunArgs:           &cmd.DryRunFlags,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+		commandResultFlags:   &cmd.CommandResultFlags,
+	}
+	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		return cmd.runCmdHelmImport(cmdCtx)
+	})
+}
+
+func (cmd *helmImportCmd) runCmdHelmImport(cmdCtx *commandCtx) error {
+	cmd2 := commands.NewHelmImportCommand(cmdCtx.targetCtx)
+	result := cmd2.Run(func(releases []*commands.HelmImportRelease) error {
+		return cmd.confirmImport(cmdCtx.ctx, releases)
+	})
+	err := outputCommandResult(cmdCtx, cmd.OutputFormatFlags, result, !cmd.DryRun || cmd.ForceWriteCommandResult)
+	if err != nil {
+		return err
+	}
+	if len(result.Errors) != 0 {
+		return fmt.Errorf("command failed")
+	}
+	return nil
+}
+
+func (cmd *helmImportCmd) confirmImport(ctx context.Context, releases []*commands.HelmImportRelease) error {
+	stderr := getStderr(ctx)
+
+	if len(releases) == 0 {
+		_, _ = stderr.WriteString("No installed Helm releases found.\n")
+		return nil
+	}
+
+	var secretRefs []k8s.ObjectRef
+	for _, x := range releases {
+		_, _ = stderr.WriteString(fmt.Sprintf("Helm release %s in namespace %s (%s):\n", x.ReleaseName, x.Namespace, x.DeploymentItemDir))
+		if x.InstalledChartName != x.ChartName {
+			_, _ = stderr.WriteString(fmt.Sprintf("  chart name differs: installed=%s, project=%s\n", x.InstalledChartName, x.ChartName))
+		}
+		if x.InstalledChartVersion != x.ChartVersion {
+			_, _ = stderr.WriteString(fmt.Sprintf("  chart version differs: installed=%s, project=%s\n", x.InstalledChartVersion, x.ChartVersion))
+		}
+		if len(x.ValueChanges) != 0 {
+			_, _ = stderr.WriteString("  values differ:\n")
+			for _, c := range x.ValueChanges {
+				_, _ = stderr.WriteString(fmt.Sprintf("    %s\n", c.JsonPath))
+				for _, l := range strings.Split(strings.TrimRight(c.UnifiedDiff, "\n"), "\n") {
+					_, _ = stderr.WriteString(fmt.Sprintf("      %s\n", l))
+				}
+			}
+		}
+		if len(x.ManifestOnlyObjects) != 0 {
+			_, _ = stderr.WriteString(fmt.Sprintf("  %d objects of the installed release are not rendered by the project and would be orphaned, delete them or add them to the project first:\n", len(x.ManifestOnlyObjects)))
+			for _, ref := range x.ManifestOnlyObjects {
+				_, _ = stderr.WriteString(fmt.Sprintf("    %s\n", ref.String()))
+			}
+		}
+		if !x.HasDifferences() {
+			_, _ = stderr.WriteString("  no differences found\n")
+		}
+		if len(x.NotAdoptedObjects) != 0 {
+			_, _ = stderr.WriteString(fmt.Sprintf("  %d objects are not adopted yet, run 'kluctl adopt' first:\n", len(x.NotAdoptedObjects)))
+			for _, ref := range x.NotAdoptedObjects {
+				_, _ = stderr.WriteString(fmt.Sprintf("    %s\n", ref.String()))
+			}
+		}
+		if !x.CanImport() {
+			continue
+		}
+		secretRefs = append(secretRefs, x.SecretRefs...)
+	}
+
+	if len(secretRefs) == 0 {
+		return nil
+	}
+
+	_, _ = stderr.WriteString("The following Helm release secrets will be deleted:\n")
+	for _, ref := range secretRefs {
+		_, _ = stderr.WriteString(fmt.Sprintf("  %s\n", ref.String()))
+	}
+	if !cmd.Yes && !cmd.DryRun {
+		if !prompts.AskForConfirmation(ctx, fmt.Sprintf("Do you really want to delete %d Helm release secrets?", len(secretRefs))) {
+			return fmt.Errorf("aborted")
+		}
+	}
+	return nil
+}
@@ -8,11 +8,13 @@ import (
 	helm_auth "github.com/kluctl/kluctl/v2/pkg/helm/auth"
 	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"reflect"
 )
 
 type helmPullCmd struct {
@@ -50,16 +52,16 @@ func (cmd *helmPullCmd) Run(ctx context.Context) error {
 		ociAuthProvider.RegisterAuthProvider(x, false)
 	}
 
-	_, err = doHelmPull(ctx, projectDir, helmAuthProvider, ociAuthProvider, false, true)
+	_, err = doHelmPull(ctx, projectDir, helmAuthProvider, ociAuthProvider, cmd.HelmRequireVerification, false, true)
 	return err
 }
 
-func doHelmPull(ctx context.Context, projectDir string, helmAuthProvider helm_auth.HelmAuthProvider, ociAuthProvider auth_provider.OciAuthProvider, dryRun bool, force bool) (int, error) {
+func doHelmPull(ctx context.Context, projectDir string, helmAuthProvider helm_auth.HelmAuthProvider, ociAuthProvider auth_provider.OciAuthProvider, requireVerification bool, dryRun bool, force bool) (int, error) {
 	actions := 0
 
 	baseChartsDir := filepath.Join(projectDir, ".helm-charts")
 
-	releases, charts, err := loadHelmReleases(ctx, projectDir, baseChartsDir, helmAuthProvider, ociAuthProvider)
+	releases, charts, err := loadHelmReleases(ctx, projectDir, baseChartsDir, helmAuthProvider, ociAuthProvider, requireVerification)
 	if err != nil {
 		return actions, err
 	}
@@ -108,7 +110,14 @@ func doHelmPull(ctx context.Context, projectDir string, helmAuthProvider helm_au
 			version := version
 
 			if yaml.Exists(filepath.Join(chartsDir, version, "Chart.yaml")) && !force {
-				continue
+				pc, err := chart.GetPulledChart(baseChartsDir, version)
+				if err != nil {
+					return actions, err
+				}
+				// re-pull charts that were pulled before verification got configured
+				if pc.CheckVerification() == nil {
+					continue
+				}
 			}
 
 			actions++
@@ -140,10 +149,33 @@ func doHelmPull(ctx context.Context, projectDir string, helmAuthProvider helm_au
 	return actions, nil
 }
 
-func loadHelmReleases(ctx context.Context, projectDir string, baseChartsDir string, helmAuthProvider helm_auth.HelmAuthProvider, ociAuthProvider auth_provider.OciAuthProvider) ([]*helm.Release, []*helm.Chart, error) {
+// isHelmVerificationRequired checks if either the cli arguments or the helm.requireVerification setting from
+// .kluctl.yaml require Helm Charts to be verified
+func isHelmVerificationRequired(projectDir string, requireVerification bool) (bool, error) {
+	if requireVerification {
+		return true, nil
+	}
+	configPath := yaml.FixPathExt(filepath.Join(projectDir, ".kluctl.yml"))
+	if !utils.IsFile(configPath) {
+		return false, nil
+	}
+	var config types.KluctlProject
+	err := yaml.ReadYamlFile(configPath, &config)
+	if err != nil {
+		return false, err
+	}
+	return config.Helm != nil && config.Helm.RequireVerification, nil
+}
+
+func loadHelmReleases(ctx context.Context, projectDir string, baseChartsDir string, helmAuthProvider helm_auth.HelmAuthProvider, ociAuthProvider auth_provider.OciAuthProvider, requireVerification bool) ([]*helm.Release, []*helm.Chart, error) {
+	requireVerification, err := isHelmVerificationRequired(projectDir, requireVerification)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var releases []*helm.Release
 	chartsMap := make(map[string]*helm.Chart)
-	err := filepath.WalkDir(projectDir, func(p string, d fs.DirEntry, err error) error {
+	err = filepath.WalkDir(projectDir, func(p string, d fs.DirEntry, err error) error {
 		fname := filepath.Base(p)
 		if fname != "helm-chart.yml" && fname != "helm-chart.yaml" {
 			return nil
@@ -154,7 +186,7 @@ func loadHelmReleases(ctx context.Context, projectDir string, baseChartsDir stri
 			return err
 		}
 
-		hr, err := helm.NewRelease(ctx, projectDir, relDir, p, baseChartsDir, helmAuthProvider, ociAuthProvider)
+		hr, err := helm.NewRelease(ctx, projectDir, relDir, p, baseChartsDir, helmAuthProvider, ociAuthProvider, requireVerification)
 		if err != nil {
 			return err
 		}
@@ -169,6 +201,9 @@ func loadHelmReleases(ctx context.Context, projectDir string, baseChartsDir stri
 		if x, ok := chartsMap[key]; !ok {
 			chartsMap[key] = chart
 		} else {
+			if !reflect.DeepEqual(x.GetVerify(), chart.GetVerify()) {
+				return fmt.Errorf("Helm Chart %s is used with different verify configurations, which is not allowed", key)
+			}
 			hr.Chart = x
 		}
 		return nil
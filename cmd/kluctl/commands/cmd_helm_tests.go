@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/deployment/commands"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"time"
+)
+
+type helmTestCmd struct {
+	args.ProjectFlags
+	args.TargetFlags
+	args.ArgsFlags
+	args.InclusionFlags
+	args.HelmCredentials
+	args.RegistryCredentials
+	args.OutputFlags
+	args.RenderOutputDirFlags
+
+	TestTimeout time.Duration `group:"misc" help:"Maximum time to wait for a single test to finish" default:"5m"`
+	NoCleanup   bool          `group:"misc" help:"Don't delete the test Pods and Jobs after the tests have finished. Useful for debugging."`
+}
+
+func (cmd *helmTestCmd) Help() string {
+	return `This renders the target and then runs the test hooks (objects annotated with
+'helm.sh/hook: test') of all Helm Charts that are part of the target, which is the
+equivalent of 'helm test'. Pods and Jobs are waited for until they have finished, after
+which their logs are collected and the objects are deleted.
+
+The command fails if any test fails.`
+}
+
+func (cmd *helmTestCmd) Run(ctx context.Context) error {
+	ptArgs := projectTargetCommandArgs{
+		projectFlags:         cmd.ProjectFlags,
+		targetFlags:          cmd.TargetFlags,
+		argsFlags:            cmd.ArgsFlags,
+		inclusionFlags:       cmd.InclusionFlags,
+		helmCredentials:      cmd.HelmCredentials,
+		registryCredentials:  cmd.RegistryCredentials,
+		renderOutputDirFlags: cmd.RenderOutputDirFlags,
+	}
+
+	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
+		cmd2 := commands.NewHelmTestCommand(cmdCtx.targetCtx)
+		cmd2.Timeout = cmd.TestTimeout
+		cmd2.NoCleanup = cmd.NoCleanup
+
+		result := cmd2.Run(cmdCtx.ctx)
+		err := outputValidateResult(cmdCtx, cmd.Output, result)
+		if err != nil {
+			return err
+		}
+
+		if len(result.Errors) != 0 || !result.Ready {
+			return fmt.Errorf("Helm tests failed")
+		}
+		status.Info(cmdCtx.ctx, "Helm tests succeeded")
+		return nil
+	})
+}
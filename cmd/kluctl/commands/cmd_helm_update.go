@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/format/index"
@@ -29,10 +30,33 @@ type helmUpdateCmd struct {
 	Commit  bool `group:"misc" help:"Create a git commit for every updated chart"`
 
 	Interactive bool `group:"misc" short:"i" help:"Ask for every Helm Chart if it should be upgraded."`
+
+	CheckValues        bool `group:"misc" help:"Validate helm-values.yaml against the values.schema.json of new chart versions and report removed and changed default values. This requires pulling the old and new chart versions."`
+	RequireValidValues bool `group:"misc" help:"Refuse to upgrade Helm Charts when helm-values.yaml does not validate against the values.schema.json of the new chart version. Implies --check-values."`
+
+	PushBranches bool   `group:"misc" help:"Create a dedicated branch and commit for every updated chart and push it to the git remote. Implies --upgrade and --commit."`
+	BranchPrefix string `group:"misc" help:"Prefix for the branches created via --push-branches. The chart name and new version are appended to it." default:"kluctl-helm-update/"`
+	GitRemote    string `group:"misc" help:"The git remote to push branches to." default:"origin"`
+
+	CreatePr     bool   `group:"misc" help:"Create a pull request for every pushed branch. Requires --push-branches."`
+	PrProvider   string `group:"misc" help:"The API flavor to use for pull requests. Can be 'github' or 'gitlab'." default:"github"`
+	PrApiUrl     string `group:"misc" help:"The base URL of the pull request API. Defaults to https://api.github.com or https://gitlab.com/api/v4, depending on --pr-provider."`
+	PrRepo       string `group:"misc" help:"The repository in which pull requests are created, e.g. 'my-org/my-repo'. Defaults to the path of the git remote URL."`
+	PrBaseBranch string `group:"misc" help:"The branch that pull requests should target. Defaults to the currently checked out branch."`
+	PrToken      string `group:"misc" help:"The token used to authenticate against the pull request API."`
 }
 
 func (cmd *helmUpdateCmd) Help() string {
-	return `Optionally performs the actual upgrade and/or add a commit to version control.`
+	return `Optionally performs the actual upgrade and/or add a commit to version control.
+
+With --check-values, the helm-values.yaml of every Helm Chart with a new version available is validated
+against the values.schema.json of the new version. Values that do not exist in the default values of the
+new version anymore are reported, together with a diff of the default values between both versions.
+Values that contain templates are not validated, as they can't be rendered without a target.
+
+With --push-branches, every chart upgrade is committed into a dedicated branch which is then pushed
+to the git remote. With --create-pr, a pull request is created (or updated) for every pushed branch,
+containing a diff of the rendered objects between the old and new chart versions.`
 }
 
 func (cmd *helmUpdateCmd) Run(ctx context.Context) error {
@@ -41,6 +65,14 @@ func (cmd *helmUpdateCmd) Run(ctx context.Context) error {
 		return err
 	}
 
+	if cmd.CreatePr && !cmd.PushBranches {
+		return fmt.Errorf("--create-pr can only be used together with --push-branches")
+	}
+	if cmd.PushBranches {
+		cmd.Upgrade = true
+		cmd.Commit = true
+	}
+
 	if !yaml.Exists(filepath.Join(projectDir, ".kluctl.yaml")) {
 		return fmt.Errorf("helm-update can only be used on the root of a Kluctl project that must have a .kluctl.yaml file")
 	}
@@ -84,13 +116,16 @@ func (cmd *helmUpdateCmd) Run(ctx context.Context) error {
 
 	g := utils.NewGoHelper(ctx, 8)
 
-	releases, charts, err := loadHelmReleases(ctx, projectDir, baseChartsDir, helmAuthProvider, ociAuthProvider)
+	releases, charts, err := loadHelmReleases(ctx, projectDir, baseChartsDir, helmAuthProvider, ociAuthProvider, cmd.HelmRequireVerification)
 	if err != nil {
 		return err
 	}
+	for _, hr := range releases {
+		hr.AllowExecPostRenderer = cmd.HelmAllowExecPostRenderers
+	}
 
 	if cmd.Commit {
-		actions, err := doHelmPull(ctx, projectDir, helmAuthProvider, ociAuthProvider, true, false)
+		actions, err := doHelmPull(ctx, projectDir, helmAuthProvider, ociAuthProvider, cmd.HelmRequireVerification, true, false)
 		if err != nil {
 			return err
 		}
@@ -150,6 +185,7 @@ func (cmd *helmUpdateCmd) Run(ctx context.Context) error {
 	}
 
 	upgrades := map[helmUpgradeKey][]*helm.Release{}
+	invalidValues := false
 
 	for _, hr := range releases {
 		cd, err := hr.Chart.BuildPulledChartDir(baseChartsDir, "")
@@ -177,10 +213,21 @@ func (cmd *helmUpdateCmd) Run(ctx context.Context) error {
 
 		status.Infof(ctx, "%s: Chart %s has new version %s available", relDir, hr.Chart.GetChartName(), latestVersion)
 
+		valuesValid := true
+		if cmd.CheckValues || cmd.RequireValidValues {
+			valuesValid = cmd.checkValues(ctx, relDir, hr, latestVersion)
+		}
+
 		if !cmd.Upgrade {
 			continue
 		}
 
+		if !valuesValid && cmd.RequireValidValues {
+			status.Warningf(ctx, "%s: Skipped upgrade to version %s due to invalid values", relDir, latestVersion)
+			invalidValues = true
+			continue
+		}
+
 		if cmd.Interactive {
 			if !prompts.AskForConfirmation(ctx, fmt.Sprintf("%s: Do you want to upgrade Chart %s to version %s?",
 				relDir, hr.Chart.GetChartName(), latestVersion)) {
@@ -188,32 +235,97 @@ func (cmd *helmUpdateCmd) Run(ctx context.Context) error {
 			}
 		}
 
-		oldVersion := hr.Config.ChartVersion
+		k := helmUpgradeKey{
+			chartDir:   cd,
+			oldVersion: hr.Config.ChartVersion,
+			newVersion: latestVersion,
+		}
+		upgrades[k] = append(upgrades[k], hr)
+
+		if cmd.PushBranches {
+			// the new version is written later in the dedicated branch
+			continue
+		}
+
 		hr.Config.ChartVersion = latestVersion
 		err = hr.Save()
 		if err != nil {
 			return err
 		}
 		status.Infof(ctx, "%s: Updated Chart version to %s", relDir, latestVersion)
-
-		k := helmUpgradeKey{
-			chartDir:   cd,
-			oldVersion: oldVersion,
-			newVersion: latestVersion,
-		}
-		upgrades[k] = append(upgrades[k], hr)
 	}
 
-	for k, hrs := range upgrades {
-		err = cmd.pullAndCommit(ctx, projectDir, baseChartsDir, gitRootPath, hrs, k.oldVersion, helmAuthProvider, ociAuthProvider)
+	if cmd.PushBranches {
+		err = cmd.pushBranches(ctx, projectDir, baseChartsDir, gitRootPath, upgrades, helmAuthProvider, ociAuthProvider)
 		if err != nil {
 			return err
 		}
+	} else {
+		for k, hrs := range upgrades {
+			err = cmd.pullAndCommit(ctx, projectDir, baseChartsDir, gitRootPath, hrs, k.oldVersion, helmAuthProvider, ociAuthProvider)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if invalidValues {
+		return fmt.Errorf("some Helm Charts were not upgraded due to invalid values")
 	}
 
 	return nil
 }
 
+// checkValues validates the helm-values.yaml of the release against the new chart version and reports removed
+// values and changes to the chart's default values. It returns false if validation failed.
+func (cmd *helmUpdateCmd) checkValues(ctx context.Context, relDir string, hr *helm.Release, newVersion string) bool {
+	oldPc, err := hr.Chart.PullCached(ctx, hr.Config.ChartVersion)
+	if err != nil {
+		status.Warningf(ctx, "%s: Failed to check helm-values.yaml: %s", relDir, err.Error())
+		return false
+	}
+
+	s := status.Startf(ctx, "%s: Downloading Chart with version %s into cache", hr.Chart.GetChartName(), newVersion)
+	newPc, err := hr.Chart.PullCached(ctx, newVersion)
+	if err != nil {
+		s.FailedWithMessagef("%s: %s", hr.Chart.GetChartName(), err.Error())
+		return false
+	}
+	s.Success()
+
+	r, err := hr.CheckValues(ctx, oldPc, newPc)
+	if errors.Is(err, helm.ErrTemplatedValues) {
+		status.Warningf(ctx, "%s: Skipped check of helm-values.yaml: %s", relDir, err.Error())
+		return true
+	}
+	if err != nil {
+		status.Warningf(ctx, "%s: Failed to check helm-values.yaml: %s", relDir, err.Error())
+		return false
+	}
+
+	if len(r.TemplatedKeys) != 0 {
+		status.Infof(ctx, "%s: Skipped schema validation of templated values: %s", relDir, strings.Join(r.TemplatedKeys, ", "))
+	}
+	if r.SchemaError != nil {
+		status.Warningf(ctx, "%s: helm-values.yaml does not validate against the values schema of version %s: %s", relDir, newVersion, r.SchemaError.Error())
+	}
+	if len(r.RemovedKeys) != 0 {
+		status.Warningf(ctx, "%s: helm-values.yaml contains values that were removed from the default values of version %s: %s", relDir, newVersion, strings.Join(r.RemovedKeys, ", "))
+	}
+	if len(r.DefaultValuesChanges) != 0 {
+		stderr := getStderr(ctx)
+		_, _ = stderr.WriteString(fmt.Sprintf("%s: Default values changed from version %s to %s:\n", relDir, hr.Config.ChartVersion, newVersion))
+		for _, c := range r.DefaultValuesChanges {
+			_, _ = stderr.WriteString(fmt.Sprintf("  %s\n", c.JsonPath))
+			for _, l := range strings.Split(strings.TrimRight(c.UnifiedDiff, "\n"), "\n") {
+				_, _ = stderr.WriteString(fmt.Sprintf("    %s\n", l))
+			}
+		}
+	}
+
+	return r.SchemaError == nil
+}
+
 func (cmd *helmUpdateCmd) collectFiles(root string, dir string, m map[string]os.FileInfo) error {
 	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
 		if d == nil || d.IsDir() {
@@ -280,7 +392,7 @@ func (cmd *helmUpdateCmd) pullAndCommit(ctx context.Context, projectDir string,
 		}
 	}
 
-	_, err = doHelmPull(ctx, projectDir, helmAuthProvider, ociAuthProvider, false, false)
+	_, err = doHelmPull(ctx, projectDir, helmAuthProvider, ociAuthProvider, cmd.HelmRequireVerification, false, false)
 	if err != nil {
 		return doError(err)
 	}
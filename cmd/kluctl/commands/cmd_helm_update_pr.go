@@ -0,0 +1,310 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/git/auth"
+	"github.com/kluctl/kluctl/v2/pkg/git/messages"
+	"github.com/kluctl/kluctl/v2/pkg/git/pullrequest"
+	"github.com/kluctl/kluctl/v2/pkg/helm"
+	helm_auth "github.com/kluctl/kluctl/v2/pkg/helm/auth"
+	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
+	"github.com/kluctl/kluctl/v2/pkg/prompts"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// GitHub limits pull request bodies to 65536 characters
+const maxPrBodyLen = 60000
+
+func (cmd *helmUpdateCmd) pushBranches(ctx context.Context, projectDir string, baseChartsDir string, gitRootPath string, upgrades map[helmUpgradeKey][]*helm.Release, helmAuthProvider helm_auth.HelmAuthProvider, ociAuthProvider *auth_provider.OciAuthProviders) error {
+	r, err := git.PlainOpen(gitRootPath)
+	if err != nil {
+		return err
+	}
+	head, err := r.Head()
+	if err != nil {
+		return err
+	}
+	if !head.Name().IsBranch() {
+		return fmt.Errorf("--push-branches can only be used when a branch is checked out")
+	}
+
+	remote, err := r.Remote(cmd.GitRemote)
+	if err != nil {
+		return fmt.Errorf("failed to get git remote %s: %w", cmd.GitRemote, err)
+	}
+	if len(remote.Config().URLs) == 0 {
+		return fmt.Errorf("git remote %s has no URL", cmd.GitRemote)
+	}
+	remoteUrl, err := types.ParseGitUrl(remote.Config().URLs[0])
+	if err != nil {
+		return err
+	}
+
+	messageCallbacks := &messages.MessageCallbacks{
+		WarningFn:            func(s string) { status.Warning(ctx, s) },
+		TraceFn:              func(s string) { status.Trace(ctx, s) },
+		AskForPasswordFn:     func(s string) (string, error) { return prompts.AskForPassword(ctx, s) },
+		AskForConfirmationFn: func(s string) bool { return prompts.AskForConfirmation(ctx, s) },
+	}
+	gitAuth, err := auth.NewDefaultAuthProviders("KLUCTL_GIT", messageCallbacks).BuildAuth(ctx, *remoteUrl)
+	if err != nil {
+		return err
+	}
+
+	var prProvider pullrequest.Provider
+	if cmd.CreatePr {
+		repo := cmd.PrRepo
+		if repo == "" {
+			repo = strings.TrimSuffix(strings.TrimPrefix(remoteUrl.Path, "/"), ".git")
+		}
+		prProvider, err = pullrequest.NewProvider(cmd.PrProvider, cmd.PrApiUrl, repo, cmd.PrToken)
+		if err != nil {
+			return err
+		}
+	}
+	targetBranch := cmd.PrBaseBranch
+	if targetBranch == "" {
+		targetBranch = head.Name().Short()
+	}
+
+	wt, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := wt.Checkout(&git.CheckoutOptions{Branch: head.Name(), Force: true})
+		if err != nil {
+			status.Warningf(ctx, "Failed to checkout %s: %s", head.Name().Short(), err.Error())
+		}
+	}()
+
+	keys := make([]helmUpgradeKey, 0, len(upgrades))
+	for k := range upgrades {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].chartDir != keys[j].chartDir {
+			return keys[i].chartDir < keys[j].chartDir
+		}
+		return keys[i].oldVersion < keys[j].oldVersion
+	})
+
+	for _, k := range keys {
+		hrs := upgrades[k]
+		chart := hrs[0].Chart
+		branchName := fmt.Sprintf("%s%s-%s", cmd.BranchPrefix, chart.GetChartName(), k.newVersion)
+		refName := plumbing.NewBranchReferenceName(branchName)
+
+		// re-create the branch in case it already exists from a previous run
+		err = checkExistingUpdateBranch(ctx, r, refName, head, cmd.GitRemote)
+		if err != nil {
+			return err
+		}
+		err = r.Storer.RemoveReference(refName)
+		if err != nil {
+			return fmt.Errorf("failed to remove branch %s: %w", branchName, err)
+		}
+		err = wt.Checkout(&git.CheckoutOptions{Hash: head.Hash(), Branch: refName, Create: true, Force: true})
+		if err != nil {
+			return fmt.Errorf("failed to create branch %s: %w", branchName, err)
+		}
+
+		for _, hr := range hrs {
+			hr.Config.ChartVersion = k.newVersion
+			err = hr.Save()
+			if err != nil {
+				return err
+			}
+		}
+
+		err = cmd.pullAndCommit(ctx, projectDir, baseChartsDir, gitRootPath, hrs, k.oldVersion, helmAuthProvider, ociAuthProvider)
+		if err != nil {
+			return err
+		}
+
+		s := status.Startf(ctx, "Pushing branch %s", branchName)
+		pushOpts := &git.PushOptions{
+			RemoteName: cmd.GitRemote,
+			RefSpecs:   []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%s", refName, refName))},
+			Auth:       gitAuth.AuthMethod,
+			CABundle:   gitAuth.CABundle,
+		}
+		if _, err := r.Reference(plumbing.NewRemoteReferenceName(cmd.GitRemote, branchName), true); err == nil {
+			// only overwrite the remote branch if nobody pushed to it since we've last seen it
+			pushOpts.ForceWithLease = &git.ForceWithLease{}
+		}
+		err = r.PushContext(ctx, pushOpts)
+		if err != nil && err != git.NoErrAlreadyUpToDate {
+			s.FailedWithMessagef("Failed to push branch %s: %s", branchName, err.Error())
+			return err
+		}
+		s.Success()
+
+		if prProvider == nil {
+			continue
+		}
+
+		s = status.Startf(ctx, "Creating pull request for branch %s", branchName)
+		prUrl, err := prProvider.CreateOrUpdate(ctx, pullrequest.PullRequest{
+			Title:        fmt.Sprintf("Update helm chart %s to version %s", chart.GetChartName(), k.newVersion),
+			Body:         cmd.buildPrBody(ctx, projectDir, hrs, k),
+			SourceBranch: branchName,
+			TargetBranch: targetBranch,
+		})
+		if err != nil {
+			s.FailedWithMessagef("Failed to create pull request for branch %s: %s", branchName, err.Error())
+			return err
+		}
+		s.UpdateAndInfoFallbackf("Created pull request %s", prUrl)
+		s.Success()
+	}
+	return nil
+}
+
+func (cmd *helmUpdateCmd) buildPrBody(ctx context.Context, projectDir string, hrs []*helm.Release, k helmUpgradeKey) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("This updates the Helm Chart `%s` from version `%s` to `%s`.\n\n", hrs[0].Chart.GetChartName(), k.oldVersion, k.newVersion))
+	b.WriteString("The following diffs were rendered from the raw `helm-values.yaml`, without templating and decryption.\n\n")
+
+	for _, hr := range hrs {
+		relDir, err := filepath.Rel(projectDir, filepath.Dir(hr.ConfigFile))
+		if err != nil {
+			relDir = hr.ConfigFile
+		}
+		b.WriteString(fmt.Sprintf("### %s\n\n", relDir))
+
+		d, err := cmd.buildRenderedDiff(ctx, hr, k)
+		if err != nil {
+			b.WriteString(fmt.Sprintf("Failed to render the object diff: %s\n\n", err.Error()))
+			continue
+		}
+		b.WriteString(d)
+	}
+
+	ret := b.String()
+	if len(ret) > maxPrBodyLen {
+		ret = utils.TruncateUtf8(ret, maxPrBodyLen) + "\n\n...truncated...\n"
+	}
+	return ret
+}
+
+// checkExistingUpdateBranch verifies that an already existing branch can be re-created without losing commits. This
+// is the case if the branch is fully merged into HEAD or if it was already pushed to the remote, e.g. by a previous run.
+func checkExistingUpdateBranch(ctx context.Context, r *git.Repository, refName plumbing.ReferenceName, head *plumbing.Reference, remoteName string) error {
+	ref, err := r.Reference(refName, true)
+	if err == plumbing.ErrReferenceNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	branchCommit, err := r.CommitObject(ref.Hash())
+	if err != nil {
+		return err
+	}
+	headCommit, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return err
+	}
+	isAncestor, err := branchCommit.IsAncestor(headCommit)
+	if err != nil {
+		return err
+	}
+	if isAncestor {
+		return nil
+	}
+
+	remoteRef, err := r.Reference(plumbing.NewRemoteReferenceName(remoteName, refName.Short()), true)
+	if err == nil && remoteRef.Hash() == ref.Hash() {
+		status.Warningf(ctx, "Re-creating branch %s, which already exists from a previous run", refName.Short())
+		return nil
+	}
+	return fmt.Errorf("branch %s already exists and contains commits that were not pushed to %s, please delete or rename it", refName.Short(), remoteName)
+}
+
+// buildRenderedDiff renders the release with the old and new chart versions and returns a markdown formatted diff
+// of the rendered objects
+func (cmd *helmUpdateCmd) buildRenderedDiff(ctx context.Context, hr *helm.Release, k helmUpgradeKey) (string, error) {
+	render := func(version string) (map[k8s2.ObjectRef]*uo.UnstructuredObject, error) {
+		pc, err := hr.Chart.PullCached(ctx, version)
+		if err != nil {
+			return nil, err
+		}
+		objects, err := hr.RenderChart(ctx, pc, nil, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		ret := map[k8s2.ObjectRef]*uo.UnstructuredObject{}
+		for _, o := range objects {
+			ret[o.GetK8sRef()] = o
+		}
+		return ret, nil
+	}
+
+	oldObjects, err := render(k.oldVersion)
+	if err != nil {
+		return "", err
+	}
+	newObjects, err := render(k.newVersion)
+	if err != nil {
+		return "", err
+	}
+
+	var refs []k8s2.ObjectRef
+	for ref := range oldObjects {
+		refs = append(refs, ref)
+	}
+	for ref := range newObjects {
+		if _, ok := oldObjects[ref]; !ok {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].String() < refs[j].String()
+	})
+
+	var b strings.Builder
+	for _, ref := range refs {
+		oldObject, newObject := oldObjects[ref], newObjects[ref]
+		if oldObject == nil {
+			b.WriteString(fmt.Sprintf("Added `%s`\n\n", ref.String()))
+			continue
+		}
+		if newObject == nil {
+			b.WriteString(fmt.Sprintf("Removed `%s`\n\n", ref.String()))
+			continue
+		}
+
+		changes, err := diff.Diff(oldObject, newObject)
+		if err != nil {
+			return "", err
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		b.WriteString(fmt.Sprintf("Changed `%s`\n", ref.String()))
+		b.WriteString("```diff\n")
+		for _, c := range changes {
+			b.WriteString(fmt.Sprintf("# %s\n", c.JsonPath))
+			b.WriteString(strings.TrimRight(c.UnifiedDiff, "\n") + "\n")
+		}
+		b.WriteString("```\n\n")
+	}
+	if b.Len() == 0 {
+		return "No changes in rendered objects.\n\n", nil
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,5 @@
+package commands
+
+type imagesCmd struct {
+	Update imagesUpdateCmd `cmd:"" help:"Checks for new image versions and updates pinned fixed images"`
+}
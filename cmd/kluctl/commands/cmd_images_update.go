@@ -0,0 +1,371 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-git/go-git/v5"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	git2 "github.com/kluctl/kluctl/v2/pkg/git"
+	"github.com/kluctl/kluctl/v2/pkg/images"
+	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
+	"github.com/kluctl/kluctl/v2/pkg/prompts"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	yaml3 "gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type imagesUpdateCmd struct {
+	args.ProjectDir
+	args.RegistryCredentials
+
+	FixedImagesFile args.ExistingFileType `group:"images" help:"Also update the fixed images found in the given fixed images file." exts:"yml,yaml"`
+
+	DryRun      bool `group:"misc" help:"Only check for new image versions, without modifying any files"`
+	Commit      bool `group:"misc" help:"Create a git commit for every updated image"`
+	Interactive bool `group:"misc" short:"i" help:"Ask for every image if it should be updated."`
+}
+
+func (cmd *imagesUpdateCmd) Help() string {
+	return `This command searches the targets in .kluctl.yaml and the optional fixed images file for
+fixed images with an 'updatePolicy'. For each of these, the registry of the 'resultImage' is
+queried for available tags and the latest tag matching the policy is written back into
+the 'resultImage' field. Only the 'resultImage' values are replaced, so comments and formatting
+of the modified files are preserved.
+
+Optionally, a git commit is created for every updated image.`
+}
+
+type fixedImageFile struct {
+	path         string
+	isKluctlYaml bool
+	o            *uo.UnstructuredObject
+}
+
+type fixedImageEntry struct {
+	file *fixedImageFile
+	// idx is the index of the fixed image inside the file, as returned by findResultImageNodes
+	idx int
+	fi  types.UpdatableFixedImage
+
+	latest string
+}
+
+func (cmd *imagesUpdateCmd) Run(ctx context.Context) error {
+	projectDir, err := cmd.ProjectDir.GetProjectDir()
+	if err != nil {
+		return err
+	}
+
+	var files []*fixedImageFile
+	if yaml.Exists(filepath.Join(projectDir, ".kluctl.yaml")) {
+		files = append(files, &fixedImageFile{
+			path:         yaml.FixPathExt(filepath.Join(projectDir, ".kluctl.yaml")),
+			isKluctlYaml: true,
+		})
+	}
+	if cmd.FixedImagesFile != "" {
+		p, err := filepath.Abs(cmd.FixedImagesFile.String())
+		if err != nil {
+			return err
+		}
+		files = append(files, &fixedImageFile{path: p})
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("images update can only be used on the root of a Kluctl project that must have a .kluctl.yaml file or with --fixed-images-file")
+	}
+
+	gitRootPath := ""
+	if cmd.Commit {
+		gitRootPath, err = git2.DetectGitRepositoryRoot(projectDir)
+		if err != nil {
+			return err
+		}
+		gitStatus, err := git2.GetWorktreeStatus(ctx, gitRootPath)
+		if err != nil {
+			return err
+		}
+		for _, s := range gitStatus {
+			if (s.Staging != git.Untracked && s.Staging != git.Unmodified) || (s.Worktree != git.Untracked && s.Worktree != git.Unmodified) {
+				status.Tracef(ctx, "gitStatus=%s", gitStatus.String())
+				return fmt.Errorf("--commit can only be used when the git worktree is unmodified")
+			}
+		}
+	}
+
+	ociAuthProvider := auth_provider.NewDefaultAuthProviders("KLUCTL_REGISTRY")
+	if x, err := cmd.RegistryCredentials.BuildAuthProvider(ctx); err != nil {
+		return err
+	} else {
+		ociAuthProvider.RegisterAuthProvider(x, false)
+	}
+
+	var entries []*fixedImageEntry
+	for _, f := range files {
+		l, err := cmd.loadFixedImages(f)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, l...)
+	}
+
+	g := utils.NewGoHelper(ctx, 8)
+	for _, e := range entries {
+		e := e
+		g.RunE(func() error {
+			s := status.Startf(ctx, "%s: Querying tags", e.fi.ResultImage)
+			defer s.Failed()
+			latest, err := images.FindLatestImage(ctx, ociAuthProvider, &e.fi)
+			if err != nil {
+				s.FailedWithMessagef("%s: %s", e.fi.ResultImage, err.Error())
+				return err
+			}
+			e.latest = latest
+			s.Success()
+			return nil
+		})
+	}
+	g.Wait()
+	if g.ErrorOrNil() != nil {
+		return g.ErrorOrNil()
+	}
+
+	// group by old/new image, so that we can create a single commit for all occurrences of the same update
+	type updateKey struct {
+		oldImage string
+		newImage string
+	}
+	updates := map[updateKey][]*fixedImageEntry{}
+	var keys []updateKey
+	for _, e := range entries {
+		if e.latest == e.fi.ResultImage {
+			continue
+		}
+		k := updateKey{oldImage: e.fi.ResultImage, newImage: e.latest}
+		if _, ok := updates[k]; !ok {
+			keys = append(keys, k)
+		}
+		updates[k] = append(updates[k], e)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].oldImage == keys[j].oldImage {
+			return keys[i].newImage < keys[j].newImage
+		}
+		return keys[i].oldImage < keys[j].oldImage
+	})
+
+	for _, k := range keys {
+		status.Infof(ctx, "Image %s can be updated to %s", k.oldImage, k.newImage)
+
+		if cmd.DryRun {
+			continue
+		}
+		if cmd.Interactive {
+			if !prompts.AskForConfirmation(ctx, fmt.Sprintf("Do you want to update image %s to %s?", k.oldImage, k.newImage)) {
+				continue
+			}
+		}
+
+		err = cmd.updateAndCommit(ctx, gitRootPath, updates[k], k.oldImage, k.newImage)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cmd *imagesUpdateCmd) loadFixedImages(f *fixedImageFile) ([]*fixedImageEntry, error) {
+	var err error
+	f.o, err = uo.FromFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var lists [][]*uo.UnstructuredObject
+	if f.isKluctlYaml {
+		targets, _, err := f.o.GetNestedObjectList("targets")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.path, err)
+		}
+		for _, t := range targets {
+			l, _, err := t.GetNestedObjectList("images")
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.path, err)
+			}
+			lists = append(lists, l)
+		}
+	} else {
+		l, _, err := f.o.GetNestedObjectList("images")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.path, err)
+		}
+		lists = append(lists, l)
+	}
+
+	var ret []*fixedImageEntry
+	idx := 0
+	for _, l := range lists {
+		for _, o := range l {
+			e := &fixedImageEntry{
+				file: f,
+				idx:  idx,
+			}
+			idx++
+			err = o.ToStruct(&e.fi)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.path, err)
+			}
+			if e.fi.UpdatePolicy == nil {
+				continue
+			}
+			err = yaml.ValidateStructs(&e.fi)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", f.path, err)
+			}
+			ret = append(ret, e)
+		}
+	}
+
+	// ensure that we'll later be able to update the images without re-encoding the whole file
+	_, err = replaceResultImages(f, ret, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// findResultImageNodes returns the 'resultImage' value nodes of all fixed images found in the file, in the same order
+// as loadFixedImages iterates them. The node is nil for fixed images without a 'resultImage'.
+func findResultImageNodes(f *fixedImageFile, root *yaml3.Node) []*yaml3.Node {
+	var lists []*yaml3.Node
+	if f.isKluctlYaml {
+		targets := yaml.GetMappingValue(root, "targets")
+		if targets != nil && targets.Kind == yaml3.SequenceNode {
+			for _, t := range targets.Content {
+				lists = append(lists, yaml.GetMappingValue(t, "images"))
+			}
+		}
+	} else {
+		lists = append(lists, yaml.GetMappingValue(root, "images"))
+	}
+
+	var ret []*yaml3.Node
+	for _, l := range lists {
+		if l == nil || l.Kind != yaml3.SequenceNode {
+			continue
+		}
+		for _, x := range l.Content {
+			ret = append(ret, yaml.GetMappingValue(x, "resultImage"))
+		}
+	}
+	return ret
+}
+
+// replaceResultImages replaces the 'resultImage' of the given entries with newImage, while preserving comments,
+// key order and formatting of the file. If newImage is nil, the current values are kept, which is used to verify that
+// the file can be updated in-place.
+func replaceResultImages(f *fixedImageFile, entries []*fixedImageEntry, newImage *string) ([]byte, error) {
+	raw, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	root, err := yaml.ParseNode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.path, err)
+	}
+	nodes := findResultImageNodes(f, root)
+
+	replacements := map[*yaml3.Node]string{}
+	for _, e := range entries {
+		if e.idx >= len(nodes) || nodes[e.idx] == nil {
+			return nil, fmt.Errorf("%s: failed to find resultImage of image %s", f.path, e.fi.ResultImage)
+		}
+		n := nodes[e.idx]
+		if n.Value != e.fi.ResultImage {
+			return nil, fmt.Errorf("%s: unexpected resultImage %s, expected %s", f.path, n.Value, e.fi.ResultImage)
+		}
+		if newImage != nil {
+			replacements[n] = *newImage
+		} else {
+			replacements[n] = n.Value
+		}
+	}
+
+	ret, err := yaml.ReplaceScalars(raw, replacements)
+	if err != nil {
+		return nil, fmt.Errorf("%s: unable to update images without reformatting the file: %w", f.path, err)
+	}
+	return ret, nil
+}
+
+func (cmd *imagesUpdateCmd) updateAndCommit(ctx context.Context, gitRootPath string, entries []*fixedImageEntry, oldImage string, newImage string) error {
+	s := status.Startf(ctx, "Updating image %s to %s", oldImage, newImage)
+	defer s.Failed()
+
+	doError := func(err error) error {
+		s.FailedWithMessage(err.Error())
+		return err
+	}
+
+	changedFiles := map[string]*fixedImageFile{}
+	entriesByFile := map[string][]*fixedImageEntry{}
+	for _, e := range entries {
+		changedFiles[e.file.path] = e.file
+		entriesByFile[e.file.path] = append(entriesByFile[e.file.path], e)
+	}
+	for p, f := range changedFiles {
+		raw, err := replaceResultImages(f, entriesByFile[p], &newImage)
+		if err != nil {
+			return doError(err)
+		}
+		st, err := os.Stat(p)
+		if err != nil {
+			return doError(err)
+		}
+		err = os.WriteFile(p, raw, st.Mode().Perm())
+		if err != nil {
+			return doError(err)
+		}
+	}
+	for _, e := range entries {
+		e.fi.ResultImage = newImage
+	}
+
+	if cmd.Commit {
+		r, err := git.PlainOpen(gitRootPath)
+		if err != nil {
+			return doError(err)
+		}
+		wt, err := r.Worktree()
+		if err != nil {
+			return doError(err)
+		}
+		for p := range changedFiles {
+			relToGit, err := filepath.Rel(gitRootPath, p)
+			if err != nil {
+				return doError(err)
+			}
+			_, err = wt.Add(relToGit)
+			if err != nil {
+				return doError(err)
+			}
+		}
+
+		commitMsg := fmt.Sprintf("Updated image %s to %s", oldImage, newImage)
+		_, err = wt.Commit(commitMsg, &git.CommitOptions{})
+		if err != nil {
+			return doError(fmt.Errorf("failed to commit: %w", err))
+		}
+		s.UpdateAndInfoFallbackf("Committed image %s", newImage)
+	}
+	s.Success()
+
+	return nil
+}
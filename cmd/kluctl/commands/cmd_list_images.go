@@ -42,8 +42,9 @@ func (cmd *listImagesCmd) Run(ctx context.Context) error {
 		kubernetesVersion:    cmd.KubernetesVersion,
 	}
 	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
-		result := types.FixedImagesConfig{
-			Images: cmdCtx.images.SeenImages(cmd.Simple),
+		var result types.FixedImagesConfig
+		for _, fi := range cmdCtx.images.SeenImages(cmd.Simple) {
+			result.Images = append(result.Images, types.UpdatableFixedImage{FixedImage: fi})
 		}
 		return outputYamlResult(ctx, cmd.Output, result, false)
 	})
@@ -2,8 +2,10 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
 	"io/ioutil"
@@ -21,7 +23,8 @@ type renderCmd struct {
 	args.RenderOutputDirFlags
 	args.OfflineKubernetesFlags
 
-	PrintAll bool `group:"misc" help:"Write all rendered manifests to stdout"`
+	PrintAll        bool `group:"misc" help:"Write all rendered manifests to stdout"`
+	SensitiveReport bool `group:"misc" help:"Write a report to stdout that lists all fields of rendered objects that contain values from sensitive vars sources. The values themselves are not included."`
 }
 
 func (cmd *renderCmd) Help() string {
@@ -30,6 +33,10 @@ a temporary directory or a specified directory.`
 }
 
 func (cmd *renderCmd) Run(ctx context.Context) error {
+	if cmd.PrintAll && cmd.SensitiveReport {
+		return fmt.Errorf("--print-all and --sensitive-report can not be combined")
+	}
+
 	isTmp := false
 	if cmd.RenderOutputDir == "" {
 		p, err := ioutil.TempDir(utils.GetTmpBaseDir(ctx), "rendered-")
@@ -53,7 +60,26 @@ func (cmd *renderCmd) Run(ctx context.Context) error {
 		kubernetesVersion:    cmd.KubernetesVersion,
 	}
 	return withProjectCommandContext(ctx, ptArgs, func(cmdCtx *commandCtx) error {
-		if cmd.PrintAll {
+		for _, w := range cmdCtx.targetCtx.DeploymentCollection.SensitiveFieldsWarnings() {
+			status.Warningf(cmdCtx.ctx, "%s: %s", w.Ref.String(), w.Message)
+		}
+
+		if cmd.SensitiveReport {
+			if isTmp {
+				defer os.RemoveAll(cmd.RenderOutputDir)
+			}
+			status.Flush(cmdCtx.ctx)
+			fields := cmdCtx.targetCtx.DeploymentCollection.SensitiveFields
+			if fields == nil {
+				fields = []result.SensitiveField{}
+			}
+			s, err := yaml.WriteYamlString(fields)
+			if err != nil {
+				return err
+			}
+			_, err = getStdout(ctx).WriteString(s)
+			return err
+		} else if cmd.PrintAll {
 			var all []any
 			for _, d := range cmdCtx.targetCtx.DeploymentCollection.Deployments {
 				for _, o := range d.Objects {
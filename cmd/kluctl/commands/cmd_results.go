@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"time"
+)
+
+type resultsCmd struct {
+	List    resultsListCmd    `cmd:"" help:"List command results"`
+	Show    resultsShowCmd    `cmd:"" help:"Show a command result"`
+	Export  resultsExportCmd  `cmd:"" help:"Export command results"`
+	Delete  resultsDeleteCmd  `cmd:"" help:"Delete command results"`
+	Diff    resultsDiffCmd    `cmd:"" help:"Compare two command results"`
+	History resultsHistoryCmd `cmd:"" help:"Show the history of an object across command results"`
+	Pin     resultsPinCmd     `cmd:"" help:"Pin command results so that they are never deleted by retention"`
+	Unpin   resultsUnpinCmd   `cmd:"" help:"Unpin command results"`
+}
+
+func buildResultsStore(ctx context.Context, f args.ResultsFlags, allowWrite bool) (results.ResultStore, error) {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{
+			CurrentContext: f.Context,
+		})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	_, mapper, err := k8s.CreateDiscoveryAndMapper(ctx, restConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowWrite {
+		return buildResultStoreRO(ctx, restConfig, mapper, &f.CommandResultReadOnlyFlags)
+	}
+	return buildResultStoreRW(ctx, restConfig, mapper, &args.CommandResultFlags{
+		CommandResultReadOnlyFlags: f.CommandResultReadOnlyFlags,
+		CommandResultWriteFlags: args.CommandResultWriteFlags{
+			WriteCommandResult: true,
+		},
+	}, 0, false)
+}
+
+// filteredResults holds the summaries of all command or validate results that matched the filter flags. Only one of
+// both lists is filled, depending on --validate-results. Both lists are sorted from newest to oldest.
+type filteredResults struct {
+	commandResults  []result.CommandResultSummary
+	validateResults []result.ValidateResultSummary
+}
+
+func (r *filteredResults) ids() []string {
+	var ret []string
+	for _, x := range r.commandResults {
+		ret = append(ret, x.Id)
+	}
+	for _, x := range r.validateResults {
+		ret = append(ret, x.Id)
+	}
+	return ret
+}
+
+func listFilteredResults(store results.ResultStore, f args.ResultsFilterFlags) (*filteredResults, error) {
+	if f.ValidateResults && f.Command != "" {
+		return nil, fmt.Errorf("--command can not be used in combination with --validate-results")
+	}
+
+	var options results.ListResultSummariesOptions
+	if f.Project != "" {
+		repoKey, err := types.ParseRepoKey(f.Project, "git")
+		if err != nil {
+			return nil, err
+		}
+		options.ProjectFilter = &result.ProjectKey{
+			RepoKey: repoKey,
+			SubDir:  f.SubDir,
+		}
+	}
+
+	now := time.Now()
+	filter := func(tk result.TargetKey, startTime metav1.Time) bool {
+		if f.TargetName != "" && tk.TargetName != f.TargetName {
+			return false
+		}
+		if f.ClusterId != "" && tk.ClusterId != f.ClusterId {
+			return false
+		}
+		if f.NewerThan != 0 && startTime.Time.Before(now.Add(-f.NewerThan)) {
+			return false
+		}
+		if f.OlderThan != 0 && !startTime.Time.Before(now.Add(-f.OlderThan)) {
+			return false
+		}
+		return true
+	}
+
+	var ret filteredResults
+	if f.ValidateResults {
+		summaries, err := store.ListValidateResultSummaries(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range summaries {
+			if filter(x.TargetKey, x.StartTime) {
+				ret.validateResults = append(ret.validateResults, x)
+			}
+		}
+	} else {
+		summaries, err := store.ListCommandResultSummaries(options)
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range summaries {
+			if f.Command != "" && x.Command.Command != f.Command {
+				continue
+			}
+			if filter(x.TargetKey, x.Command.StartTime) {
+				ret.commandResults = append(ret.commandResults, x)
+			}
+		}
+	}
+	return &ret, nil
+}
+
+// loadResult loads the command or validate result with the given id. Exactly one of the returned results is non-nil
+// on success.
+func loadResult(store results.ResultStore, id string) (*result.CommandResult, *result.ValidateResult, error) {
+	cr, err := store.GetCommandResult(results.GetCommandResultOptions{
+		Id: id,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if cr != nil {
+		return cr, nil, nil
+	}
+	vr, err := store.GetValidateResult(results.GetValidateResultOptions{
+		Id: id,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if vr != nil {
+		return nil, vr, nil
+	}
+	return nil, nil, fmt.Errorf("result %s not found", id)
+}
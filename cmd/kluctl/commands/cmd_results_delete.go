@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/prompts"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+)
+
+type resultsDeleteCmd struct {
+	args.ResultsFlags
+	args.ResultsFilterFlags
+	args.YesFlags
+
+	Ids []string `arg:"id"`
+}
+
+func (cmd *resultsDeleteCmd) Help() string {
+	return `This command deletes command results and validate results from the cluster.
+
+If result IDs are passed, only these results are deleted. Otherwise, all results matching the filter arguments are
+deleted, in which case at least one filter argument must be specified. Pinned command results are only deleted when
+their IDs are passed explicitly. Only results stored in the namespace specified via --command-result-namespace are
+deleted.
+`
+}
+
+func (cmd *resultsDeleteCmd) Run(ctx context.Context) error {
+	f := cmd.ResultsFilterFlags
+	if len(cmd.Ids) == 0 && f.Project == "" && f.TargetName == "" && f.ClusterId == "" && f.Command == "" && f.NewerThan == 0 && f.OlderThan == 0 {
+		return fmt.Errorf("either result IDs or at least one filter argument must be specified")
+	}
+
+	store, err := buildResultsStore(ctx, cmd.ResultsFlags, true)
+	if err != nil {
+		return err
+	}
+
+	var commandResultIds, validateResultIds []string
+	if len(cmd.Ids) != 0 {
+		commandResultIds, validateResultIds, err = cmd.resolveIds(store)
+		if err != nil {
+			return err
+		}
+	} else {
+		r, err := listFilteredResults(store, cmd.ResultsFilterFlags)
+		if err != nil {
+			return err
+		}
+		for _, x := range r.commandResults {
+			if x.Pinned {
+				continue
+			}
+			commandResultIds = append(commandResultIds, x.Id)
+		}
+		for _, x := range r.validateResults {
+			validateResultIds = append(validateResultIds, x.Id)
+		}
+	}
+
+	cnt := len(commandResultIds) + len(validateResultIds)
+	if cnt == 0 {
+		status.Info(ctx, "No results to delete")
+		return nil
+	}
+	if !cmd.Yes && !prompts.AskForConfirmation(ctx, fmt.Sprintf("Do you really want to delete %d results?", cnt)) {
+		return fmt.Errorf("aborted")
+	}
+
+	for _, id := range commandResultIds {
+		err = store.DeleteCommandResult(id)
+		if err != nil {
+			return err
+		}
+		status.Infof(ctx, "Deleted command result %s", id)
+	}
+	for _, id := range validateResultIds {
+		err = store.DeleteValidateResult(id)
+		if err != nil {
+			return err
+		}
+		status.Infof(ctx, "Deleted validate result %s", id)
+	}
+	return nil
+}
+
+// resolveIds finds out which of the passed IDs belong to command results and which belong to validate results.
+func (cmd *resultsDeleteCmd) resolveIds(store results.ResultStore) ([]string, []string, error) {
+	commandResults, err := store.ListCommandResultSummaries(results.ListResultSummariesOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	validateResults, err := store.ListValidateResultSummaries(results.ListResultSummariesOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	isCommandResult := map[string]bool{}
+	for _, x := range commandResults {
+		isCommandResult[x.Id] = true
+	}
+	isValidateResult := map[string]bool{}
+	for _, x := range validateResults {
+		isValidateResult[x.Id] = true
+	}
+
+	var commandResultIds, validateResultIds []string
+	for _, id := range cmd.Ids {
+		if isCommandResult[id] {
+			commandResultIds = append(commandResultIds, id)
+		} else if isValidateResult[id] {
+			validateResultIds = append(validateResultIds, id)
+		} else {
+			return nil, nil, fmt.Errorf("result %s not found", id)
+		}
+	}
+	return commandResultIds, validateResultIds, nil
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+)
+
+type resultsDiffCmd struct {
+	args.ResultsFlags
+	args.OutputFormatFlags
+
+	Ids []string `arg:"id"`
+}
+
+func (cmd *resultsDiffCmd) Help() string {
+	return `This command compares two command results, e.g. the results of two deployments of the same target,
+and outputs the differences in rendered objects, args and vars, images and git info.
+
+The first ID is treated as the old command result and the second ID as the new command result. Only the rendered
+objects are compared, meaning that changes done on the cluster side are not shown. Vars loaded from sensitive sources
+are not compared.
+`
+}
+
+func (cmd *resultsDiffCmd) Run(ctx context.Context) error {
+	if len(cmd.Ids) != 2 {
+		return fmt.Errorf("exactly two command result IDs must be specified")
+	}
+
+	store, err := buildResultsStore(ctx, cmd.ResultsFlags, false)
+	if err != nil {
+		return err
+	}
+
+	var crs []*result.CommandResult
+	for _, id := range cmd.Ids {
+		cr, err := store.GetCommandResult(results.GetCommandResultOptions{
+			Id: id,
+		})
+		if err != nil {
+			return err
+		}
+		if cr == nil {
+			return fmt.Errorf("command result %s not found", id)
+		}
+		if !cmd.NoObfuscate {
+			var obfuscator diff.Obfuscator
+			err = obfuscator.ObfuscateResult(cr)
+			if err != nil {
+				return err
+			}
+		}
+		crs = append(crs, cr)
+	}
+
+	c, err := results.CompareCommandResults(crs[0], crs[1])
+	if err != nil {
+		return err
+	}
+
+	status.Flush(ctx)
+	err = outputHelper(ctx, cmd.OutputFormat, func(format string) (string, error) {
+		return formatCommandResultComparison(c, format, cmd.ShortOutput)
+	})
+	status.Flush(ctx)
+	return err
+}
+
+func formatCommandResultComparison(c *result.CommandResultComparison, format string, short bool) (string, error) {
+	switch format {
+	case "text":
+		return formatCommandResultComparisonText(c, short), nil
+	case "yaml":
+		return yaml.WriteYamlString(c)
+	case "json":
+		return formatJsonResult(c)
+	default:
+		return "", fmt.Errorf("invalid format: %s", format)
+	}
+}
+
+func formatCommandResultComparisonText(c *result.CommandResultComparison, short bool) string {
+	buf := bytes.NewBuffer(nil)
+
+	buf.WriteString(fmt.Sprintf("Comparing command result %s with %s\n", c.OldResultId, c.NewResultId))
+
+	if c.OldGitInfo.Commit != c.NewGitInfo.Commit {
+		buf.WriteString(fmt.Sprintf("\nGit commit changed from %s to %s\n", c.OldGitInfo.Commit, c.NewGitInfo.Commit))
+	} else {
+		buf.WriteString(fmt.Sprintf("\nGit commit %s is unchanged\n", c.OldGitInfo.Commit))
+	}
+
+	if len(c.VarsChanges) != 0 {
+		buf.WriteString("\nChanged vars:\n")
+		prettyChangesTable(buf, c.VarsChanges)
+	}
+
+	if len(c.ImageChanges) != 0 {
+		buf.WriteString("\nChanged images:\n")
+		var t utils.PrettyTable
+		t.AddRow("Image", "Old", "New")
+		for _, ic := range c.ImageChanges {
+			image := ic.Image
+			if ic.Object != nil {
+				image += fmt.Sprintf(" (%s)", ic.Object.String())
+			}
+			t.AddRow(image, ic.OldResultImage, ic.NewResultImage)
+		}
+		buf.WriteString(t.Render([]int{60}))
+	}
+
+	if len(c.AddedObjects) != 0 {
+		buf.WriteString("\nAdded objects:\n")
+		prettyObjectRefs(buf, c.AddedObjects)
+	}
+	if len(c.RemovedObjects) != 0 {
+		buf.WriteString("\nRemoved objects:\n")
+		prettyObjectRefs(buf, c.RemovedObjects)
+	}
+	if len(c.ChangedObjects) != 0 {
+		var refs []k8s.ObjectRef
+		for _, o := range c.ChangedObjects {
+			refs = append(refs, o.Ref)
+		}
+		buf.WriteString("\nChanged objects:\n")
+		prettyObjectRefs(buf, refs)
+
+		if !short {
+			for _, o := range c.ChangedObjects {
+				buf.WriteString("\n")
+				prettyChanges(buf, o.Ref, o.Changes)
+			}
+		}
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type resultsExportCmd struct {
+	args.ResultsFlags
+	args.ResultsFilterFlags
+
+	OutputDir string `group:"misc" help:"Export the results as yaml files into the given directory."`
+	OutputTar string `group:"misc" help:"Export the results as yaml files into the given gzip compressed tarball."`
+
+	Ids []string `arg:"id"`
+}
+
+func (cmd *resultsExportCmd) Help() string {
+	return `This command exports command results and validate results as yaml files, either into a directory
+or into a gzip compressed tarball.
+
+If result IDs are passed, only these results are exported. Otherwise, all results matching the filter arguments are
+exported. Each result is written into its own file, named 'command-result-<id>.yaml' or 'validate-result-<id>.yaml'.
+`
+}
+
+func (cmd *resultsExportCmd) Run(ctx context.Context) error {
+	if (cmd.OutputDir == "") == (cmd.OutputTar == "") {
+		return fmt.Errorf("exactly one of --output-dir or --output-tar must be specified")
+	}
+
+	store, err := buildResultsStore(ctx, cmd.ResultsFlags, false)
+	if err != nil {
+		return err
+	}
+
+	ids := cmd.Ids
+	if len(ids) == 0 {
+		r, err := listFilteredResults(store, cmd.ResultsFilterFlags)
+		if err != nil {
+			return err
+		}
+		ids = r.ids()
+	}
+
+	var write func(name string, data []byte) error
+	finish := func() error { return nil }
+	if cmd.OutputDir != "" {
+		err = os.MkdirAll(cmd.OutputDir, 0o700)
+		if err != nil {
+			return err
+		}
+		write = func(name string, data []byte) error {
+			return os.WriteFile(filepath.Join(cmd.OutputDir, name), data, 0o600)
+		}
+	} else {
+		f, err := os.Create(cmd.OutputTar)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		gw := gzip.NewWriter(f)
+		tw := tar.NewWriter(gw)
+
+		write = func(name string, data []byte) error {
+			err := tw.WriteHeader(&tar.Header{
+				Name:    name,
+				Mode:    0o600,
+				Size:    int64(len(data)),
+				ModTime: time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+			_, err = tw.Write(data)
+			return err
+		}
+		finish = func() error {
+			err := tw.Close()
+			if err != nil {
+				return err
+			}
+			return gw.Close()
+		}
+	}
+
+	for _, id := range ids {
+		err = cmd.exportResult(store, id, write)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = finish()
+	if err != nil {
+		return err
+	}
+
+	status.Infof(ctx, "Exported %d results", len(ids))
+	return nil
+}
+
+func (cmd *resultsExportCmd) exportResult(store results.ResultStore, id string, write func(name string, data []byte) error) error {
+	cr, vr, err := loadResult(store, id)
+	if err != nil {
+		return err
+	}
+
+	var name string
+	var data []byte
+	if cr != nil {
+		name = fmt.Sprintf("command-result-%s.yaml", id)
+		data, err = yaml.WriteYamlBytes(cr.ToCompacted())
+	} else {
+		name = fmt.Sprintf("validate-result-%s.yaml", id)
+		data, err = yaml.WriteYamlBytes(vr)
+	}
+	if err != nil {
+		return err
+	}
+	return write(name, data)
+}
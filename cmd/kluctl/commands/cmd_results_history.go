@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"strings"
+	"time"
+)
+
+type resultsHistoryCmd struct {
+	args.ResultsFlags
+	args.ResultsFilterFlags
+	args.OutputFormatFlags
+
+	Ref []string `arg:"ref"`
+}
+
+func (cmd *resultsHistoryCmd) Help() string {
+	return `This command shows the history of a single object across all stored command results, sorted from
+newest to oldest.
+
+The object is specified in the format '[namespace/]kind[.group]/name', e.g. 'my-ns/ConfigMap/my-cm' or
+'my-ns/Deployment.apps/my-deployment'. For each command result that created, changed, orphaned or deleted the
+object, an entry with the command, the git commit and the changes is shown. Changes that only reverted modifications
+done on the cluster side (the rendered object did not change) are shown as drifted. Dry-run results, e.g. from
+'kluctl diff', are not included.
+
+All matching command results must be loaded to build the history, so it is advised to restrict the results via the
+filter arguments, e.g. --project and --target-name.
+`
+}
+
+func (cmd *resultsHistoryCmd) Run(ctx context.Context) error {
+	if len(cmd.Ref) != 1 {
+		return fmt.Errorf("exactly one object must be specified")
+	}
+	if cmd.ValidateResults {
+		return fmt.Errorf("--validate-results can not be used to show object history")
+	}
+	ref, err := parseObjectRefArg(cmd.Ref[0])
+	if err != nil {
+		return err
+	}
+
+	store, err := buildResultsStore(ctx, cmd.ResultsFlags, false)
+	if err != nil {
+		return err
+	}
+
+	r, err := listFilteredResults(store, cmd.ResultsFilterFlags)
+	if err != nil {
+		return err
+	}
+
+	s := status.Startf(ctx, "Loading %d command results", len(r.commandResults))
+	entries, err := results.LoadObjectHistory(store, r.commandResults, ref)
+	if err != nil {
+		s.FailedWithMessagef("Failed to load command results: %s", err.Error())
+		return err
+	}
+	s.Success()
+
+	if !cmd.NoObfuscate {
+		var obfuscator diff.Obfuscator
+		for _, e := range entries {
+			err = obfuscator.ObfuscateChanges(e.Ref, e.Changes)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	status.Flush(ctx)
+	err = outputHelper(ctx, cmd.OutputFormat, func(format string) (string, error) {
+		switch format {
+		case "text":
+			return formatObjectHistoryText(ref, entries, cmd.ShortOutput), nil
+		case "yaml":
+			return yaml.WriteYamlString(entries)
+		case "json":
+			return formatJsonResult(entries)
+		default:
+			return "", fmt.Errorf("invalid format: %s", format)
+		}
+	})
+	status.Flush(ctx)
+	return err
+}
+
+// parseObjectRefArg parses an object reference in the format '[namespace/]kind[.group]/name'.
+func parseObjectRefArg(s string) (k8s.ObjectRef, error) {
+	var ref k8s.ObjectRef
+	parts := strings.Split(s, "/")
+	switch len(parts) {
+	case 2:
+		ref.Kind, ref.Name = parts[0], parts[1]
+	case 3:
+		ref.Namespace, ref.Kind, ref.Name = parts[0], parts[1], parts[2]
+	default:
+		return ref, fmt.Errorf("invalid object reference %s, expected [namespace/]kind[.group]/name", s)
+	}
+	if x := strings.SplitN(ref.Kind, ".", 2); len(x) == 2 {
+		ref.Kind, ref.Group = x[0], x[1]
+	}
+	if ref.Kind == "" || ref.Name == "" {
+		return ref, fmt.Errorf("invalid object reference %s, expected [namespace/]kind[.group]/name", s)
+	}
+	return ref, nil
+}
+
+func formatObjectHistoryText(ref k8s.ObjectRef, entries []result.ObjectHistoryEntry, short bool) string {
+	buf := bytes.NewBuffer(nil)
+
+	if len(entries) == 0 {
+		buf.WriteString(fmt.Sprintf("No history found for object %s\n", ref.String()))
+		return buf.String()
+	}
+
+	buf.WriteString(fmt.Sprintf("History of object %s:\n", ref.String()))
+
+	var t utils.PrettyTable
+	t.AddRow("Started", "ID", "Command", "Target", "Commit", "Event", "Changes")
+	for _, e := range entries {
+		commit := e.GitInfo.Commit
+		if e.GitInfo.Dirty {
+			commit += " (dirty)"
+		}
+		t.AddRow(e.StartTime.Format(time.RFC3339), e.ResultId, e.Command, e.TargetKey.TargetName, commit, e.Event, fmt.Sprintf("%d", len(e.Changes)))
+	}
+	buf.WriteString(t.Render([]int{-1, -1, -1, -1, -1, -1, -1}))
+
+	if !short {
+		for _, e := range entries {
+			if len(e.Changes) == 0 {
+				continue
+			}
+			buf.WriteString(fmt.Sprintf("\nChanges in command result %s (%s):\n", e.ResultId, e.Event))
+			prettyChangesTable(buf, e.Changes)
+		}
+	}
+
+	return buf.String()
+}
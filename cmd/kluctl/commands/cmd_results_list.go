@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"strings"
+	"time"
+)
+
+type resultsListCmd struct {
+	args.ResultsFlags
+	args.ResultsFilterFlags
+
+	OutputFormat []string `group:"misc" short:"o" help:"Specify output format and target file, in the format 'format=path'. Format can either be 'text', 'yaml' or 'json'. Can be specified multiple times."`
+	Limit        int      `group:"misc" help:"Limit the number of results to list. Set to 0 to list all results." default:"0"`
+}
+
+func (cmd *resultsListCmd) Help() string {
+	return `This command lists the command results (or validate results when --validate-results is passed)
+that are stored in the cluster, sorted from newest to oldest.
+
+The 'yaml' and 'json' formats output the full result summaries.
+`
+}
+
+func (cmd *resultsListCmd) Run(ctx context.Context) error {
+	store, err := buildResultsStore(ctx, cmd.ResultsFlags, false)
+	if err != nil {
+		return err
+	}
+
+	r, err := listFilteredResults(store, cmd.ResultsFilterFlags)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Limit > 0 {
+		if len(r.commandResults) > cmd.Limit {
+			r.commandResults = r.commandResults[:cmd.Limit]
+		}
+		if len(r.validateResults) > cmd.Limit {
+			r.validateResults = r.validateResults[:cmd.Limit]
+		}
+	}
+
+	var items any
+	if cmd.ValidateResults {
+		items = append([]result.ValidateResultSummary{}, r.validateResults...)
+	} else {
+		items = append([]result.CommandResultSummary{}, r.commandResults...)
+	}
+
+	return outputHelper(ctx, cmd.OutputFormat, func(format string) (string, error) {
+		switch format {
+		case "text":
+			if cmd.ValidateResults {
+				return formatValidateResultSummariesText(r.validateResults), nil
+			}
+			return formatCommandResultSummariesText(r.commandResults), nil
+		case "yaml":
+			return yaml.WriteYamlString(items)
+		case "json":
+			return formatJsonResult(items)
+		default:
+			return "", fmt.Errorf("invalid format: %s", format)
+		}
+	})
+}
+
+func formatResultProject(pk result.ProjectKey) string {
+	s := pk.RepoKey.String()
+	if pk.SubDir != "" {
+		s += "/" + pk.SubDir
+	}
+	return s
+}
+
+func formatCommandResultSummariesText(summaries []result.CommandResultSummary) string {
+	var t utils.PrettyTable
+	t.AddRow("ID", "Started", "Command", "Project", "Target", "Summary")
+	for _, x := range summaries {
+		var s []string
+		add := func(cnt int, name string) {
+			if cnt != 0 {
+				s = append(s, fmt.Sprintf("%d %s", cnt, name))
+			}
+		}
+		if x.Pinned {
+			s = append(s, "pinned")
+		}
+		add(x.NewObjects, "new")
+		add(x.ChangedObjects, "changed")
+		add(x.OrphanObjects, "orphan")
+		add(x.DeletedObjects, "deleted")
+		add(len(x.Errors), "errors")
+		add(len(x.Warnings), "warnings")
+
+		t.AddRow(x.Id, x.Command.StartTime.Format(time.RFC3339), x.Command.Command, formatResultProject(x.ProjectKey), x.TargetKey.TargetName, strings.Join(s, ", "))
+	}
+	return t.Render([]int{-1, -1, -1, 60, -1, -1})
+}
+
+func formatValidateResultSummariesText(summaries []result.ValidateResultSummary) string {
+	var t utils.PrettyTable
+	t.AddRow("ID", "Started", "Project", "Target", "Ready", "Errors", "Warnings")
+	for _, x := range summaries {
+		t.AddRow(x.Id, x.StartTime.Format(time.RFC3339), formatResultProject(x.ProjectKey), x.TargetKey.TargetName, fmt.Sprintf("%v", x.Ready), fmt.Sprintf("%d", x.Errors), fmt.Sprintf("%d", x.Warnings))
+	}
+	return t.Render([]int{-1, -1, 60, -1, -1, -1, -1})
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+)
+
+type resultsPinCmd struct {
+	args.ResultsFlags
+
+	Ids []string `arg:"id"`
+}
+
+func (cmd *resultsPinCmd) Help() string {
+	return `This command pins one or more command results. Pinned command results are never deleted by the
+retention based cleanup, e.g. when --keep-command-results-count or --keep-command-results-age are exceeded. Pinned
+command results can still be deleted via 'kluctl results delete <id>'.
+`
+}
+
+func (cmd *resultsPinCmd) Run(ctx context.Context) error {
+	return setResultsPinned(ctx, cmd.ResultsFlags, cmd.Ids, true)
+}
+
+type resultsUnpinCmd struct {
+	args.ResultsFlags
+
+	Ids []string `arg:"id"`
+}
+
+func (cmd *resultsUnpinCmd) Help() string {
+	return `This command unpins one or more command results, so that they are deleted by the retention based
+cleanup again.
+`
+}
+
+func (cmd *resultsUnpinCmd) Run(ctx context.Context) error {
+	return setResultsPinned(ctx, cmd.ResultsFlags, cmd.Ids, false)
+}
+
+func setResultsPinned(ctx context.Context, flags args.ResultsFlags, ids []string, pinned bool) error {
+	if len(ids) == 0 {
+		return fmt.Errorf("at least one command result ID must be specified")
+	}
+
+	store, err := buildResultsStore(ctx, flags, true)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err = store.SetCommandResultPinned(id, pinned)
+		if err != nil {
+			return err
+		}
+		if pinned {
+			status.Infof(ctx, "Pinned command result %s", id)
+		} else {
+			status.Infof(ctx, "Unpinned command result %s", id)
+		}
+	}
+	return nil
+}
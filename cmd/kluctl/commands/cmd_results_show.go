@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+)
+
+type resultsShowCmd struct {
+	args.ResultsFlags
+	args.OutputFormatFlags
+
+	Ids []string `arg:"id"`
+}
+
+func (cmd *resultsShowCmd) Help() string {
+	return `This command loads a single command result or validate result and outputs it in the same
+formats as used by the commands that produced the result, e.g. 'kluctl deploy' or 'kluctl validate'.
+
+Result IDs can be found via 'kluctl results list'.
+`
+}
+
+func (cmd *resultsShowCmd) Run(ctx context.Context) error {
+	if len(cmd.Ids) != 1 {
+		return fmt.Errorf("exactly one result ID must be specified")
+	}
+
+	store, err := buildResultsStore(ctx, cmd.ResultsFlags, false)
+	if err != nil {
+		return err
+	}
+
+	cr, vr, err := loadResult(store, cmd.Ids[0])
+	if err != nil {
+		return err
+	}
+
+	if vr != nil {
+		return outputValidateResult2(ctx, cmd.OutputFormat, vr)
+	}
+
+	if !cmd.NoObfuscate {
+		var obfuscator diff.Obfuscator
+		err = obfuscator.ObfuscateResult(cr)
+		if err != nil {
+			return err
+		}
+	}
+	return outputCommandResult2(ctx, cmd.OutputFormatFlags, cr)
+}
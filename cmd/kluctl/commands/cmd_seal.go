@@ -12,6 +12,8 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
 type sealCmd struct {
@@ -21,8 +23,15 @@ type sealCmd struct {
 	args.RegistryCredentials
 	args.OfflineKubernetesFlags
 
-	ForceReseal bool   `group:"misc" help:"Lets kluctl ignore secret hashes found in already sealed secrets and thus forces resealing of those."`
-	CertFile    string `group:"misc" help:"Use the given certificate for sealing instead of requesting it from the sealed-secrets controller"`
+	ForceReseal   bool     `group:"misc" help:"Lets kluctl ignore secret hashes found in already sealed secrets and thus forces resealing of those."`
+	CertFile      string   `group:"misc" help:"Use the given certificate for sealing instead of requesting it from the sealed-secrets controller"`
+	Rotate        bool     `group:"misc" help:"Only seal secrets that were sealed for a different certificate than the current one, or that were not sealed at all yet. Useful after the sealed-secrets controller has rotated its sealing key."`
+	ExtraCertFile []string `group:"misc" help:"Additionally seal for the given certificate and store the sealed secrets with a different output pattern. Must be in the form <outputPattern>=<certFile>. Useful when migrating to a new cluster, as it allows to seal for the old and the new cluster at once. Can be specified multiple times."`
+}
+
+type extraSealCert struct {
+	outputPattern string
+	certFile      string
 }
 
 func (cmd *sealCmd) Help() string {
@@ -31,10 +40,14 @@ then renders the target, including all files with the '.sealme' extension. Then
 kubeseal on each '.sealme' file and stores secrets in the directory specified by
 '--local-sealed-secrets', using the outputPattern from your deployment project.
 
-If no '--target' is specified, sealing is performed for all targets.`
+If no '--target' is specified, sealing is performed for all targets.
+
+When '--rotate' is specified, only sealed secrets are re-sealed that were sealed for an
+outdated certificate (as recorded in the 'kluctl.io/sealedsecret-cert-hash' annotation).
+All other sealed secrets are left untouched, even if their secret values have changed.`
 }
 
-func (cmd *sealCmd) runCmdSealForTarget(ctx context.Context, p *kluctl_project.LoadedKluctlProject, targetName string) error {
+func (cmd *sealCmd) runCmdSealForTarget(ctx context.Context, p *kluctl_project.LoadedKluctlProject, targetName string, extraCerts []extraSealCert) error {
 	s := status.Startf(ctx, "%s: Sealing for target", targetName)
 	defer s.FailedWithMessagef("%s: Sealing failed", targetName)
 
@@ -79,16 +92,73 @@ func (cmd *sealCmd) runCmdSealForTarget(ctx context.Context, p *kluctl_project.L
 		}
 
 		cmd2 := commands.NewSealCommand(cmdCtx.targetCtx.DeploymentCollection, outputPattern, cmdCtx.targetCtx.SharedContext.RenderDir, cmdCtx.targetCtx.SharedContext.SealedSecretsDir)
-		err = cmd2.Run(sealer)
-
+		cmd2.Rotate = cmd.Rotate
+		summary, err := cmd2.Run(sealer)
 		if err != nil {
 			return doFail(err)
 		}
+		cmd.printSummary(cmdCtx, targetName, sealer, summary)
+
+		for _, ec := range extraCerts {
+			cert, err := loadCertFile(ec.certFile)
+			if err != nil {
+				return doFail(err)
+			}
+			sealer, err := seal.NewSealer(cmdCtx.ctx, cert, cmd.ForceReseal)
+			if err != nil {
+				return doFail(err)
+			}
+
+			cmd2 := commands.NewSealCommand(cmdCtx.targetCtx.DeploymentCollection, ec.outputPattern, cmdCtx.targetCtx.SharedContext.RenderDir, cmdCtx.targetCtx.SharedContext.SealedSecretsDir)
+			cmd2.Rotate = cmd.Rotate
+			cmd2.OutputPatternOverride = ec.outputPattern
+			summary, err := cmd2.Run(sealer)
+			if err != nil {
+				return doFail(err)
+			}
+			cmd.printSummary(cmdCtx, fmt.Sprintf("%s (%s)", targetName, ec.outputPattern), sealer, summary)
+		}
+
 		s.Success()
 		return nil
 	})
 }
 
+func shortCertHash(h string) string {
+	if h == "" {
+		return "<unknown>"
+	}
+	if len(h) > 12 {
+		return h[:12]
+	}
+	return h
+}
+
+func (cmd *sealCmd) printSummary(cmdCtx *commandCtx, name string, sealer *seal.Sealer, summary *commands.SealSummary) {
+	relPath := func(p string) string {
+		r, err := filepath.Rel(cmdCtx.targetCtx.KluctlProject.LoadArgs.ProjectDir, p)
+		if err != nil {
+			return p
+		}
+		return r
+	}
+
+	for _, rf := range summary.Rotated {
+		var hashes []string
+		for _, h := range rf.OutdatedCertHashes {
+			hashes = append(hashes, shortCertHash(h))
+		}
+		status.Infof(cmdCtx.ctx, "%s: %s was sealed for outdated cert(s) %s", name, relPath(rf.Path), strings.Join(hashes, ", "))
+	}
+
+	upToDateMsg := "already sealed for the current cert"
+	if cmd.Rotate {
+		upToDateMsg = "skipped as they are already sealed for the current cert"
+	}
+	status.Infof(cmdCtx.ctx, "%s: Sealed for cert %s: %d new, %d re-sealed for outdated certs, %d %s",
+		name, shortCertHash(sealer.CertHash()), len(summary.Sealed), len(summary.Rotated), len(summary.UpToDate), upToDateMsg)
+}
+
 func (cmd *sealCmd) loadCert(cmdCtx *commandCtx) (*x509.Certificate, error) {
 	sealingConfig := cmdCtx.targetCtx.Target.SealingConfig
 
@@ -110,15 +180,7 @@ func (cmd *sealCmd) loadCert(cmdCtx *commandCtx) (*x509.Certificate, error) {
 	}
 
 	if certFile != "" {
-		d, err := os.ReadFile(certFile)
-		if err != nil {
-			return nil, err
-		}
-		cert, err := seal.ParseCert(d)
-		if err != nil {
-			return nil, err
-		}
-		return cert, nil
+		return loadCertFile(certFile)
 	} else {
 		if cmdCtx.targetCtx.SharedContext.K == nil {
 			return nil, fmt.Errorf("must specify certFile when sealing in offline mode")
@@ -156,7 +218,35 @@ func (cmd *sealCmd) loadCert(cmdCtx *commandCtx) (*x509.Certificate, error) {
 	}
 }
 
+func loadCertFile(certFile string) (*x509.Certificate, error) {
+	d, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+	return seal.ParseCert(d)
+}
+
+func (cmd *sealCmd) parseExtraCerts() ([]extraSealCert, error) {
+	var ret []extraSealCert
+	for _, x := range cmd.ExtraCertFile {
+		s := strings.SplitN(x, "=", 2)
+		if len(s) != 2 || s[0] == "" || s[1] == "" {
+			return nil, fmt.Errorf("invalid --extra-cert-file '%s', must be in the form <outputPattern>=<certFile>", x)
+		}
+		ret = append(ret, extraSealCert{
+			outputPattern: s[0],
+			certFile:      s[1],
+		})
+	}
+	return ret, nil
+}
+
 func (cmd *sealCmd) Run(ctx context.Context) error {
+	extraCerts, err := cmd.parseExtraCerts()
+	if err != nil {
+		return err
+	}
+
 	return withKluctlProjectFromArgs(ctx, cmd.ProjectFlags, nil, &cmd.HelmCredentials, &cmd.RegistryCredentials, false, true, false, func(ctx context.Context, p *kluctl_project.LoadedKluctlProject) error {
 		hadError := false
 
@@ -171,7 +261,7 @@ func (cmd *sealCmd) Run(ctx context.Context) error {
 			}
 			noTargetMatch = false
 
-			err := cmd.runCmdSealForTarget(ctx, p, target.Name)
+			err := cmd.runCmdSealForTarget(ctx, p, target.Name, extraCerts)
 			if err != nil {
 				hadError = true
 				status.Error(ctx, err.Error())
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/sops"
+	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type sopsCmd struct {
+	Encrypt sopsEncryptCmd `cmd:"" help:"Encrypt files with SOPS"`
+	Decrypt sopsDecryptCmd `cmd:"" help:"Decrypt SOPS encrypted files"`
+	Edit    sopsEditCmd    `cmd:"" help:"Edit SOPS encrypted files"`
+}
+
+func withSopsDecryptor(ctx context.Context, f args.SopsDecryptionFlags, cb func(d *decryptor.Decryptor) error) error {
+	d := decryptor.NewDecryptor("", decryptor.MaxEncryptedFileSize)
+	d.AddLocalKeyService()
+
+	if f.DecryptionSecret != "" {
+		tmpDir, err := os.MkdirTemp(utils.GetTmpBaseDir(ctx), "sops-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmpDir)
+
+		err = addSopsSecretKeyServer(ctx, f, d, filepath.Join(tmpDir, "gnupghome"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return cb(d)
+}
+
+func addSopsSecretKeyServer(ctx context.Context, f args.SopsDecryptionFlags, d *decryptor.Decryptor, gnuPGHome string) error {
+	s := strings.SplitN(f.DecryptionSecret, "/", 2)
+	if len(s) != 2 || s[0] == "" || s[1] == "" {
+		return fmt.Errorf("invalid decryption secret '%s', must be in the format <namespace>/<name>", f.DecryptionSecret)
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{
+			CurrentContext: f.Context,
+		})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	corev1Client, err := v1.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	secret, err := corev1Client.Secrets(s[0]).Get(ctx, s[1], metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get decryption secret %s: %w", f.DecryptionSecret, err)
+	}
+
+	err = os.MkdirAll(gnuPGHome, 0o700)
+	if err != nil {
+		return err
+	}
+
+	ks, err := sops.BuildSopsKeyServerFromSecret(secret, gnuPGHome)
+	if err != nil {
+		return err
+	}
+	if ks != nil {
+		d.AddKeyServiceClient(ks)
+	}
+	return nil
+}
+
+func writeSopsResult(ctx context.Context, path string, data []byte, inPlace bool) error {
+	if inPlace {
+		return os.WriteFile(path, data, 0o600)
+	}
+	stdout, _ := getStdStreams(ctx)
+	_, err := stdout.Write(data)
+	return err
+}
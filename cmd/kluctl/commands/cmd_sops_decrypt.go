@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
+	"os"
+)
+
+type sopsDecryptCmd struct {
+	args.SopsDecryptionFlags
+
+	InPlace bool `group:"misc" short:"i" help:"Write the decrypted content back to the files instead of printing it to stdout."`
+
+	Files []string `arg:"file"`
+}
+
+func (cmd *sopsDecryptCmd) Help() string {
+	return `Decrypts the given SOPS encrypted files, using the same key sources (age, PGP, cloud KMS)
+as kluctl uses when deploying.`
+}
+
+func (cmd *sopsDecryptCmd) Run(ctx context.Context) error {
+	return withSopsDecryptor(ctx, cmd.SopsDecryptionFlags, func(d *decryptor.Decryptor) error {
+		for _, path := range cmd.Files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			format := formats.FormatForPath(path)
+			plain, err := d.SopsDecryptWithFormat(data, format, format)
+			if err != nil {
+				return fmt.Errorf("failed to decrypt %s: %w", path, err)
+			}
+			err = writeSopsResult(ctx, path, plain, cmd.InPlace)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
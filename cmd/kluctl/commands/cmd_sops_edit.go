@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/sops"
+	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type sopsEditCmd struct {
+	args.SopsDecryptionFlags
+
+	SopsConfig string `group:"misc" help:"Path to the SOPS config file to use when creating new files. If omitted, the nearest .sops.yaml is searched, starting at the directory of each file."`
+
+	Files []string `arg:"file"`
+}
+
+func (cmd *sopsEditCmd) Help() string {
+	return `Decrypts the given files into temporary plaintext copies and opens them in $EDITOR
+(falling back to vi). After the editor exits, changed files are re-encrypted with the
+same data key and keys. Files that do not exist yet are created and encrypted with the
+matching creation rule from .sops.yaml.`
+}
+
+func (cmd *sopsEditCmd) Run(ctx context.Context) error {
+	return withSopsDecryptor(ctx, cmd.SopsDecryptionFlags, func(d *decryptor.Decryptor) error {
+		for _, path := range cmd.Files {
+			changed, err := sops.EditFile(ctx, d.KeyServices(), path, cmd.SopsConfig, runEditor)
+			if err != nil {
+				return fmt.Errorf("failed to edit %s: %w", path, err)
+			}
+			if !changed {
+				status.Infof(ctx, "%s was not changed", path)
+			}
+		}
+		return nil
+	})
+}
+
+func runEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	// EDITOR may contain arguments, e.g. "code --wait"
+	s := strings.Fields(editor)
+	c := exec.Command(s[0], append(s[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	err := c.Run()
+	if err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/sops"
+	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
+	"os"
+)
+
+type sopsEncryptCmd struct {
+	args.SopsDecryptionFlags
+
+	InPlace    bool   `group:"misc" short:"i" help:"Write the encrypted content back to the files instead of printing it to stdout."`
+	SopsConfig string `group:"misc" help:"Path to the SOPS config file to use. If omitted, the nearest .sops.yaml is searched, starting at the directory of each file."`
+
+	Files []string `arg:"file"`
+}
+
+func (cmd *sopsEncryptCmd) Help() string {
+	return `Encrypts the given files with SOPS. The keys to encrypt with are determined by the
+creation rules found in the nearest .sops.yaml (or the file passed via --sops-config),
+which means that the same configuration as for the sops CLI is used.
+
+Files that are already encrypted are rejected.`
+}
+
+func (cmd *sopsEncryptCmd) Run(ctx context.Context) error {
+	return withSopsDecryptor(ctx, cmd.SopsDecryptionFlags, func(d *decryptor.Decryptor) error {
+		for _, path := range cmd.Files {
+			err := cmd.encryptFile(ctx, d, path)
+			if err != nil {
+				return fmt.Errorf("failed to encrypt %s: %w", path, err)
+			}
+		}
+		return nil
+	})
+}
+
+func (cmd *sopsEncryptCmd) encryptFile(ctx context.Context, d *decryptor.Decryptor, path string) error {
+	plain, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	rule, err := sops.LoadCreationRule(path, cmd.SopsConfig)
+	if err != nil {
+		return err
+	}
+	encrypted, err := sops.Encrypt(d.KeyServices(), rule, plain, formats.FormatForPath(path))
+	if err != nil {
+		return err
+	}
+	return writeSopsResult(ctx, path, encrypted, cmd.InPlace)
+}
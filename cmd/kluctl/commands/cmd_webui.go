@@ -14,6 +14,7 @@ import (
 type webuiCmd struct {
 	Run_  webuiRunCmd   `cmd:"run" help:"Run the Kluctl Webui"`
 	Build webuiBuildCmd `cmd:"build" help:"Build the static Kluctl Webui"`
+	Token webuiTokenCmd `cmd:"" help:"Manage Webui API tokens"`
 }
 
 func createResultStores(ctx context.Context, k8sContexts []string, allContexts bool, inCluster bool) ([]results.ResultStore, []*rest.Config, error) {
@@ -24,6 +24,8 @@ type webuiRunCmd struct {
 
 	OnlyApi bool `group:"misc" help:"Only serve API without the actual UI."`
 
+	AuditEventsNamespace string `group:"misc" help:"The namespace to write audit events to. Must match the namespace used by the controller to write command results." default:"kluctl-results"`
+
 	AuthSecretName string `group:"auth" help:"Specify the secret name for the secret used for internal encryption of tokens and cookies." default:"webui-secret"`
 	AuthSecretKey  string `group:"auth" help:"Specify the secret key for the secret used for internal encryption of tokens and cookies." default:"auth-secret"`
 
@@ -35,6 +37,11 @@ type webuiRunCmd struct {
 	AuthAdminRbacUser  string `group:"auth" help:"Specify the RBAC user to use for admin access." default:"kluctl-webui-admin"`
 	AuthViewerRbacUser string `group:"auth" help:"Specify the RBAC user to use for viewer access." default:"kluctl-webui-viewer"`
 
+	AuthRoleBindingsConfigMapName string `group:"auth" help:"Specify the name of the ConfigMap containing role bindings. Role bindings allow to grant fine-grained permissions to users and OIDC groups."`
+	AuthRoleBindingsConfigMapKey  string `group:"auth" help:"Specify the key inside the role bindings ConfigMap." default:"role-bindings.yaml"`
+
+	AuthApiTokensSecretName string `group:"auth" help:"Specify the name of the Secret that stores the API tokens. Pass an empty string to disable API tokens." default:"webui-api-tokens"`
+
 	AuthOidcIssuerUrl        string   `group:"auth" help:"Specify the OIDC provider's issuer URL."`
 	AuthOidcDisplayName      string   `group:"auth" help:"Specify the name of the OIDC provider to be displayed on the login page." default:"OpenID Connect"`
 	AuthOidcClientID         string   `group:"auth" help:"Specify the ClientID."`
@@ -74,6 +81,11 @@ func (cmd *webuiRunCmd) buildAuthConfig(ctx context.Context, c client.Client) (w
 	authConfig.AdminRbacUser = cmd.AuthAdminRbacUser
 	authConfig.ViewerRbacUser = cmd.AuthViewerRbacUser
 
+	authConfig.RoleBindingsConfigMapName = cmd.AuthRoleBindingsConfigMapName
+	authConfig.RoleBindingsConfigMapKey = cmd.AuthRoleBindingsConfigMapKey
+
+	authConfig.ApiTokensSecretName = cmd.AuthApiTokensSecretName
+
 	authConfig.OidcIssuerUrl = cmd.AuthOidcIssuerUrl
 	authConfig.OidcDisplayName = cmd.AuthOidcDisplayName
 	if cmd.AuthOidcIssuerUrl != "" {
@@ -151,7 +163,7 @@ func (cmd *webuiRunCmd) Run(ctx context.Context) error {
 	collector := results.NewResultsCollector(ctx, stores)
 	collector.Start()
 
-	server, err := webui.NewCommandResultsServer(ctx, collector, configs, cmd.ControllerNamespace, inClusterConfig, inClusterClient, authConfig, cmd.OnlyApi)
+	server, err := webui.NewCommandResultsServer(ctx, collector, configs, cmd.ControllerNamespace, cmd.AuditEventsNamespace, inClusterConfig, inClusterClient, authConfig, cmd.OnlyApi)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/webui"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+const webuiTokenManager = "kluctl-webui-token"
+
+type webuiTokenCmd struct {
+	Create webuiTokenCreateCmd `cmd:"" help:"Create a Webui API token"`
+	Revoke webuiTokenRevokeCmd `cmd:"" help:"Revoke a Webui API token"`
+	List   webuiTokenListCmd   `cmd:"" help:"List Webui API tokens"`
+}
+
+type webuiTokenCreateCmd struct {
+	args.WebuiApiTokenFlags
+
+	Name      string        `group:"misc" help:"Name of the token. Used to revoke the token later." required:"true"`
+	Username  string        `group:"misc" help:"The username to use for role bindings. Defaults to the token name."`
+	Admin     bool          `group:"misc" help:"Grant admin permissions to the token."`
+	Viewer    bool          `group:"misc" help:"Grant viewer permissions to the token."`
+	Group     []string      `group:"misc" help:"Groups to use for role bindings. Can be specified multiple times."`
+	ExpiresIn time.Duration `group:"misc" help:"Specify after which duration the token expires. Tokens never expire by default."`
+}
+
+type webuiTokenRevokeCmd struct {
+	args.WebuiApiTokenFlags
+
+	Name string `group:"misc" help:"Name of the token to revoke." required:"true"`
+}
+
+type webuiTokenListCmd struct {
+	args.WebuiApiTokenFlags
+	args.OutputFlags
+}
+
+type webuiTokenListEntry struct {
+	Name      string       `json:"name"`
+	Username  string       `json:"username"`
+	IsAdmin   bool         `json:"isAdmin,omitempty"`
+	IsViewer  bool         `json:"isViewer,omitempty"`
+	Groups    []string     `json:"groups,omitempty"`
+	CreatedAt metav1.Time  `json:"createdAt"`
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+func buildWebuiApiTokenClient(f args.WebuiApiTokenFlags) (client.Client, error) {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{
+			CurrentContext: f.Context,
+		})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.New(restConfig, client.Options{})
+}
+
+func (cmd *webuiTokenCreateCmd) Help() string {
+	return `This command will create a long-lived API token for the Kluctl Webui.
+
+The token is printed to stdout and can not be retrieved afterwards, as only a hash of the token is stored. Pass the
+token via the 'Authorization: Bearer <token>' header when calling the Webui API. Tokens without --admin or --viewer
+only get the permissions granted by role bindings for --username and --group.
+`
+}
+
+func (cmd *webuiTokenCreateCmd) Run(ctx context.Context) error {
+	if cmd.Admin && cmd.Viewer {
+		return fmt.Errorf("--admin and --viewer can not be combined")
+	}
+
+	var expiresIn *time.Duration
+	if cmd.ExpiresIn != 0 {
+		expiresIn = &cmd.ExpiresIn
+	}
+
+	token, t, err := webui.NewApiToken(cmd.Name, cmd.Username, expiresIn)
+	if err != nil {
+		return err
+	}
+	t.IsAdmin = cmd.Admin
+	t.IsViewer = cmd.Viewer
+	t.Groups = cmd.Group
+
+	c, err := buildWebuiApiTokenClient(cmd.WebuiApiTokenFlags)
+	if err != nil {
+		return err
+	}
+
+	err = webui.StoreApiToken(ctx, c, cmd.ControllerNamespace, cmd.SecretName, t, webuiTokenManager)
+	if err != nil {
+		return err
+	}
+
+	status.Infof(ctx, "Created API token %s", cmd.Name)
+	_, err = getStdout(ctx).WriteString(token + "\n")
+	return err
+}
+
+func (cmd *webuiTokenRevokeCmd) Help() string {
+	return `This command will revoke a Webui API token by removing it from the tokens Secret.
+
+The Webui caches tokens for a few seconds, meaning that it might take a short moment until the token becomes invalid.
+`
+}
+
+func (cmd *webuiTokenRevokeCmd) Run(ctx context.Context) error {
+	c, err := buildWebuiApiTokenClient(cmd.WebuiApiTokenFlags)
+	if err != nil {
+		return err
+	}
+
+	err = webui.RevokeApiToken(ctx, c, cmd.ControllerNamespace, cmd.SecretName, cmd.Name, webuiTokenManager)
+	if err != nil {
+		return err
+	}
+	status.Infof(ctx, "Revoked API token %s", cmd.Name)
+	return nil
+}
+
+func (cmd *webuiTokenListCmd) Help() string {
+	return `Outputs a yaml list with all Webui API tokens`
+}
+
+func (cmd *webuiTokenListCmd) Run(ctx context.Context) error {
+	c, err := buildWebuiApiTokenClient(cmd.WebuiApiTokenFlags)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := webui.ListApiTokens(ctx, c, cmd.ControllerNamespace, cmd.SecretName)
+	if err != nil {
+		return err
+	}
+
+	result := []webuiTokenListEntry{}
+	for _, t := range tokens {
+		result = append(result, webuiTokenListEntry{
+			Name:      t.Name,
+			Username:  t.Username,
+			IsAdmin:   t.IsAdmin,
+			IsViewer:  t.IsViewer,
+			Groups:    t.Groups,
+			CreatedAt: t.CreatedAt,
+			ExpiresAt: t.ExpiresAt,
+		})
+	}
+	return outputYamlResult(ctx, cmd.Output, result, false)
+}
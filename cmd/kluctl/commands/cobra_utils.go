@@ -67,9 +67,18 @@ func (c *rootCommand) buildCobraCmd(parent *commandAndGroups, cmdStruct interfac
 		},
 	}
 
+	posArgs, posArgsName := findPositionalArgs(cmdStruct)
+	if posArgsName != "" {
+		cg.cmd.Use = fmt.Sprintf("%s <%s>...", name, posArgsName)
+		cg.cmd.Args = cobra.MinimumNArgs(1)
+	}
+
 	runP, ok := cmdStruct.(runProvider)
 	if ok {
 		cg.cmd.RunE = func(cmd *cobra.Command, args []string) error {
+			if posArgsName != "" {
+				posArgs.Set(reflect.ValueOf(args))
+			}
 			return runP.Run(cmd.Context())
 		}
 	}
@@ -95,6 +104,22 @@ func (c *rootCommand) buildCobraCmd(parent *commandAndGroups, cmdStruct interfac
 	return cg, nil
 }
 
+// findPositionalArgs returns the []string field tagged with `arg:"<name>"`, which receives all positional arguments
+func findPositionalArgs(cmdStruct interface{}) (reflect.Value, string) {
+	v := reflect.ValueOf(cmdStruct).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if name, ok := f.Tag.Lookup("arg"); ok && f.Type == reflect.TypeOf([]string{}) {
+			return v.Field(i), name
+		}
+	}
+	return reflect.Value{}, ""
+}
+
 func (c *rootCommand) buildCobraSubCommands(cg *commandAndGroups, cmdStruct interface{}) error {
 	v := reflect.ValueOf(cmdStruct).Elem()
 	t := v.Type()
@@ -138,6 +163,9 @@ func (c *rootCommand) buildCobraArgs(cg *commandAndGroups, cmdStruct interface{}
 		if _, ok := f.Tag.Lookup("cmd"); ok {
 			continue
 		}
+		if _, ok := f.Tag.Lookup("arg"); ok {
+			continue
+		}
 
 		groupOverride2, _ := f.Tag.Lookup("groupOverride")
 		if groupOverride2 == "" {
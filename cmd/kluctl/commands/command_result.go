@@ -3,6 +3,7 @@ package commands
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/kluctl/kluctl/v2/cmd/kluctl/args"
 	"github.com/kluctl/kluctl/v2/pkg/diff"
@@ -29,6 +30,7 @@ func formatCommandResultText(cr *result.CommandResult, short bool) string {
 	var deletedObjects []k8s.ObjectRef
 	var orphanObjects []k8s.ObjectRef
 	var appliedHookObjects []k8s.ObjectRef
+	var skippedObjects []k8s.ObjectRef
 
 	for _, o := range cr.Objects {
 		if o.New {
@@ -46,6 +48,9 @@ func formatCommandResultText(cr *result.CommandResult, short bool) string {
 		if o.Hook {
 			appliedHookObjects = append(appliedHookObjects, o.Ref)
 		}
+		if o.Skipped {
+			skippedObjects = append(skippedObjects, o.Ref)
+		}
 	}
 
 	if len(newObjects) != 0 {
@@ -79,6 +84,10 @@ func formatCommandResultText(cr *result.CommandResult, short bool) string {
 		buf.WriteString("\nApplied hooks:\n")
 		prettyObjectRefs(buf, appliedHookObjects)
 	}
+	if len(skippedObjects) != 0 {
+		buf.WriteString("\nSkipped objects:\n")
+		prettyObjectRefs(buf, skippedObjects)
+	}
 	if len(orphanObjects) != 0 {
 		buf.WriteString("\nOrphan objects:\n")
 		prettyObjectRefs(buf, orphanObjects)
@@ -110,7 +119,10 @@ func prettyErrors(buf io.StringWriter, errors []result.DeploymentError) {
 
 func prettyChanges(buf io.StringWriter, ref k8s.ObjectRef, changes []result.Change) {
 	_, _ = buf.WriteString(fmt.Sprintf("Diff for object %s\n", ref.String()))
+	prettyChangesTable(buf, changes)
+}
 
+func prettyChangesTable(buf io.StringWriter, changes []result.Change) {
 	var t utils.PrettyTable
 	t.AddRow("Path", "Diff")
 
@@ -129,12 +141,22 @@ func formatCommandResultYaml(cr *result.CommandResult) (string, error) {
 	return b, nil
 }
 
+func formatJsonResult(o interface{}) (string, error) {
+	b, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
 func formatCommandResult(cr *result.CommandResult, format string, short bool) (string, error) {
 	switch format {
 	case "text":
 		return formatCommandResultText(cr, short), nil
 	case "yaml":
 		return formatCommandResultYaml(cr)
+	case "json":
+		return formatJsonResult(cr.ToCompacted())
 	default:
 		return "", fmt.Errorf("invalid format: %s", format)
 	}
@@ -151,6 +173,21 @@ func prettyValidationResults(buf io.StringWriter, results []result.ValidateResul
 	_, _ = buf.WriteString(s)
 }
 
+func prettyHelmTests(buf io.StringWriter, results []result.HelmTestResult) {
+	var t utils.PrettyTable
+	t.AddRow("Release", "Object", "Result")
+
+	for _, e := range results {
+		r := "Failed"
+		if e.Succeeded {
+			r = "Succeeded"
+		}
+		t.AddRow(e.ReleaseName, e.Ref.String(), r)
+	}
+	s := t.Render([]int{60})
+	_, _ = buf.WriteString(s)
+}
+
 func formatValidateResultText(vr *result.ValidateResult) string {
 	buf := bytes.NewBuffer(nil)
 
@@ -175,6 +212,14 @@ func formatValidateResultText(vr *result.ValidateResult) string {
 		prettyValidationResults(buf, vr.Results)
 	}
 
+	if len(vr.HelmTests) != 0 {
+		if buf.Len() != 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("Helm Tests:\n")
+		prettyHelmTests(buf, vr.HelmTests)
+	}
+
 	return buf.String()
 }
 
@@ -192,6 +237,8 @@ func formatValidateResult(vr *result.ValidateResult, format string) (string, err
 		return formatValidateResultText(vr), nil
 	case "yaml":
 		return formatValidateResultYaml(vr)
+	case "json":
+		return formatJsonResult(vr)
 	default:
 		return "", fmt.Errorf("invalid validation result format: %s", format)
 	}
@@ -237,7 +284,16 @@ func outputCommandResult(ctx *commandCtx, flags args.OutputFormatFlags, cr *resu
 	if writeToResultStore && ctx.resultStore != nil {
 		s := status.Start(ctx.ctx, "Writing command result")
 		defer s.Failed()
-		resultStoreErr = ctx.resultStore.WriteCommandResult(cr)
+		storeCr := cr
+		if flags.NoObfuscate {
+			// --no-obfuscate only affects the output, results are always written obfuscated into the result store
+			storeCr = cr.DeepCopy()
+			var obfuscator diff.Obfuscator
+			resultStoreErr = obfuscator.ObfuscateResult(storeCr)
+		}
+		if resultStoreErr == nil {
+			resultStoreErr = ctx.resultStore.WriteCommandResult(storeCr)
+		}
 		if resultStoreErr != nil {
 			s.FailedWithMessagef("Failed to write result to result store: %s", resultStoreErr.Error())
 		} else {
@@ -34,6 +34,7 @@ import (
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/tracing"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/version"
@@ -42,6 +43,7 @@ import (
 	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/klog/v2"
 )
 
@@ -52,20 +54,31 @@ type GlobalFlags struct {
 	NoUpdateCheck bool `group:"global" help:"Disable update check on startup"`
 	NoColor       bool `group:"global" help:"Disable colored output"`
 
+	StatusFormat string `group:"global" help:"Specify the format of status and progress output. Can be 'auto' or 'jsonl'. 'auto' uses interactive progress output on terminals and simple line based output otherwise. 'jsonl' emits one JSON encoded event per line." default:"auto"`
+
 	CpuProfile    string `group:"global" help:"Enable CPU profiling and write the result to the given path"`
 	GopsAgent     bool   `group:"global" help:"Start gops agent in the background"`
 	GopsAgentAddr string `group:"global" help:"Specify the address:port to use for the gops agent" default:"127.0.0.1:0"`
+
+	TraceExporter string `group:"global" help:"Enable OpenTelemetry tracing and export spans via the given exporter. Can be 'otlp-grpc', 'otlp-http', 'stderr' or 'file'. The OTLP exporters can also be configured via the standard OTEL_EXPORTER_OTLP_* environment variables."`
+	TraceEndpoint string `group:"global" help:"Override the endpoint (host:port) used by the OTLP trace exporters."`
+	TraceInsecure bool   `group:"global" help:"Disable TLS for the OTLP trace exporters."`
+	TraceFile     string `group:"global" help:"Specify the file to write spans to when using the 'file' trace exporter."`
 }
 
 type cli struct {
 	GlobalFlags
 
+	Adopt       adoptCmd       `cmd:"" help:"Adopt already existing objects that are not yet managed by the target"`
 	Delete      deleteCmd      `cmd:"" help:"Delete a target (or parts of it) from the corresponding cluster"`
 	Deploy      deployCmd      `cmd:"" help:"Deploys a target to the corresponding cluster"`
 	Diff        diffCmd        `cmd:"" help:"Perform a diff between the locally rendered target and the already deployed target"`
+	HelmImport  helmImportCmd  `cmd:"" help:"Compares Helm releases installed via the Helm CLI with the project and removes the Helm release bookkeeping"`
 	HelmPull    helmPullCmd    `cmd:"" help:"Recursively searches for 'helm-chart.yaml' files and pre-pulls the specified Helm charts"`
+	HelmTest    helmTestCmd    `cmd:"" help:"Runs the test hooks of all Helm Charts in the target"`
 	HelmUpdate  helmUpdateCmd  `cmd:"" help:"Recursively searches for 'helm-chart.yaml' files and checks for new available versions"`
 	ListImages  listImagesCmd  `cmd:"" help:"Renders the target and outputs all images used via 'images.get_image(...)"`
+	Images      imagesCmd      `cmd:"" help:"Image sub-commands"`
 	ListTargets listTargetsCmd `cmd:"" help:"Outputs a yaml list with all targets"`
 	PokeImages  pokeImagesCmd  `cmd:"" help:"Replace all images in target"`
 	Prune       pruneCmd       `cmd:"" help:"Searches the target cluster for prunable objects and deletes them"`
@@ -74,8 +87,10 @@ type cli struct {
 	Validate    validateCmd    `cmd:"" help:"Validates the already deployed deployment"`
 	Controller  controllerCmd  `cmd:"" help:"Kluctl controller sub-commands"`
 	Gitops      gitopsCmd      `cmd:"" help:"GitOps sub-commands"`
+	Results     resultsCmd     `cmd:"" help:"Command results sub-commands"`
 	Webui       webuiCmd       `cmd:"" help:"Kluctl Webui sub-commands"`
 	Oci         ociCmd         `cmd:"" help:"Oci sub-commands"`
+	Sops        sopsCmd        `cmd:"" help:"SOPS sub-commands"`
 
 	Version versionCmd `cmd:"" help:"Print kluctl version"`
 }
@@ -100,10 +115,15 @@ var origStderr = os.Stderr
 // we must determine isTerminal before we override os.Stderr
 var isTerminal = isatty.IsTerminal(os.Stderr.Fd())
 
-func initStatusHandlerAndPrompts(ctx context.Context, debug bool, noColor bool) context.Context {
+func initStatusHandlerAndPrompts(ctx context.Context, debug bool, noColor bool, statusFormat string) (context.Context, error) {
 	var sh status.StatusHandler
 	var pp prompts.PromptProvider
-	if !debug && isTerminal {
+	if statusFormat == "jsonl" {
+		sh = status.NewJsonLinesStatusHandler(origStderr, debug)
+		pp = &prompts.EventPromptProvider{In: os.Stdin}
+	} else if statusFormat != "auto" {
+		return ctx, fmt.Errorf("invalid status format %s", statusFormat)
+	} else if !debug && isTerminal {
 		sh = status.NewMultiLineStatusHandler(ctx, origStderr, isTerminal && !noColor, false)
 		pp = &prompts.StatusAndStdinPromptProvider{}
 	} else {
@@ -115,7 +135,7 @@ func initStatusHandlerAndPrompts(ctx context.Context, debug bool, noColor bool)
 	ctx = status.NewContext(ctx, sh)
 	ctx = prompts.NewContext(ctx, pp)
 
-	return ctx
+	return ctx, nil
 }
 
 func redirectLogsAndStderr(ctx context.Context) {
@@ -178,6 +198,46 @@ func setupProfiling(cpuProfile string) error {
 	return nil
 }
 
+var tracingShutdown func(ctx context.Context) error
+var rootSpan trace.Span
+
+func setupTracing(ctx context.Context, cmd *cobra.Command, flags *GlobalFlags) (context.Context, error) {
+	serviceName := "kluctl"
+	if cmd.CommandPath() == "kluctl controller run" {
+		serviceName = "kluctl-controller"
+	}
+
+	shutdown, err := tracing.Setup(ctx, tracing.Options{
+		Exporter:    flags.TraceExporter,
+		Endpoint:    flags.TraceEndpoint,
+		Insecure:    flags.TraceInsecure,
+		File:        flags.TraceFile,
+		Stderr:      origStderr,
+		ServiceName: serviceName,
+	})
+	if err != nil {
+		return ctx, fmt.Errorf("failed to setup tracing: %w", err)
+	}
+	tracingShutdown = shutdown
+
+	// long-running commands (e.g. the controller) create their own root spans
+	if flags.TraceExporter != "" && cmd.Name() != "run" {
+		ctx, rootSpan = tracing.Start(ctx, cmd.CommandPath())
+	}
+	return ctx, nil
+}
+
+func stopTracing(err error) {
+	if rootSpan != nil {
+		tracing.End(rootSpan, err)
+		rootSpan = nil
+	}
+	if tracingShutdown != nil {
+		_ = tracingShutdown(context.Background())
+		tracingShutdown = nil
+	}
+}
+
 type VersionCheckState struct {
 	LastVersionCheck time.Time `json:"lastVersionCheck"`
 }
@@ -272,9 +332,17 @@ func Main() {
 			return ctx, err
 		}
 
-		ctx = initStatusHandlerAndPrompts(ctxIn, flags.Debug, flags.NoColor)
+		ctx, err = initStatusHandlerAndPrompts(ctxIn, flags.Debug, flags.NoColor, flags.StatusFormat)
+		if err != nil {
+			return ctx, err
+		}
 		didSetupStatusHandler = true
 
+		ctx, err = setupTracing(ctx, cmd, flags)
+		if err != nil {
+			return ctx, err
+		}
+
 		if cmd.Parent() == nil || (cmd.Name() != "run" && cmd.Parent().Name() != "controller") {
 			redirectLogsAndStderr(ctx)
 		}
@@ -287,6 +355,8 @@ func Main() {
 		return ctx, nil
 	})
 
+	stopTracing(err)
+
 	if cpuProfileFile != nil {
 		pprof.StopCPUProfile()
 		_ = cpuProfileFile.Close()
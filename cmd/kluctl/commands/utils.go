@@ -22,6 +22,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -125,6 +126,7 @@ type projectTargetCommandArgs struct {
 	dryRunArgs           *args.DryRunFlags
 	renderOutputDirFlags args.RenderOutputDirFlags
 	commandResultFlags   *args.CommandResultFlags
+	k8sRateLimitFlags    args.K8sRateLimitFlags
 
 	internalDeploy    bool
 	forSeal           bool
@@ -193,6 +195,9 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 		OciAuthProvider:    p.LoadArgs.OciAuthProvider,
 		HelmAuthProvider:   p.LoadArgs.HelmAuthProvider,
 		RenderOutputDir:    renderOutputDir,
+
+		HelmRequireVerification:    args.helmCredentials.HelmRequireVerification,
+		HelmAllowExecPostRenderers: args.helmCredentials.HelmAllowExecPostRenderers,
 	}
 
 	commandResultId := uuid.NewString()
@@ -211,14 +216,15 @@ func withProjectTargetCommandContext(ctx context.Context, args projectTargetComm
 		}
 
 		s := status.Start(ctx, fmt.Sprintf("Initializing k8s client"))
-		k, err = k8s.NewK8sCluster(ctx, clientConfig, discovery, mapper, targetParams.DryRun)
+		k8sConfig := k8s.WithRateLimit(clientConfig, args.k8sRateLimitFlags.K8sQps, args.k8sRateLimitFlags.K8sBurst)
+		k, err = k8s.NewK8sCluster(ctx, k8sConfig, discovery, mapper, targetParams.DryRun)
 		if err != nil {
 			s.Failed()
 			return err
 		}
 		s.Success()
 
-		resultStore, err = buildResultStoreRW(ctx, clientConfig, mapper, args.commandResultFlags, false)
+		resultStore, err = buildResultStoreRW(ctx, clientConfig, mapper, args.commandResultFlags, 0, false)
 		if err != nil {
 			return err
 		}
@@ -285,7 +291,7 @@ func buildResultStoreRO(ctx context.Context, restConfig *rest.Config, mapper met
 		return nil, err
 	}
 
-	resultStore, err := results.NewResultStoreSecrets(ctx, restConfig, c, false, flags.CommandResultNamespace, 0, 0)
+	resultStore, err := results.NewResultStoreSecrets(ctx, restConfig, c, false, flags.CommandResultNamespace, results.RetentionOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -293,7 +299,7 @@ func buildResultStoreRO(ctx context.Context, restConfig *rest.Config, mapper met
 	return resultStore, nil
 }
 
-func buildResultStoreRW(ctx context.Context, restConfig *rest.Config, mapper meta.RESTMapper, flags *args.CommandResultFlags, startCleanup bool) (results.ResultStore, error) {
+func buildResultStoreRW(ctx context.Context, restConfig *rest.Config, mapper meta.RESTMapper, flags *args.CommandResultFlags, keepAuditEventsCount int, startCleanup bool) (results.ResultStore, error) {
 	if flags == nil || !flags.WriteCommandResult {
 		return nil, nil
 	}
@@ -305,7 +311,23 @@ func buildResultStoreRW(ctx context.Context, restConfig *rest.Config, mapper met
 		return nil, err
 	}
 
-	resultStore, err := results.NewResultStoreSecrets(ctx, restConfig, c, true, flags.CommandResultNamespace, flags.KeepCommandResultsCount, flags.KeepValidateResultsCount)
+	retention := results.RetentionOptions{
+		KeepCommandResultsCount:  flags.KeepCommandResultsCount,
+		KeepValidateResultsCount: flags.KeepValidateResultsCount,
+		KeepAuditEventsCount:     keepAuditEventsCount,
+		KeepCommandResultsAge:    flags.KeepCommandResultsAge,
+		KeepValidateResultsAge:   flags.KeepValidateResultsAge,
+		KeepLastSuccessfulDeploy: flags.KeepLastSuccessfulDeploy,
+	}
+	if flags.MaxResultsSize != "" {
+		q, err := resource.ParseQuantity(flags.MaxResultsSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --max-results-size: %w", err)
+		}
+		retention.MaxResultsSize = q.Value()
+	}
+
+	resultStore, err := results.NewResultStoreSecrets(ctx, restConfig, c, true, flags.CommandResultNamespace, retention)
 	if err != nil {
 		return nil, err
 	}
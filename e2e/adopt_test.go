@@ -0,0 +1,66 @@
+package e2e
+
+import (
+	"context"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+func TestAdopt(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm1", map[string]string{
+		"k1": "v1",
+	}, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cm1",
+			Namespace: p.TestSlug(),
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "Helm",
+			},
+			Annotations: map[string]string{
+				"meta.helm.sh/release-name":      "cm1",
+				"meta.helm.sh/release-namespace": p.TestSlug(),
+			},
+		},
+		Data: map[string]string{
+			"k1": "v1",
+		},
+	}
+	err := k.Client.Create(context.TODO(), cm, client.FieldOwner("helm"))
+	assert.NoError(t, err)
+
+	p.KluctlMust(t, "adopt", "--yes", "-t", "test", "--dry-run")
+	o := assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assert.Nil(t, o.GetK8sLabel("kluctl.io/discriminator"))
+
+	p.KluctlMust(t, "adopt", "--yes", "-t", "test", "--remove-helm-metadata")
+	o = assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertNestedFieldEquals(t, o, p.Discriminator("test"), "metadata", "labels", "kluctl.io/discriminator")
+	assert.Nil(t, o.GetK8sLabel("app.kubernetes.io/managed-by"))
+	assert.Nil(t, o.GetK8sAnnotation("meta.helm.sh/release-name"))
+
+	var managers []string
+	for _, mf := range o.ToUnstructured().GetManagedFields() {
+		managers = append(managers, mf.Manager)
+	}
+	assert.Contains(t, managers, "kluctl")
+	assert.NotContains(t, managers, "helm")
+}
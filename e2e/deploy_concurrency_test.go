@@ -0,0 +1,180 @@
+package e2e
+
+import (
+	"fmt"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// addConcurrencyTestDeployments adds two deployment items with multiple objects each, followed by a barrier and
+// another deployment item. It returns the names of the objects before and after the barrier.
+func addConcurrencyTestDeployments(p *test_project.TestProject) ([]string, []string) {
+	var before []string
+	for _, d := range []string{"a1", "a2"} {
+		var resources []test_project.KustomizeResource
+		for i := 0; i < 5; i++ {
+			name := fmt.Sprintf("%s-cm%d", d, i)
+			resources = append(resources, test_project.KustomizeResource{
+				Name: fmt.Sprintf("configmap-%s.yml", name),
+				Content: createConfigMapObject(nil, resourceOpts{
+					name:      name,
+					namespace: p.TestSlug(),
+				}),
+			})
+			before = append(before, name)
+		}
+		p.AddKustomizeDeployment(d, resources, nil)
+	}
+
+	p.AddDeploymentItem("", uo.FromMap(map[string]interface{}{
+		"barrier": true,
+	}))
+
+	addConfigMapDeployment(p, "b", nil, resourceOpts{
+		name:      "b-cm",
+		namespace: p.TestSlug(),
+	})
+
+	return before, []string{"b-cm"}
+}
+
+func getResourceVersion(t *testing.T, o *uo.UnstructuredObject) int64 {
+	rv, err := strconv.ParseInt(o.GetK8sResourceVersion(), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rv
+}
+
+// assertConcurrencyTestDeployments asserts that all objects exist and that all objects before the barrier were
+// applied before the objects after the barrier. The API server's resourceVersions are used to determine the order.
+func assertConcurrencyTestDeployments(t *testing.T, k *test_utils.EnvTestCluster, namespace string, before []string, after []string) {
+	var maxBefore int64
+	for _, name := range before {
+		rv := getResourceVersion(t, assertConfigMapExists(t, k, namespace, name))
+		if rv > maxBefore {
+			maxBefore = rv
+		}
+	}
+	for _, name := range after {
+		rv := getResourceVersion(t, assertConfigMapExists(t, k, namespace, name))
+		assert.Greater(t, rv, maxBefore, "%s was applied before the barrier got reached", name)
+	}
+}
+
+func TestDeployConcurrency(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	before, after := addConcurrencyTestDeployments(p)
+
+	resultPath := filepath.Join(t.TempDir(), "result.json")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test",
+		"--apply-concurrency", "2",
+		"--apply-item-concurrency", "4",
+		"--k8s-qps", "50",
+		"--k8s-burst", "100",
+		"-o", "json="+resultPath)
+
+	assertConcurrencyTestDeployments(t, k, p.TestSlug(), before, after)
+
+	cr := readCommandResultFile(t, resultPath)
+	assert.Empty(t, cr.Errors)
+	summary := cr.BuildSummary()
+	assert.Equal(t, len(before)+len(after), summary.NewObjects)
+	assert.Equal(t, len(before)+len(after), summary.AppliedObjects)
+
+	// re-deploying with the same settings must not lead to any changes
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test",
+		"--apply-item-concurrency", "4",
+		"-o", "json="+resultPath)
+
+	cr = readCommandResultFile(t, resultPath)
+	assert.Empty(t, cr.Errors)
+	summary = cr.BuildSummary()
+	assert.Equal(t, 0, summary.NewObjects)
+	assert.Equal(t, 0, summary.ChangedObjects)
+	assert.Equal(t, len(before)+len(after), summary.AppliedObjects)
+}
+
+func TestDeployItemConcurrencyDependencies(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	p.UpdateTarget("test", nil)
+
+	ns := p.TestSlug() + "-ns"
+	group := p.TestSlug() + ".kluctl.io"
+
+	var resources []test_project.KustomizeResource
+	resources = append(resources, test_project.KustomizeResource{
+		Name:    "namespace.yml",
+		Content: createCoreV1Object("Namespace", resourceOpts{name: ns}),
+	})
+	resources = append(resources, test_project.KustomizeResource{
+		Name: "crd.yml",
+		Content: uo.FromStringMust(fmt.Sprintf(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: tests.%[1]s
+spec:
+  group: %[1]s
+  names:
+    kind: Test
+    listKind: TestList
+    plural: tests
+    singular: test
+  scope: Namespaced
+  versions:
+  - name: v1
+    served: true
+    storage: true
+    schema:
+      openAPIV3Schema:
+        type: object
+        x-kubernetes-preserve-unknown-fields: true
+`, group)),
+	})
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("cm%d", i)
+		resources = append(resources, test_project.KustomizeResource{
+			Name:    fmt.Sprintf("configmap-%s.yml", name),
+			Content: createConfigMapObject(nil, resourceOpts{name: name, namespace: ns}),
+		})
+		resources = append(resources, test_project.KustomizeResource{
+			Name: fmt.Sprintf("test-%d.yml", i),
+			Content: uo.FromStringMust(fmt.Sprintf(`
+apiVersion: %s/v1
+kind: Test
+metadata:
+  name: test%d
+  namespace: %s
+`, group, i, ns)),
+		})
+	}
+	p.AddKustomizeDeployment("d", resources, nil)
+
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--apply-item-concurrency", "8")
+
+	for i := 0; i < 5; i++ {
+		assertConfigMapExists(t, k, ns, fmt.Sprintf("cm%d", i))
+		assertObjectExists(t, k, schema.GroupVersionResource{Group: group, Version: "v1", Resource: "tests"}, ns, fmt.Sprintf("test%d", i))
+	}
+}
@@ -0,0 +1,164 @@
+package e2e
+
+import (
+	"encoding/json"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readCommandResultFile(t *testing.T, path string) *result.CommandResult {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cr result.CommandResult
+	err = json.Unmarshal(b, &cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &cr
+}
+
+func findResultObject(cr *result.CommandResult, name string) *result.ResultObject {
+	for i := range cr.Objects {
+		if cr.Objects[i].Ref.Name == name {
+			return &cr.Objects[i]
+		}
+	}
+	return nil
+}
+
+func TestDeployInteractive(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	for _, name := range []string{"cm1", "cm2", "cm3"} {
+		addConfigMapDeployment(p, name, map[string]string{"k": "v1"}, resourceOpts{
+			name:      name,
+			namespace: p.TestSlug(),
+		})
+	}
+
+	pp := &test_utils.TestPromptProvider{
+		Answer: func(message string) (string, error) {
+			if strings.Contains(message, "/cm2?") {
+				return "s", nil
+			}
+			return "a", nil
+		},
+	}
+
+	resultPath := filepath.Join(t.TempDir(), "result.json")
+	_, _, err := p.KluctlWithPrompts(t, pp, "deploy", "-t", "test", "--interactive", "-o", "json="+resultPath)
+	assert.NoError(t, err)
+	assert.Len(t, pp.Prompts(), 3)
+
+	assertConfigMapExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm2")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm3")
+
+	cr := readCommandResultFile(t, resultPath)
+	assert.Equal(t, 2, cr.BuildSummary().AppliedObjects)
+
+	o := findResultObject(cr, "cm2")
+	if assert.NotNil(t, o) {
+		assert.True(t, o.Skipped)
+		assert.Nil(t, o.Applied)
+	}
+	for _, name := range []string{"cm1", "cm3"} {
+		o = findResultObject(cr, name)
+		if assert.NotNil(t, o) {
+			assert.False(t, o.Skipped)
+			assert.NotNil(t, o.Applied)
+		}
+	}
+
+	// changed objects are prompted as well, unchanged objects are not
+	for _, name := range []string{"cm1", "cm3"} {
+		p.UpdateYaml(name+"/configmap-"+name+".yml", func(o *uo.UnstructuredObject) error {
+			_ = o.SetNestedField("v2", "data", "k")
+			return nil
+		}, "")
+	}
+	pp = &test_utils.TestPromptProvider{
+		Answer: func(message string) (string, error) {
+			if strings.Contains(message, "/cm3?") {
+				return "s", nil
+			}
+			return "a", nil
+		},
+	}
+	_, _, err = p.KluctlWithPrompts(t, pp, "deploy", "-t", "test", "--interactive", "-o", "json="+resultPath)
+	assert.NoError(t, err)
+	assert.Len(t, pp.Prompts(), 3)
+
+	assertConfigMapExists(t, k, p.TestSlug(), "cm2")
+	assertNestedFieldEquals(t, assertConfigMapExists(t, k, p.TestSlug(), "cm1"), "v2", "data", "k")
+	assertNestedFieldEquals(t, assertConfigMapExists(t, k, p.TestSlug(), "cm3"), "v1", "data", "k")
+
+	cr = readCommandResultFile(t, resultPath)
+	assert.Equal(t, 2, cr.BuildSummary().AppliedObjects)
+	assert.True(t, findResultObject(cr, "cm3").Skipped)
+}
+
+func TestDeployInteractiveQuit(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	for _, name := range []string{"cm1", "cm2"} {
+		addConfigMapDeployment(p, name, nil, resourceOpts{
+			name:      name,
+			namespace: p.TestSlug(),
+		})
+	}
+
+	pp := &test_utils.TestPromptProvider{
+		Answer: func(message string) (string, error) {
+			if strings.Contains(message, "/cm2?") {
+				return "q", nil
+			}
+			return "a", nil
+		},
+	}
+
+	_, _, err := p.KluctlWithPrompts(t, pp, "deploy", "-t", "test", "--interactive")
+	assert.Error(t, err)
+	assert.Len(t, pp.Prompts(), 2)
+
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm1")
+	assertConfigMapNotExists(t, k, p.TestSlug(), "cm2")
+}
+
+func TestDeployInteractiveInvalidFlags(t *testing.T) {
+	t.Parallel()
+
+	p := test_utils.NewTestProject(t)
+
+	p.UpdateTarget("test", nil)
+
+	_, _, err := p.Kluctl(t, "deploy", "-t", "test", "--interactive", "--yes")
+	assert.ErrorContains(t, err, "--interactive can not be combined with --yes")
+
+	_, _, err = p.Kluctl(t, "deploy", "-t", "test", "--interactive", "--dry-run")
+	assert.ErrorContains(t, err, "--interactive can not be combined with --dry-run")
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/results"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	corev1 "k8s.io/api/core/v1"
@@ -424,3 +426,71 @@ func (suite *GitOpsManualRequestsSuite) TestOverrides() {
 		assertNestedFieldEquals(suite.T(), cm1, "via_target", "data", "k3")
 	})
 }
+
+func (suite *GitOpsManualRequestsSuite) TestAuditEvents() {
+	g := NewWithT(suite.T())
+
+	p := test_project.NewTestProject(suite.T())
+	createNamespace(suite.T(), suite.k, p.TestSlug())
+
+	p.UpdateTarget("target1", nil)
+	addConfigMapDeployment(p, "d1", nil, resourceOpts{
+		name:      "cm1",
+		namespace: p.TestSlug(),
+	})
+
+	key := suite.createKluctlDeployment(p, "target1", nil)
+	suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+
+	// user and source are claimed by whoever sets the annotation, so they must not end up as the audit event's user
+	requestValue := "forged-" + p.TestSlug()
+	kd := suite.getKluctlDeployment(key)
+	patch := client.MergeFrom(kd.DeepCopy())
+	metav1.SetMetaDataAnnotation(&kd.ObjectMeta, kluctlv1.KluctlRequestReconcileAnnotation, yaml.WriteJsonStringMust(&kluctlv1.ManualRequest{
+		RequestValue: requestValue,
+		RequestedBy:  "admin",
+		Source:       result.AuditSourceWebui,
+	}))
+	err := suite.k.Client.Patch(context.TODO(), kd, patch, client.FieldOwner("forger"))
+	g.Expect(err).To(Succeed())
+
+	g.Eventually(func() bool {
+		kd = suite.getKluctlDeployment(key)
+		rr := kd.Status.ReconcileRequestResult
+		return rr != nil && rr.Request.RequestValue == requestValue && rr.EndTime != nil
+	}, timeout, time.Second).Should(BeTrue())
+	assert.Equal(suite.T(), "forger", kd.Status.ReconcileRequestResult.RequestFieldManager)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rs, err := results.NewResultStoreSecrets(ctx, suite.k.RESTConfig(), suite.k.Client, false, "", results.RetentionOptions{})
+	g.Expect(err).To(Succeed())
+
+	var e *result.AuditEvent
+	g.Eventually(func() bool {
+		events, err := rs.ListAuditEvents(results.ListAuditEventsOptions{
+			KluctlDeployment: &result.KluctlDeploymentInfo{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+			},
+		})
+		if err != nil {
+			return false
+		}
+		for _, x := range events {
+			if x.RequestValue == requestValue {
+				e = &x
+				return true
+			}
+		}
+		return false
+	}, timeout, time.Second).Should(BeTrue())
+
+	assert.Equal(suite.T(), "", e.User)
+	assert.Equal(suite.T(), result.AuditSourceAnnotation, e.Source)
+	assert.Equal(suite.T(), result.AuditActionReconcile, e.Action)
+	assert.Equal(suite.T(), "admin", e.ClaimedUser)
+	assert.Equal(suite.T(), result.AuditSourceWebui, e.ClaimedSource)
+	assert.Equal(suite.T(), "forger", e.FieldManager)
+}
@@ -95,3 +95,31 @@ func (suite *GitOpsMiscSuite) TestOciSourceWithPath() {
 		assert.Equal(suite.T(), v1.ConditionTrue, status.Status)
 	})
 }
+
+func (suite *GitOpsMiscSuite) TestApplyConcurrency() {
+	p := test_project.NewTestProject(suite.T())
+	p.AddExtraArgs("--controller-namespace", suite.gitopsNamespace+"-system")
+	createNamespace(suite.T(), suite.k, p.TestSlug())
+
+	p.UpdateTarget("target1", nil)
+	before, after := addConcurrencyTestDeployments(p)
+
+	key := suite.createKluctlDeployment2(p, "target1", nil, func(kd *kluctlv1.KluctlDeployment) {
+		kd.Spec.ApplyConcurrency = 2
+		kd.Spec.ApplyItemConcurrency = 4
+		kd.Spec.K8sQps = 50
+		kd.Spec.K8sBurst = 100
+	})
+
+	kd := suite.waitForCommit(key, getHeadRevision(suite.T(), p))
+	status := suite.getReadiness(kd)
+	assert.Equal(suite.T(), v1.ConditionTrue, status.Status)
+
+	assertConcurrencyTestDeployments(suite.T(), suite.k, p.TestSlug(), before, after)
+
+	ldr, err := kd.Status.GetLastDeployResult()
+	assert.NoError(suite.T(), err)
+	cr := suite.getCommandResult(ldr.Id)
+	assert.Empty(suite.T(), cr.Errors)
+	assert.Equal(suite.T(), len(before)+len(after), cr.BuildSummary().AppliedObjects)
+}
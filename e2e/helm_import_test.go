@@ -0,0 +1,162 @@
+package e2e
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/stretchr/testify/assert"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"path/filepath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"testing"
+)
+
+func createHelmReleaseSecret(t *testing.T, namespace string, name string, chartName string, chartVersion string, values map[string]any, manifest string) {
+	rel := release.Release{
+		Name:      name,
+		Namespace: namespace,
+		Version:   1,
+		Info: &release.Info{
+			Status: release.StatusDeployed,
+		},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{
+				Name:    chartName,
+				Version: chartVersion,
+			},
+		},
+		Config:   values,
+		Manifest: manifest,
+	}
+	b, err := json.Marshal(&rel)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err = w.Write(b)
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sh.helm.release.v1." + name + ".v1",
+			Namespace: namespace,
+			Labels: map[string]string{
+				"owner":   "helm",
+				"name":    name,
+				"status":  "deployed",
+				"version": "1",
+			},
+		},
+		Type: "helm.sh/release.v1",
+		Data: map[string][]byte{
+			"release": []byte(base64.StdEncoding.EncodeToString(buf.Bytes())),
+		},
+	}
+	err = defaultCluster1.Client.Create(context.TODO(), secret)
+	assert.NoError(t, err)
+}
+
+func TestHelmImport(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+	p.AddHelmDeployment("helm1", "test-chart1", "", "", "test-helm-1", p.TestSlug(), map[string]any{
+		"data": map[string]any{
+			"a": "x",
+		},
+	})
+	test_utils.CreateHelmDir(t, "test-chart1", "0.1.0", filepath.Join(p.LocalProjectDir(), "helm1/test-chart1"))
+
+	createHelmReleaseSecret(t, p.TestSlug(), "test-helm-1", "test-chart1", "0.0.1", map[string]any{
+		"data": map[string]any{
+			"a": "y",
+		},
+	}, `---
+# Source: test-chart1/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-helm-1-test-chart1
+`)
+
+	// simulate an object installed by Helm
+	err := k.Client.Create(context.TODO(), &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-helm-1-test-chart1",
+			Namespace: p.TestSlug(),
+		},
+	}, client.FieldOwner("helm"))
+	assert.NoError(t, err)
+
+	_, stderr := p.KluctlMust(t, "helm-import", "--yes", "-t", "test")
+	assert.Contains(t, stderr, "chart version differs: installed=0.0.1, project=0.1.0")
+	assert.Contains(t, stderr, "run 'kluctl adopt' first")
+
+	p.KluctlMust(t, "adopt", "--yes", "-t", "test")
+
+	p.KluctlMust(t, "helm-import", "--yes", "-t", "test", "--dry-run")
+	assertSecretExists(t, k, p.TestSlug(), "sh.helm.release.v1.test-helm-1.v1")
+
+	p.KluctlMust(t, "helm-import", "--yes", "-t", "test")
+	err = k.Client.Get(context.TODO(), client.ObjectKey{Name: "sh.helm.release.v1.test-helm-1.v1", Namespace: p.TestSlug()}, &corev1.Secret{})
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func TestHelmImportManifestOnlyObjects(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+	p.AddHelmDeployment("helm1", "test-chart1", "", "", "test-helm-1", p.TestSlug(), nil)
+	test_utils.CreateHelmDir(t, "test-chart1", "0.1.0", filepath.Join(p.LocalProjectDir(), "helm1/test-chart1"))
+
+	// the installed release contains an object that is not part of the newer chart version anymore
+	createHelmReleaseSecret(t, p.TestSlug(), "test-helm-1", "test-chart1", "0.0.1", nil, `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-helm-1-test-chart1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-helm-1-removed
+`)
+
+	for _, name := range []string{"test-helm-1-test-chart1", "test-helm-1-removed"} {
+		err := k.Client.Create(context.TODO(), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: p.TestSlug(),
+			},
+		}, client.FieldOwner("helm"))
+		assert.NoError(t, err)
+	}
+
+	p.KluctlMust(t, "adopt", "--yes", "-t", "test")
+
+	_, stderr := p.KluctlMust(t, "helm-import", "--yes", "-t", "test")
+	assert.Contains(t, stderr, "1 objects of the installed release are not rendered by the project and would be orphaned")
+	assert.Contains(t, stderr, p.TestSlug()+"/ConfigMap/test-helm-1-removed")
+	assertSecretExists(t, k, p.TestSlug(), "sh.helm.release.v1.test-helm-1.v1")
+}
@@ -2,22 +2,33 @@ package e2e
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
 	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"math/rand"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 type helmTestCase struct {
@@ -689,6 +700,167 @@ func TestHelmUpdateConstraintsOci(t *testing.T) {
 	testHelmUpdateConstraints(t, true)
 }
 
+func TestHelmUpdateValuesCheck(t *testing.T) {
+	t.Parallel()
+
+	p := test_project.NewTestProject(t)
+
+	schema := `{"type": "object", "properties": {"data": {"type": "object", "properties": {"a": {"type": "integer"}}}}}`
+	repo := &test_utils.TestHelmRepo{
+		Charts: []test_utils.RepoChart{
+			{ChartName: "test-chart1", Version: "0.1.0", Values: map[string]any{
+				"data": map[string]any{"a": "v1", "b": "v2", "c": "v3"},
+			}},
+			{ChartName: "test-chart1", Version: "0.2.0", Values: map[string]any{
+				"data": map[string]any{"a": 1, "b": "v2-new"},
+			}, ValuesSchema: schema},
+		},
+	}
+	repo.Start(t)
+
+	p.UpdateTarget("test", nil)
+	p.AddHelmDeployment("helm1", repo.URL.String(), "test-chart1", "0.1.0", "test-helm1", p.TestSlug(), map[string]any{
+		"data": map[string]any{"a": "x", "c": "y"},
+	})
+	p.KluctlMust(t, "helm-pull")
+
+	_, stderr := p.KluctlMust(t, "helm-update")
+	assert.Contains(t, stderr, "helm1: Chart test-chart1 has new version 0.2.0 available")
+	assert.NotContains(t, stderr, "helm-values.yaml does not validate")
+
+	_, stderr = p.KluctlMust(t, "helm-update", "--check-values")
+	assert.Contains(t, stderr, "helm1: helm-values.yaml does not validate against the values schema of version 0.2.0")
+	assert.Contains(t, stderr, "helm1: helm-values.yaml contains values that were removed from the default values of version 0.2.0: data[\"c\"]")
+	assert.Contains(t, stderr, "helm1: Default values changed from version 0.1.0 to 0.2.0:")
+	assert.Contains(t, stderr, "-v2\n")
+	assert.Contains(t, stderr, "+v2-new\n")
+
+	_, stderr, err := p.Kluctl(t, "helm-update", "--upgrade", "--require-valid-values")
+	assert.ErrorContains(t, err, "some Helm Charts were not upgraded due to invalid values")
+	assert.Contains(t, stderr, "helm1: Skipped upgrade to version 0.2.0 due to invalid values")
+	v, _, _ := p.GetYaml("helm1/helm-chart.yaml").GetNestedString("helmChart", "chartVersion")
+	assert.Equal(t, "0.1.0", v)
+
+	p.UpdateYaml("helm1/helm-values.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField(1, "data", "a")
+		return nil
+	}, "")
+
+	p.KluctlMust(t, "helm-update", "--upgrade", "--require-valid-values")
+	v, _, _ = p.GetYaml("helm1/helm-chart.yaml").GetNestedString("helmChart", "chartVersion")
+	assert.Equal(t, "0.2.0", v)
+}
+
+func TestHelmUpdatePushBranches(t *testing.T) {
+	t.Parallel()
+
+	p := test_project.NewTestProject(t)
+
+	repo := &test_utils.TestHelmRepo{
+		Charts: []test_utils.RepoChart{
+			{ChartName: "test-chart1", Version: "0.1.0"},
+			{ChartName: "test-chart1", Version: "0.2.0", Values: map[string]any{
+				"data": map[string]any{"a": "v1-new", "b": "v2"},
+			}},
+		},
+	}
+	repo.Start(t)
+
+	p.UpdateTarget("test", nil)
+	p.AddHelmDeployment("helm1", repo.URL.String(), "test-chart1", "0.1.0", "test-helm1", p.TestSlug(), nil)
+	p.KluctlMust(t, "helm-pull")
+	p.GitServer().CommitFiles("kluctl-project", []string{".helm-charts"}, false, "helm-pull")
+
+	var prMutex sync.Mutex
+	var prRequests []map[string]any
+	prServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		prMutex.Lock()
+		defer prMutex.Unlock()
+		if r.Method == "GET" {
+			_, _ = w.Write([]byte("[]"))
+			return
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		body["path"] = r.URL.Path
+		body["auth"] = r.Header.Get("Authorization")
+		prRequests = append(prRequests, body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 1, "html_url": "http://example.com/pr/1"}`))
+	}))
+	t.Cleanup(prServer.Close)
+
+	cloneDir := t.TempDir()
+	r, err := git.PlainClone(cloneDir, false, &git.CloneOptions{URL: p.GitUrl()})
+	assert.NoError(t, err)
+	cfg, err := r.Config()
+	assert.NoError(t, err)
+	cfg.User.Name = "Test User"
+	cfg.User.Email = "no@mail.com"
+	assert.NoError(t, r.SetConfig(cfg))
+
+	_, _, err = test_project.KluctlExecute(t, context.Background(), t.Log, "helm-update", "--project-dir", cloneDir, "--create-pr")
+	assert.ErrorContains(t, err, "--create-pr can only be used together with --push-branches")
+
+	_, stderr, err := test_project.KluctlExecute(t, context.Background(), t.Log, "helm-update", "--project-dir", cloneDir,
+		"--push-branches", "--create-pr", "--pr-api-url", prServer.URL, "--pr-repo", "my-org/my-repo", "--pr-token", "my-token")
+	assert.NoError(t, err)
+	assert.Contains(t, stderr, "Created pull request http://example.com/pr/1")
+
+	// the clone must be back on the original branch
+	head, err := r.Head()
+	assert.NoError(t, err)
+	assert.Equal(t, "refs/heads/master", head.Name().String())
+	c, err := uo.FromFile(filepath.Join(cloneDir, "helm1/helm-chart.yaml"))
+	assert.NoError(t, err)
+	v, _, _ := c.GetNestedString("helmChart", "chartVersion")
+	assert.Equal(t, "0.1.0", v)
+
+	// the pushed branch must contain the upgrade
+	branch, err := p.GetGitRepo().Reference(plumbing.NewBranchReferenceName("kluctl-helm-update/test-chart1-0.2.0"), true)
+	assert.NoError(t, err)
+	commit, err := p.GetGitRepo().CommitObject(branch.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, "Updated helm chart test-chart1 from version 0.1.0 to version 0.2.0", commit.Message)
+	f, err := commit.File("helm1/helm-chart.yaml")
+	assert.NoError(t, err)
+	s, err := f.Contents()
+	assert.NoError(t, err)
+	assert.Contains(t, s, "chartVersion: 0.2.0")
+	_, err = commit.File(".helm-charts/http_" + repo.URL.Port() + "_127.0.0.1/test-chart1/0.2.0/Chart.yaml")
+	assert.NoError(t, err)
+
+	assert.Len(t, prRequests, 1)
+	pr := prRequests[0]
+	assert.Equal(t, "/repos/my-org/my-repo/pulls", pr["path"])
+	assert.Equal(t, "Bearer my-token", pr["auth"])
+	assert.Equal(t, "kluctl-helm-update/test-chart1-0.2.0", pr["head"])
+	assert.Equal(t, "master", pr["base"])
+	assert.Equal(t, "Update helm chart test-chart1 to version 0.2.0", pr["title"])
+	assert.Contains(t, pr["body"], "from version `0.1.0` to `0.2.0`")
+	assert.Contains(t, pr["body"], "Changed `ConfigMap/test-helm1-test-chart1`")
+	assert.Contains(t, pr["body"], "-v1\n+v1-new\n")
+
+	// re-running re-creates the already pushed branch
+	_, stderr, err = test_project.KluctlExecute(t, context.Background(), t.Log, "helm-update", "--project-dir", cloneDir, "--push-branches")
+	assert.NoError(t, err)
+	assert.Contains(t, stderr, "Re-creating branch kluctl-helm-update/test-chart1-0.2.0")
+
+	// unpushed local commits on the branch must not get lost
+	wt, err := r.Worktree()
+	assert.NoError(t, err)
+	assert.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("kluctl-helm-update/test-chart1-0.2.0")}))
+	assert.NoError(t, os.WriteFile(filepath.Join(cloneDir, "local.txt"), []byte("local"), 0o600))
+	_, err = wt.Add("local.txt")
+	assert.NoError(t, err)
+	_, err = wt.Commit("local commit", &git.CommitOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}))
+
+	_, _, err = test_project.KluctlExecute(t, context.Background(), t.Log, "helm-update", "--project-dir", cloneDir, "--push-branches")
+	assert.ErrorContains(t, err, "branch kluctl-helm-update/test-chart1-0.2.0 already exists and contains commits that were not pushed to origin")
+}
+
 func TestHelmValues(t *testing.T) {
 	t.Parallel()
 
@@ -1031,4 +1203,234 @@ func TestHelmLookup(t *testing.T) {
 	y, err = uo.FromString(s)
 	assert.NoError(t, err)
 	assertNestedFieldEquals(t, y, "lookupReturnedNil", "data", "lookup")
+
+	s, _ = p.KluctlMust(t, "diff", "-t", "test", "-o", "yaml")
+	var cr result.CommandResult
+	err = yaml.ReadYamlString(s, &cr)
+	assert.NoError(t, err)
+	assert.Contains(t, cr.HelmLookups, result.HelmLookup{
+		ReleaseName: "test-helm1",
+		Ref:         k8s2.NewObjectRef("", "v1", "ConfigMap", lookupCm.Name, lookupCm.Namespace),
+		Found:       true,
+	})
+
+	p.UpdateYaml("helm1/helm-chart.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField(false, "helmChart", "enableLookup")
+		return nil
+	}, "")
+	s, _ = p.KluctlMust(t, "render", "-t", "test", "--print-all")
+	y, err = uo.FromString(s)
+	assert.NoError(t, err)
+	assertNestedFieldEquals(t, y, "lookupReturnedNil", "data", "lookup")
+}
+
+func TestHelmLookupFixture(t *testing.T) {
+	t.Parallel()
+
+	p := test_project.NewTestProject(t)
+
+	repo := &test_utils.TestHelmRepo{
+		Charts: []test_utils.RepoChart{
+			{ChartName: "test-chart1", Version: "0.1.0"},
+		},
+	}
+	repo.Start(t)
+
+	values1 := map[string]any{
+		"lookup":          true,
+		"lookupNamespace": "ns1",
+		"lookupName":      "lookup-cm",
+	}
+
+	p.UpdateTarget("test", nil)
+	p.AddHelmDeployment("helm1", repo.URL.String(), "test-chart1", "0.1.0", "test-helm1", p.TestSlug(), values1)
+	p.UpdateYaml("helm1/lookup-fixture.yaml", func(o *uo.UnstructuredObject) error {
+		*o = *uo.FromMap(map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name":      "lookup-cm",
+				"namespace": "ns1",
+			},
+			"data": map[string]any{
+				"a": "fixtureValue",
+			},
+		})
+		return nil
+	}, "")
+	p.UpdateYaml("helm1/helm-chart.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("lookup-fixture.yaml", "helmChart", "lookupFixture")
+		return nil
+	}, "")
+
+	p.KluctlMust(t, "helm-pull")
+
+	s, _ := p.KluctlMust(t, "render", "-t", "test", "--print-all", "--offline-kubernetes")
+	y, err := uo.FromString(s)
+	assert.NoError(t, err)
+	assertNestedFieldEquals(t, y, "fixtureValue", "data", "lookup")
+
+	p.UpdateYaml("helm1/helm-values.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("missing-cm", "lookupName")
+		return nil
+	}, "")
+	s, _ = p.KluctlMust(t, "render", "-t", "test", "--print-all", "--offline-kubernetes")
+	y, err = uo.FromString(s)
+	assert.NoError(t, err)
+	assertNestedFieldEquals(t, y, "lookupReturnedNil", "data", "lookup")
+}
+
+func TestHelmPostRenderer(t *testing.T) {
+	t.Parallel()
+
+	p := test_project.NewTestProject(t)
+
+	p.UpdateTarget("test", nil)
+	p.AddHelmDeployment("helm1", "test-chart1", "", "", "test-helm1", p.TestSlug(), nil)
+	test_utils.CreateHelmDir(t, "test-chart1", "0.1.0", filepath.Join(p.LocalProjectDir(), "helm1/test-chart1"))
+
+	err := os.WriteFile(filepath.Join(p.LocalProjectDir(), "helm1/post-render.sh"), []byte("#!/bin/sh\nsed 's/v2/v2-post-rendered/'\n"), 0o755)
+	assert.NoError(t, err)
+
+	p.UpdateYaml("helm1/helm-chart.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField(map[string]any{
+			"exec": map[string]any{
+				"command": "./post-render.sh",
+			},
+			"patches": []any{
+				map[string]any{
+					"patch": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-helm1-test-chart1
+  labels:
+    patched: "true"
+`,
+				},
+				map[string]any{
+					"target": map[string]any{
+						"kind": "ConfigMap",
+						"name": "test-helm1-test-chart1",
+					},
+					"patch": `- op: replace
+  path: /data/a
+  value: v1-patched
+`,
+				},
+			},
+		}, "helmChart", "postRenderer")
+		return nil
+	}, "")
+
+	_, _, err = p.Kluctl(t, "render", "--print-all", "--offline-kubernetes", "-t", "test")
+	assert.ErrorContains(t, err, "exec post-renderers are not allowed")
+
+	stdout, _ := p.KluctlMust(t, "render", "--print-all", "--offline-kubernetes", "-t", "test", "--helm-allow-exec-post-renderers")
+	cm1 := uo.FromStringMust(stdout)
+
+	assertNestedFieldEquals(t, cm1, "true", "metadata", "labels", "patched")
+	assertNestedFieldEquals(t, cm1, "v1-patched", "data", "a")
+	assertNestedFieldEquals(t, cm1, "v2-post-rendered", "data", "b")
+}
+
+// completeHelmTestPod waits for the given test Pod to appear and then simulates its completion, as envtest does not
+// run any Pods
+func completeHelmTestPod(t *testing.T, k *test_utils.EnvTestCluster, namespace string, name string, failed bool) {
+	var pod corev1.Pod
+	for i := 0; i < 60; i++ {
+		err := k.Client.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: name}, &pod)
+		if err == nil && pod.DeletionTimestamp == nil {
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	pod.Status.Phase = corev1.PodSucceeded
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodReady, Status: corev1.ConditionFalse, Reason: "PodCompleted"},
+	}
+	terminated := &corev1.ContainerStateTerminated{Reason: "Completed"}
+	if failed {
+		pod.Status.Phase = corev1.PodFailed
+		terminated = &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}
+	}
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{Name: "test", Image: "busybox", State: corev1.ContainerState{Terminated: terminated}},
+	}
+	err := k.Client.Status().Patch(context.Background(), &pod, patch)
+	assert.NoError(t, err)
+}
+
+func TestHelmTest(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_project.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	// envtest does not run the ServiceAccount controller
+	err := k.Client.Create(context.Background(), &corev1.ServiceAccount{
+		ObjectMeta: v1.ObjectMeta{Name: "default", Namespace: p.TestSlug()},
+	})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		assert.NoError(t, err)
+	}
+
+	repo := &test_utils.TestHelmRepo{
+		Charts: []test_utils.RepoChart{
+			{ChartName: "test-chart1", Version: "0.1.0"},
+		},
+	}
+	repo.Start(t)
+
+	p.UpdateTarget("test", nil)
+	p.AddHelmDeployment("helm1", repo.URL.String(), "test-chart1", "0.1.0", "test-helm1", p.TestSlug(), map[string]any{
+		"testPod": true,
+	})
+
+	p.KluctlMust(t, "helm-pull")
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	podName := "test-helm1-test-chart1-test"
+	podRef := k8s2.NewObjectRef("", "v1", "Pod", podName, p.TestSlug())
+	podGvr := corev1.SchemeGroupVersion.WithResource("pods")
+
+	// test hooks must not be deployed
+	assertConfigMapExists(t, k, p.TestSlug(), "test-helm1-test-chart1")
+	_, err = k.Get(podGvr, p.TestSlug(), podName)
+	assert.True(t, errors.IsNotFound(err))
+
+	go completeHelmTestPod(t, k, p.TestSlug(), podName, false)
+
+	stdout, _ := p.KluctlMust(t, "helm-test", "-t", "test", "--test-timeout", "30s", "-o", "yaml")
+	var vr result.ValidateResult
+	err = yaml.ReadYamlString(stdout, &vr)
+	assert.NoError(t, err)
+	assert.Len(t, vr.HelmTests, 1)
+	assert.Equal(t, "test-helm1", vr.HelmTests[0].ReleaseName)
+	assert.Equal(t, podRef, vr.HelmTests[0].Ref)
+	assert.True(t, vr.HelmTests[0].Succeeded)
+	assert.True(t, vr.Ready)
+
+	_, err = k.Get(podGvr, p.TestSlug(), podName)
+	assert.True(t, errors.IsNotFound(err))
+
+	go completeHelmTestPod(t, k, p.TestSlug(), podName, true)
+
+	stdout, _, err = p.Kluctl(t, "helm-test", "-t", "test", "--test-timeout", "30s", "--no-cleanup", "-o", "yaml")
+	assert.ErrorContains(t, err, "Helm tests failed")
+	vr = result.ValidateResult{}
+	err = yaml.ReadYamlString(stdout, &vr)
+	assert.NoError(t, err)
+	assert.Len(t, vr.HelmTests, 1)
+	assert.False(t, vr.HelmTests[0].Succeeded)
+	assert.False(t, vr.Ready)
+	assert.NotEmpty(t, vr.Errors)
+
+	// --no-cleanup keeps the failed test Pod around
+	_, err = k.Get(podGvr, p.TestSlug(), podName)
+	assert.NoError(t, err)
 }
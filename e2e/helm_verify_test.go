@@ -0,0 +1,174 @@
+package e2e
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	test_utils "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeCosignPublicKey(t *testing.T, key *ecdsa.PrivateKey, path string) {
+	b, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b}), 0o600)
+	assert.NoError(t, err)
+}
+
+// signOciChart creates a cosign compatible signature for the given chart version and pushes it into the registry
+func signOciChart(t *testing.T, repo string, version string, key *ecdsa.PrivateKey) {
+	repo = strings.TrimPrefix(repo, "oci://")
+	digest, err := crane.Digest(repo + ":" + version)
+	assert.NoError(t, err)
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, repo, digest))
+	h := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+	assert.NoError(t, err)
+
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:     static.NewLayer(payload, "application/vnd.dev.cosign.simplesigning.v1+json"),
+		MediaType: types.MediaType("application/vnd.dev.cosign.simplesigning.v1+json"),
+		Annotations: map[string]string{
+			"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(sig),
+		},
+	})
+	assert.NoError(t, err)
+
+	err = crane.Push(img, repo+":"+strings.Replace(digest, ":", "-", 1)+".sig")
+	assert.NoError(t, err)
+}
+
+func TestHelmVerifyCosign(t *testing.T) {
+	t.Parallel()
+
+	p := test_project.NewTestProject(t)
+
+	repo := &test_utils.TestHelmRepo{
+		Oci: true,
+		Charts: []test_utils.RepoChart{
+			{ChartName: "test-chart1", Version: "0.1.0"},
+			{ChartName: "test-chart1", Version: "0.2.0"},
+			{ChartName: "test-chart1", Version: "0.3.0"},
+		},
+	}
+	repo.Start(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	chartRepo := repo.URL.String() + "/test-chart1"
+	signOciChart(t, chartRepo, "0.1.0", key)
+	signOciChart(t, chartRepo, "0.2.0", key)
+
+	p.UpdateTarget("test", nil)
+	p.AddHelmDeployment("helm1", repo.URL.String(), "test-chart1", "0.1.0", "test-helm1", p.TestSlug(), nil)
+
+	writeCosignPublicKey(t, key, filepath.Join(p.LocalProjectDir(), "helm1/cosign.pub"))
+	writeCosignPublicKey(t, otherKey, filepath.Join(p.LocalProjectDir(), "helm1/other.pub"))
+
+	// pre-pull without verification, which must be re-pulled after verification is configured
+	p.KluctlMust(t, "helm-pull")
+	assert.FileExists(t, getChartFile(t, p, repo.URL.String(), "test-chart1", "0.1.0"))
+
+	p.UpdateYaml("helm1/helm-chart.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("cosign.pub", "helmChart", "verify", "cosignKey")
+		return nil
+	}, "")
+
+	_, _, err = p.Kluctl(t, "render", "--offline-kubernetes", "-t", "test")
+	assert.ErrorContains(t, err, "has not been verified")
+
+	p.KluctlMust(t, "helm-pull")
+	assert.FileExists(t, filepath.Join(getChartDir(t, p, repo.URL.String(), "test-chart1", "0.1.0"), ".kluctl-verification.yaml"))
+
+	stdout, _ := p.KluctlMust(t, "render", "--print-all", "--offline-kubernetes", "-t", "test")
+	cm := uo.FromStringMust(stdout)
+	assertNestedFieldEquals(t, cm, "0.1.0", "data", "version")
+
+	// modifying the pre-pulled chart must invalidate the verification
+	chartFile := getChartFile(t, p, repo.URL.String(), "test-chart1", "0.1.0")
+	chartYaml, err := os.ReadFile(chartFile)
+	assert.NoError(t, err)
+	err = os.WriteFile(chartFile, append(chartYaml, []byte("description: modified\n")...), 0o600)
+	assert.NoError(t, err)
+	_, _, err = p.Kluctl(t, "render", "--offline-kubernetes", "-t", "test")
+	assert.ErrorContains(t, err, "has been modified after it got verified")
+	p.KluctlMust(t, "helm-pull")
+	p.KluctlMust(t, "render", "--offline-kubernetes", "-t", "test")
+
+	p.UpdateYaml("helm1/helm-chart.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("other.pub", "helmChart", "verify", "cosignKey")
+		_ = o.SetNestedField("0.2.0", "helmChart", "chartVersion")
+		return nil
+	}, "")
+	_, stderr, err := p.Kluctl(t, "helm-pull")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "invalid signature")
+	assert.NoFileExists(t, getChartFile(t, p, repo.URL.String(), "test-chart1", "0.2.0"))
+
+	p.UpdateYaml("helm1/helm-chart.yaml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("cosign.pub", "helmChart", "verify", "cosignKey")
+		_ = o.SetNestedField("0.3.0", "helmChart", "chartVersion")
+		return nil
+	}, "")
+	_, stderr, err = p.Kluctl(t, "helm-pull")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "failed to pull signature")
+	assert.NoFileExists(t, getChartFile(t, p, repo.URL.String(), "test-chart1", "0.3.0"))
+}
+
+func TestHelmRequireVerification(t *testing.T) {
+	t.Parallel()
+
+	p := test_project.NewTestProject(t)
+
+	repo := &test_utils.TestHelmRepo{
+		Oci: true,
+		Charts: []test_utils.RepoChart{
+			{ChartName: "test-chart1", Version: "0.1.0"},
+		},
+	}
+	repo.Start(t)
+
+	p.UpdateTarget("test", nil)
+	p.AddHelmDeployment("helm1", repo.URL.String(), "test-chart1", "0.1.0", "test-helm1", p.TestSlug(), nil)
+
+	_, _, err := p.Kluctl(t, "helm-pull", "--helm-require-verification")
+	assert.ErrorContains(t, err, "has no verification configured, which is required")
+
+	p.KluctlMust(t, "helm-pull")
+
+	_, _, err = p.Kluctl(t, "render", "--offline-kubernetes", "-t", "test", "--helm-require-verification")
+	assert.ErrorContains(t, err, "has no verification configured, which is required")
+
+	p.KluctlMust(t, "render", "--offline-kubernetes", "-t", "test")
+
+	// same via .kluctl.yaml
+	p.UpdateKluctlYaml(func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField(true, "helm", "requireVerification")
+		return nil
+	})
+	_, _, err = p.Kluctl(t, "helm-pull")
+	assert.ErrorContains(t, err, "has no verification configured, which is required")
+	_, _, err = p.Kluctl(t, "render", "--offline-kubernetes", "-t", "test")
+	assert.ErrorContains(t, err, "has no verification configured, which is required")
+}
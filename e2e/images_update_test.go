@@ -0,0 +1,139 @@
+package e2e
+
+import (
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func startImagesTestRegistry(t *testing.T, images map[string][]string) string {
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+
+	u, _ := url.Parse(s.URL)
+
+	img, err := random.Image(16, 1)
+	assert.NoError(t, err)
+	for repo, tags := range images {
+		for _, tag := range tags {
+			err = crane.Push(img, u.Host+"/"+repo+":"+tag)
+			assert.NoError(t, err)
+		}
+	}
+	return u.Host
+}
+
+func testImagesUpdate(t *testing.T, commit bool) {
+	t.Parallel()
+
+	p := test_project.NewTestProject(t)
+
+	host := startImagesTestRegistry(t, map[string][]string{
+		"img1": {"1.0.0", "1.1.0", "2.0.0"},
+		"img2": {"main-9", "main-10", "dev-11"},
+		"img3": {"1.0.0", "1.1.0"},
+	})
+
+	p.UpdateTarget("test", func(target *uo.UnstructuredObject) {
+		_ = target.SetNestedField([]any{
+			map[string]any{
+				"image":       "img1",
+				"resultImage": host + "/img1:1.0.0",
+				"updatePolicy": map[string]any{
+					"semver": "<2.0.0",
+				},
+			},
+			map[string]any{
+				"image":       "img2",
+				"resultImage": host + "/img2:main-9",
+				"updatePolicy": map[string]any{
+					"regex":   `^main-(\d+)$`,
+					"numeric": "asc",
+				},
+			},
+			map[string]any{
+				"image":       "img3",
+				"resultImage": host + "/img3:1.0.0",
+			},
+		}, "images")
+	})
+
+	args := []string{"images", "update"}
+	if commit {
+		args = append(args, "--commit")
+	}
+
+	_, stderr := p.KluctlMust(t, args...)
+	assert.Contains(t, stderr, "Image "+host+"/img1:1.0.0 can be updated to "+host+"/img1:1.1.0")
+	assert.Contains(t, stderr, "Image "+host+"/img2:main-9 can be updated to "+host+"/img2:main-10")
+	assert.NotContains(t, stderr, "img3")
+
+	kluctlYaml := p.GetYaml(".kluctl.yml")
+	images := kluctlYaml.GetNestedObjectListNoErr("targets")[0].GetNestedObjectListNoErr("images")
+	assertNestedFieldEquals(t, images[0], host+"/img1:1.1.0", "resultImage")
+	assertNestedFieldEquals(t, images[1], host+"/img2:main-10", "resultImage")
+	assertNestedFieldEquals(t, images[2], host+"/img3:1.0.0", "resultImage")
+
+	if commit {
+		assert.Contains(t, stderr, "Committed image "+host+"/img1:1.1.0")
+		assert.Contains(t, stderr, "Committed image "+host+"/img2:main-10")
+
+		s, err := p.GetGitWorktree().Status()
+		assert.NoError(t, err)
+		assert.True(t, s.IsClean())
+	}
+
+	_, stderr = p.KluctlMust(t, "images", "update")
+	assert.NotContains(t, stderr, "can be updated")
+}
+
+func TestImagesUpdate(t *testing.T) {
+	testImagesUpdate(t, false)
+}
+
+func TestImagesUpdateCommit(t *testing.T) {
+	testImagesUpdate(t, true)
+}
+
+func TestImagesUpdateKeepsFormatting(t *testing.T) {
+	t.Parallel()
+
+	p := test_project.NewTestProject(t)
+
+	host := startImagesTestRegistry(t, map[string][]string{
+		"img1": {"1.0.0", "1.1.0"},
+		"img2": {"1.0.0", "1.1.0"},
+	})
+
+	fixedImages := fmt.Sprintf(`# fixed images of all targets
+images:
+  # the first image
+  - resultImage: "%[1]s/img1:1.0.0" # keep this comment
+    image: img1
+    updatePolicy:
+      semver: "*"
+  - image: img2
+    resultImage: '%[1]s/img2:1.0.0'
+    updatePolicy: {semver: "*"}
+`, host)
+	p.UpdateFile("fixed-images.yaml", func(f string) (string, error) {
+		return fixedImages, nil
+	}, "")
+
+	p.KluctlMust(t, "images", "update", "--fixed-images-file", filepath.Join(p.LocalProjectDir(), "fixed-images.yaml"))
+
+	b, err := os.ReadFile(filepath.Join(p.LocalProjectDir(), "fixed-images.yaml"))
+	assert.NoError(t, err)
+	expected := strings.ReplaceAll(fixedImages, "1.0.0", "1.1.0")
+	assert.Equal(t, expected, string(b))
+}
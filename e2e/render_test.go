@@ -2,6 +2,8 @@ package e2e
 
 import (
 	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
 	"github.com/stretchr/testify/assert"
@@ -59,3 +61,77 @@ func TestRenderNoKubeconfig(t *testing.T) {
 	assert.ErrorContains(t, err, "context \"context1\" does not exist")
 
 }
+
+func TestRenderSensitiveReport(t *testing.T) {
+	t.Parallel()
+
+	p := test_utils.NewTestProject(t)
+
+	p.UpdateTarget("test", nil)
+	p.UpdateDeploymentYaml("", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField([]map[string]any{
+			{
+				"values": map[string]any{
+					"secrets": map[string]any{
+						"password": "super-secret-password",
+					},
+				},
+				"sensitive": true,
+			},
+			{
+				"values": map[string]any{
+					"config": map[string]any{
+						"value": "not-a-secret",
+					},
+				},
+			},
+		}, "vars")
+		return nil
+	})
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"password": "prefix-{{ secrets.password }}",
+		"encoded":  "{{ secrets.password | b64encode }}",
+		"other":    "{{ secrets.password }}s",
+		"value":    "{{ config.value }}",
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+	addSecretDeployment(p, "secret", map[string]string{
+		"password": "{{ secrets.password }}",
+	}, resourceOpts{
+		name:      "secret",
+		namespace: p.TestSlug(),
+	}, false)
+
+	stdout, stderr := p.KluctlMust(t, "render", "-t", "test", "--offline-kubernetes", "--sensitive-report")
+
+	var report []result.SensitiveField
+	err := yaml.ReadYamlString(stdout, &report)
+	assert.NoError(t, err)
+	assert.Equal(t, []result.SensitiveField{
+		{
+			Ref:        k8s.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm", Namespace: p.TestSlug()},
+			JsonPath:   "data.encoded",
+			VarPath:    "secrets.password",
+			VarsSource: "values",
+		},
+		{
+			Ref:        k8s.ObjectRef{Version: "v1", Kind: "ConfigMap", Name: "cm", Namespace: p.TestSlug()},
+			JsonPath:   "data.password",
+			VarPath:    "secrets.password",
+			VarsSource: "values",
+		},
+		{
+			Ref:        k8s.ObjectRef{Version: "v1", Kind: "Secret", Name: "secret", Namespace: p.TestSlug()},
+			JsonPath:   "stringData.password",
+			VarPath:    "secrets.password",
+			VarsSource: "values",
+		},
+	}, report)
+	assert.NotContains(t, stdout, "super-secret-password")
+
+	assert.Contains(t, stderr, "field data.password contains the value of sensitive variable secrets.password, but the object is not a Secret")
+	assert.NotContains(t, stderr, "field stringData.password")
+}
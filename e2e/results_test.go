@@ -2,13 +2,21 @@ package e2e
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
 	test_utils "github.com/kluctl/kluctl/v2/e2e/test_project"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/results"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func assertSummary(t *testing.T, expected result.CommandResultSummary, actual result.CommandResultSummary) {
@@ -49,7 +57,7 @@ func TestWriteResult(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	rs, err := results.NewResultStoreSecrets(ctx, k.RESTConfig(), k.Client, false, "kluctl-results", 0, 0)
+	rs, err := results.NewResultStoreSecrets(ctx, k.RESTConfig(), k.Client, false, "kluctl-results", results.RetentionOptions{})
 	assert.NoError(t, err)
 
 	opts := results.ListResultSummariesOptions{
@@ -116,3 +124,213 @@ func TestWriteResult(t *testing.T) {
 		DeletedObjects: 1,
 	}, summaries[0])
 }
+
+func TestWriteAuditEvents(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rs, err := results.NewResultStoreSecrets(ctx, k.RESTConfig(), k.Client, true, "kluctl-results", results.RetentionOptions{KeepAuditEventsCount: 3})
+	assert.NoError(t, err)
+
+	clusterId, err := k8s.GetClusterId(ctx, k.Client)
+	assert.NoError(t, err)
+
+	kdInfo := result.KluctlDeploymentInfo{
+		Name:      "audit-" + uuid.NewString()[:8],
+		Namespace: "default",
+		ClusterId: clusterId,
+	}
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		id := uuid.NewString()
+		err = rs.WriteAuditEvent(&result.AuditEvent{
+			Id:               id,
+			Time:             metav1.NewTime(now.Add(time.Duration(i) * time.Second)),
+			User:             "test-user",
+			Source:           result.AuditSourceCli,
+			Action:           result.AuditActionDeploy,
+			KluctlDeployment: kdInfo,
+			RequestValue:     fmt.Sprintf("request-%d", i),
+		})
+		assert.NoError(t, err)
+
+		// cleanup of old audit events relies on the cache, so we must wait for it to catch up before writing more events
+		assert.Eventually(t, func() bool {
+			events, err := rs.ListAuditEvents(results.ListAuditEventsOptions{KluctlDeployment: &kdInfo})
+			return err == nil && len(events) != 0 && events[0].Id == id
+		}, 10*time.Second, 100*time.Millisecond)
+	}
+
+	assert.Eventually(t, func() bool {
+		events, err := rs.ListAuditEvents(results.ListAuditEventsOptions{KluctlDeployment: &kdInfo})
+		if err != nil || len(events) != 3 {
+			return false
+		}
+		return events[0].RequestValue == "request-4" && events[2].RequestValue == "request-2"
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func TestResultsCommands(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"d1": "v1",
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+	assertConfigMapExists(t, k, p.TestSlug(), "cm")
+
+	p.SetSkipProjectDirArg(true)
+
+	repoKey := types.ParseGitUrlMust(p.GitUrl()).RepoKey().String()
+
+	listResults := func() []result.CommandResultSummary {
+		stdout, _ := p.KluctlMust(t, "results", "list", "--context", k.Context, "--project", repoKey, "-o", "json")
+		var summaries []result.CommandResultSummary
+		err := json.Unmarshal([]byte(stdout), &summaries)
+		assert.NoError(t, err)
+		return summaries
+	}
+
+	summaries := listResults()
+	assert.Len(t, summaries, 1)
+	assert.Equal(t, "deploy", summaries[0].Command.Command)
+
+	stdout, _ := p.KluctlMust(t, "results", "show", "--context", k.Context, summaries[0].Id, "-o", "json")
+	var cr result.CommandResult
+	err := json.Unmarshal([]byte(stdout), &cr)
+	assert.NoError(t, err)
+	assert.Equal(t, summaries[0].Id, cr.Id)
+
+	exportDir := t.TempDir()
+	p.KluctlMust(t, "results", "export", "--context", k.Context, "--output-dir", exportDir, summaries[0].Id)
+	assert.FileExists(t, filepath.Join(exportDir, fmt.Sprintf("command-result-%s.yaml", summaries[0].Id)))
+
+	p.KluctlMust(t, "results", "delete", "--context", k.Context, "--yes", summaries[0].Id)
+
+	assert.Eventually(t, func() bool {
+		return len(listResults()) == 0
+	}, 10*time.Second, 500*time.Millisecond)
+}
+
+func TestResultsHistory(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"d1": "v1",
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	// ensure stable sorting of command results
+	b := newSecondPassedBarrier(t)
+
+	p.UpdateYaml("cm/configmap-cm.yml", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField("v2", "data", "d1")
+		return nil
+	}, "")
+	b.Wait()
+	p.KluctlMust(t, "deploy", "--yes", "-t", "test")
+
+	p.SetSkipProjectDirArg(true)
+
+	repoKey := types.ParseGitUrlMust(p.GitUrl()).RepoKey().String()
+	stdout, _ := p.KluctlMust(t, "results", "history", "--context", k.Context, "--project", repoKey, "-o", "json", fmt.Sprintf("%s/ConfigMap/cm", p.TestSlug()))
+
+	var entries []result.ObjectHistoryEntry
+	err := json.Unmarshal([]byte(stdout), &entries)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, result.ObjectHistoryEventChanged, entries[0].Event)
+	assert.NotEmpty(t, entries[0].Changes)
+	assert.Equal(t, result.ObjectHistoryEventCreated, entries[1].Event)
+}
+
+func TestWriteResultObfuscated(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := test_utils.NewTestProject(t)
+
+	createNamespace(t, k, p.TestSlug())
+
+	p.UpdateTarget("test", nil)
+	p.UpdateDeploymentYaml("", func(o *uo.UnstructuredObject) error {
+		_ = o.SetNestedField([]map[string]any{
+			{
+				"values": map[string]any{
+					"secrets": map[string]any{
+						"password": "super-secret-password",
+					},
+				},
+				"sensitive": true,
+			},
+		}, "vars")
+		return nil
+	})
+
+	addConfigMapDeployment(p, "cm", map[string]string{
+		"password": "{{ secrets.password }}",
+	}, resourceOpts{
+		name:      "cm",
+		namespace: p.TestSlug(),
+	})
+	addSecretDeployment(p, "secret", map[string]string{
+		"password": "{{ secrets.password }}",
+	}, resourceOpts{
+		name:      "secret",
+		namespace: p.TestSlug(),
+	}, false)
+
+	// --no-obfuscate must only affect the output, but not what is written to the result store
+	stdout, _ := p.KluctlMust(t, "deploy", "--yes", "-t", "test", "--no-obfuscate", "-o", "yaml")
+	assert.Contains(t, stdout, "super-secret-password")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rs, err := results.NewResultStoreSecrets(ctx, k.RESTConfig(), k.Client, false, "kluctl-results", results.RetentionOptions{})
+	assert.NoError(t, err)
+
+	summaries, err := rs.ListCommandResultSummaries(results.ListResultSummariesOptions{
+		ProjectFilter: &result.ProjectKey{
+			RepoKey: types.ParseGitUrlMust(p.GitUrl()).RepoKey(),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, summaries, 1)
+
+	cr, err := rs.GetCommandResult(results.GetCommandResultOptions{Id: summaries[0].Id})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, cr.Objects)
+	b, err := json.Marshal(cr)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "super-secret-password")
+	assert.NotContains(t, string(b), base64.StdEncoding.EncodeToString([]byte("super-secret-password")))
+}
@@ -23,6 +23,7 @@ import (
 	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -32,6 +33,7 @@ type certServer struct {
 	server   http.Server
 	url      string
 	certHash string
+	certPem  []byte
 }
 
 var certServer1 *certServer
@@ -72,6 +74,7 @@ func startCertServer() (*certServer, error) {
 	var cs certServer
 	cs.url = certUrl
 	cs.server.Handler = mux
+	cs.certPem = certbytes
 	cs.certHash, err = seal.HashPublicKey(cert)
 	if err != nil {
 		return nil, err
@@ -550,3 +553,78 @@ func TestSeal_Vault(t *testing.T) {
 		"s2": "v2",
 	})
 }
+
+func readSealedCertHash(t *testing.T, p string) string {
+	o, err := uo.FromFile(p)
+	assert.NoError(t, err)
+	h := o.GetK8sAnnotation("kluctl.io/sealedsecret-cert-hash")
+	if h == nil {
+		t.Fatal("kluctl.io/sealedsecret-cert-hash annotation not found")
+	}
+	return *h
+}
+
+func TestSeal_Rotate(t *testing.T) {
+	t.Parallel()
+
+	k := defaultCluster1
+
+	p := prepareSealTest(t, k,
+		map[string]string{
+			"s1": "{{ secrets.s1 }}",
+		},
+		[]*uo.UnstructuredObject{
+			uo.FromMap(map[string]interface{}{
+				"values": map[string]interface{}{
+					"s1": "v1",
+				},
+			}),
+		}, false)
+	addSecretDeployment(p, "secret-deployment2", map[string]string{
+		"s2": "{{ secrets.s1 }}",
+	}, resourceOpts{name: "secret2", namespace: p.TestSlug()}, true)
+
+	certFile1 := filepath.Join(t.TempDir(), "cert1.pem")
+	certFile2 := filepath.Join(t.TempDir(), "cert2.pem")
+	assert.NoError(t, os.WriteFile(certFile1, certServer1.certPem, 0o600))
+	assert.NoError(t, os.WriteFile(certFile2, certServer2.certPem, 0o600))
+
+	sealedFile1 := filepath.Join(p.LocalProjectDir(), ".sealed-secrets/secret-deployment/test-target/secret-secret.yml")
+	sealedFile2 := filepath.Join(p.LocalProjectDir(), ".sealed-secrets/secret-deployment2/test-target/secret-secret2.yml")
+
+	p.KluctlMust(t, "seal", "-t", "test-target", "--offline-kubernetes", "--cert-file", certFile1)
+	assert.Equal(t, certServer1.certHash, readSealedCertHash(t, sealedFile1))
+	assert.Equal(t, certServer1.certHash, readSealedCertHash(t, sealedFile2))
+
+	// simulate a partially rotated project, where only the first secret is sealed for the new cert
+	old2, err := os.ReadFile(sealedFile2)
+	assert.NoError(t, err)
+	p.KluctlMust(t, "seal", "-t", "test-target", "--offline-kubernetes", "--cert-file", certFile2)
+	assert.NoError(t, os.WriteFile(sealedFile2, old2, 0o600))
+	assert.Equal(t, certServer2.certHash, readSealedCertHash(t, sealedFile1))
+	assert.Equal(t, certServer1.certHash, readSealedCertHash(t, sealedFile2))
+
+	before1, err := os.ReadFile(sealedFile1)
+	assert.NoError(t, err)
+
+	_, stderr := p.KluctlMust(t, "seal", "-t", "test-target", "--offline-kubernetes", "--cert-file", certFile2, "--rotate")
+	assert.Contains(t, stderr, fmt.Sprintf("secret-secret2.yml was sealed for outdated cert(s) %s", certServer1.certHash[:12]))
+	assert.Contains(t, stderr, "0 new, 1 re-sealed for outdated certs, 1 skipped")
+
+	// the up-to-date file must not be touched
+	after1, err := os.ReadFile(sealedFile1)
+	assert.NoError(t, err)
+	assert.Equal(t, string(before1), string(after1))
+	assert.Equal(t, certServer2.certHash, readSealedCertHash(t, sealedFile2))
+
+	// seal for the old and the new cert at once
+	p.KluctlMust(t, "seal", "-t", "test-target", "--offline-kubernetes", "--cert-file", certFile2,
+		"--extra-cert-file", "old-cluster="+certFile1)
+	assert.Equal(t, certServer2.certHash, readSealedCertHash(t, sealedFile1))
+	assert.Equal(t, certServer1.certHash, readSealedCertHash(t, filepath.Join(p.LocalProjectDir(), ".sealed-secrets/secret-deployment/old-cluster/secret-secret.yml")))
+	assert.Equal(t, certServer1.certHash, readSealedCertHash(t, filepath.Join(p.LocalProjectDir(), ".sealed-secrets/secret-deployment2/old-cluster/secret-secret2.yml")))
+
+	_, stderr, err = p.Kluctl(t, "seal", "-t", "test-target", "--offline-kubernetes", "--cert-file", certFile2, "--extra-cert-file", "invalid")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "must be in the form <outputPattern>=<certFile>")
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/vars/sops_test_resources"
 	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -128,3 +130,50 @@ func TestSopsHelmValues(t *testing.T) {
 		"kubeVersion": k.ServerVersion.String(),
 	}, cm1.Object["data"])
 }
+
+func readProjectFile(t *testing.T, p *test_project.TestProject, path string) string {
+	b, err := os.ReadFile(filepath.Join(p.LocalProjectDir(), path))
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestSopsEncryptDecryptEdit(t *testing.T) {
+	t.Parallel()
+
+	p := test_project.NewTestProject(t, test_project.WithUseProcess(true), test_project.WithBareProject())
+	setSopsKey(p)
+
+	p.UpdateFile(".sops.yaml", func(f string) (string, error) {
+		return `creation_rules:
+  - path_regex: .*\.yaml
+    encrypted_regex: ^secret$
+    age: age1q69g6x9jcz7lgnrgdxemystmhec4e8cxlzz45x0tt6t7dddp2ppsnkdxe7
+`, nil
+	}, "")
+	p.UpdateFile("secret.yaml", func(f string) (string, error) {
+		return "a: b\nsecret: s3cr3t\n", nil
+	}, "")
+
+	stdout, _ := p.KluctlProcessMust(t, "sops", "encrypt", "secret.yaml")
+	assert.Contains(t, stdout, "a: b\n")
+	assert.Contains(t, stdout, "secret: ENC[")
+	assert.NotContains(t, stdout, "s3cr3t")
+	assert.Equal(t, "a: b\nsecret: s3cr3t\n", readProjectFile(t, p, "secret.yaml"))
+
+	p.KluctlProcessMust(t, "sops", "encrypt", "--in-place", "secret.yaml")
+	assert.NotContains(t, readProjectFile(t, p, "secret.yaml"), "s3cr3t")
+
+	_, stderr, err := p.KluctlProcess(t, "sops", "encrypt", "secret.yaml")
+	assert.Error(t, err)
+	assert.Contains(t, stderr, "already encrypted")
+
+	stdout, _ = p.KluctlProcessMust(t, "sops", "decrypt", "secret.yaml")
+	assert.Equal(t, "a: b\nsecret: s3cr3t\n", stdout)
+
+	p.SetEnv("EDITOR", "sed -i s/s3cr3t/changed/")
+	p.KluctlProcessMust(t, "sops", "edit", "secret.yaml")
+	assert.NotContains(t, readProjectFile(t, p, "secret.yaml"), "changed")
+
+	stdout, _ = p.KluctlProcessMust(t, "sops", "decrypt", "secret.yaml")
+	assert.Equal(t, "a: b\nsecret: changed\n", stdout)
+}
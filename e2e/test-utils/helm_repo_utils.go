@@ -44,10 +44,23 @@ func CreateHelmDir(t *testing.T, name string, version string, dest string) {
 	}
 }
 
-func createHelmPackage(t *testing.T, name string, version string) string {
+func createHelmPackage(t *testing.T, c RepoChart) string {
 	tmpDir := t.TempDir()
 
-	CreateHelmDir(t, name, version, tmpDir)
+	CreateHelmDir(t, c.ChartName, c.Version, tmpDir)
+
+	if c.Values != nil {
+		err := yaml.WriteYamlFile(filepath.Join(tmpDir, "values.yaml"), c.Values)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if c.ValuesSchema != "" {
+		err := os.WriteFile(filepath.Join(tmpDir, "values.schema.json"), []byte(c.ValuesSchema), 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
 
 	settings := cli.New()
 	client := action.NewPackage()
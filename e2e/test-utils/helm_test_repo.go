@@ -30,6 +30,11 @@ type TestHelmRepo struct {
 type RepoChart struct {
 	ChartName string
 	Version   string
+
+	// Values replaces the default values.yaml of the test chart if set
+	Values map[string]any
+	// ValuesSchema is written to values.schema.json if set
+	ValuesSchema string
 }
 
 func (s *TestHelmRepo) Start(t *testing.T) {
@@ -44,7 +49,7 @@ func (s *TestHelmRepo) startHelmRepo(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	for _, c := range s.Charts {
-		tgz := createHelmPackage(t, c.ChartName, c.Version)
+		tgz := createHelmPackage(t, c)
 		_ = cp.Copy(tgz, filepath.Join(tmpDir, s.Path, filepath.Base(tgz)))
 	}
 
@@ -126,7 +131,7 @@ func (s *TestHelmRepo) startOciRepo(t *testing.T) {
 	c.Options = append(c.Options, pusher.WithRegistryClient(registryClient))
 
 	for _, chart := range s.Charts {
-		tgz := createHelmPackage(t, chart.ChartName, chart.Version)
+		tgz := createHelmPackage(t, chart)
 		_ = cp.Copy(tgz, filepath.Join(tmpDir, filepath.Base(tgz)))
 
 		err := c.UploadTo(tgz, s.URL.String())
@@ -57,3 +57,24 @@ func KluctlExecute(t *testing.T, ctx context.Context, logFn func(args ...any), a
 	defer m.Unlock()
 	return stdoutBuf.String(), stderrBuf.String(), err
 }
+
+// TestPromptProvider answers all prompts via the Answer callback and records the asked prompts.
+type TestPromptProvider struct {
+	Answer func(message string) (string, error)
+
+	mutex   sync.Mutex
+	prompts []string
+}
+
+func (pp *TestPromptProvider) Prompt(ctx context.Context, password bool, message string) (string, error) {
+	pp.mutex.Lock()
+	pp.prompts = append(pp.prompts, message)
+	pp.mutex.Unlock()
+	return pp.Answer(message)
+}
+
+func (pp *TestPromptProvider) Prompts() []string {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+	return append([]string{}, pp.prompts...)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/huandu/xstrings"
 	"github.com/jinzhu/copier"
 	git2 "github.com/kluctl/kluctl/v2/e2e/test-utils"
+	"github.com/kluctl/kluctl/v2/pkg/prompts"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
@@ -538,6 +539,23 @@ func (p *TestProject) KluctlExecute(t *testing.T, argsIn ...string) (string, str
 	return KluctlExecute(t, context.Background(), t.Log, args...)
 }
 
+// KluctlWithPrompts works like KluctlExecute, but answers all prompts via the given prompt provider.
+func (p *TestProject) KluctlWithPrompts(t *testing.T, pp prompts.PromptProvider, argsIn ...string) (string, string, error) {
+	if p.useProcess {
+		t.Fatal("prompts are not supported in KluctlProcess(...)")
+	}
+
+	var args []string
+	args = append(args, p.extraArgs...)
+	if !p.skipProjectDirArg {
+		args = append(args, "--project-dir", p.LocalProjectDir())
+	}
+	args = append(args, argsIn...)
+
+	ctx := prompts.NewContext(context.Background(), pp)
+	return KluctlExecute(t, ctx, t.Log, args...)
+}
+
 func (p *TestProject) Kluctl(t *testing.T, argsIn ...string) (string, string, error) {
 	if p.useProcess {
 		return p.KluctlProcess(t, argsIn...)
@@ -9,12 +9,14 @@ import (
 	"github.com/kluctl/go-jinja2"
 	"github.com/kluctl/kluctl/v2/pkg/clouds/aws"
 	internal_metrics "github.com/kluctl/kluctl/v2/pkg/controllers/metrics"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
 	"github.com/kluctl/kluctl/v2/pkg/git"
 	helm_auth "github.com/kluctl/kluctl/v2/pkg/helm/auth"
 	"github.com/kluctl/kluctl/v2/pkg/kluctl_jinja2"
 	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
 	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
 	"github.com/kluctl/kluctl/v2/pkg/repocache"
+	"github.com/kluctl/kluctl/v2/pkg/results"
 	"github.com/kluctl/kluctl/v2/pkg/sops"
 	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
 	intkeyservice "github.com/kluctl/kluctl/v2/pkg/sops/keyservice"
@@ -138,12 +140,21 @@ func prepareProject(ctx context.Context,
 		return nil, err
 	}
 
+	pp.gitRP.SetCacheAccessFunc(func(hit bool) {
+		internal_metrics.NewKluctlSourceCacheRequests(obj.Namespace, obj.Name, "git", hit).Inc()
+	})
+	pp.ociRP.SetCacheAccessFunc(func(hit bool) {
+		internal_metrics.NewKluctlSourceCacheRequests(obj.Namespace, obj.Name, "oci", hit).Inc()
+	})
+
 	pp.helmAuthProvider, err = r.buildHelmAuth(ctx, helmSecrets)
 	if err != nil {
 		return nil, err
 	}
 
 	if doCloneSource {
+		fetchStart := time.Now()
+		sourceType := "git"
 		pth := ""
 		if pp.obj.Spec.Source.Git != nil {
 			pth = pp.obj.Spec.Source.Git.Path
@@ -157,6 +168,7 @@ func prepareProject(ctx context.Context,
 				return nil, err
 			}
 		} else if pp.obj.Spec.Source.Oci != nil {
+			sourceType = "oci"
 			pth = pp.obj.Spec.Source.Oci.Path
 			rpEntry, err := pp.ociRP.GetEntry(pp.obj.Spec.Source.Oci.URL)
 			if err != nil {
@@ -181,6 +193,7 @@ func prepareProject(ctx context.Context,
 		} else {
 			return nil, fmt.Errorf("missing source spec")
 		}
+		internal_metrics.NewKluctlSourceFetchDuration(obj.Namespace, obj.Name, sourceType).Observe(time.Since(fetchStart).Seconds())
 
 		// check kluctl project path exists
 		pp.projectDir, err = securejoin.SecureJoin(pp.repoDir, pth)
@@ -625,6 +638,9 @@ func (pt *preparedTarget) loadTarget(ctx context.Context, p *kluctl_project.Load
 		HelmAuthProvider: pt.pp.helmAuthProvider,
 		OciAuthProvider:  pt.pp.ociAuthProvider,
 		RenderOutputDir:  renderOutputDir,
+
+		HelmRequireVerification:    pt.pp.obj.Spec.HelmRequireVerification,
+		HelmAllowExecPostRenderers: pt.pp.r.HelmAllowExecPostRenderers,
 	}
 	if pt.pp.obj.Spec.Target != nil {
 		props.TargetName = *pt.pp.obj.Spec.Target
@@ -646,7 +662,8 @@ func (pt *preparedTarget) loadTarget(ctx context.Context, p *kluctl_project.Load
 		return nil, err
 	}
 
-	k, err := k8s2.NewK8sCluster(ctx, restConfig, discovery, mapper, props.DryRun)
+	k8sConfig := k8s2.WithRateLimit(restConfig, pt.pp.obj.Spec.K8sQps, pt.pp.obj.Spec.K8sBurst)
+	k, err := k8s2.NewK8sCluster(ctx, k8sConfig, discovery, mapper, props.DryRun)
 	if err != nil {
 		return nil, err
 	}
@@ -723,7 +740,12 @@ func (pt *preparedTarget) writeCommandResult(ctx context.Context, cmdResult *res
 		log.Info("skipping storing of empty command result")
 	} else if pt.pp.r.ResultStore != nil {
 		log.Info(fmt.Sprintf("Writing command result %s", cmdResult.Id))
-		err = pt.pp.r.ResultStore.WriteCommandResult(cmdResult)
+		// same as with the CLI, secrets and sensitive fields must never end up in the result store
+		var obfuscator diff.Obfuscator
+		err = obfuscator.ObfuscateResult(cmdResult)
+		if err == nil {
+			err = pt.pp.r.ResultStore.WriteCommandResult(cmdResult)
+		}
 		if err != nil {
 			log.Error(err, "Writing command result failed")
 		}
@@ -763,6 +785,8 @@ func (pt *preparedTarget) writeValidateResult(ctx context.Context, validateResul
 		return err
 	}
 
+	pt.exportValidateResultMetricsToProm(validateResult)
+
 	if pt.pp.r.ResultStore != nil {
 		log.Info(fmt.Sprintf("Writing validate result %s", validateResult.Id))
 		err = pt.pp.r.ResultStore.WriteValidateResult(validateResult)
@@ -796,6 +820,8 @@ func (pt *preparedTarget) kluctlDeploy(targetContext *target_context.TargetConte
 	cmd.NoWait = pt.pp.obj.Spec.NoWait
 	cmd.Prune = pt.pp.obj.Spec.Prune
 	cmd.WaitPrune = false
+	cmd.ApplyConcurrency = pt.pp.obj.Spec.ApplyConcurrency
+	cmd.ApplyItemConcurrency = pt.pp.obj.Spec.ApplyItemConcurrency
 
 	cmdResult := cmd.Run(nil)
 	return cmdResult
@@ -828,6 +854,8 @@ func (pt *preparedTarget) kluctlDiff(targetContext *target_context.TargetContext
 	cmd.ReplaceOnError = pt.pp.obj.Spec.ReplaceOnError
 	cmd.ForceReplaceOnError = pt.pp.obj.Spec.ForceReplaceOnError
 	cmd.SkipResourceVersions = resourceVersions
+	cmd.ApplyConcurrency = pt.pp.obj.Spec.ApplyConcurrency
+	cmd.ApplyItemConcurrency = pt.pp.obj.Spec.ApplyItemConcurrency
 
 	cmdResult := cmd.Run()
 	return cmdResult
@@ -843,6 +871,12 @@ func (pt *preparedTarget) kluctlValidate(targetContext *target_context.TargetCon
 	return validateResult
 }
 
+func (pt *preparedTarget) kluctlHelmTest(targetContext *target_context.TargetContext) *result.ValidateResult {
+	cmd := commands.NewHelmTestCommand(targetContext)
+
+	return cmd.Run(targetContext.SharedContext.Ctx)
+}
+
 func (pt *preparedTarget) kluctlDelete(ctx context.Context, discriminator string) (*result.CommandResult, error) {
 	if !pt.pp.obj.Spec.Delete {
 		return nil, nil
@@ -906,4 +940,56 @@ func (pt *preparedTarget) exportCommandResultMetricsToProm(summary *result.Comma
 	internal_metrics.NewKluctlNumberOfOrphanObjects(pt.pp.obj.Namespace, pt.pp.obj.Name).Set(float64(summary.OrphanObjects))
 	internal_metrics.NewKluctlNumberOfWarnings(pt.pp.obj.Namespace, pt.pp.obj.Name, summary.Command.Command).Set(float64(len(summary.Warnings)))
 	internal_metrics.NewKluctlNumberOfErrors(pt.pp.obj.Namespace, pt.pp.obj.Name, summary.Command.Command).Set(float64(len(summary.Errors)))
+	if results.IsSuccessfulDeploy(summary) {
+		internal_metrics.NewKluctlLastSuccessfulDeployTimestamp(pt.pp.obj.Namespace, pt.pp.obj.Name).Set(float64(summary.Command.EndTime.Unix()))
+	}
+}
+
+func (pt *preparedTarget) exportValidateResultMetricsToProm(validateResult *result.ValidateResult) {
+	ns, name := pt.pp.obj.Namespace, pt.pp.obj.Name
+	ol := pt.pp.r.ObjectMetricsLabels
+
+	ready := 0.0
+	if validateResult.Ready {
+		ready = 1.0
+	}
+	internal_metrics.NewKluctlValidateReady(ns, name).Set(ready)
+
+	internal_metrics.ResetKluctlValidateObjectMetrics(ns, name)
+	for lv, cnt := range countObjectLabelValues(ol, validateResult.Errors) {
+		internal_metrics.NewKluctlValidateErrors(ns, name, lv).Set(float64(cnt))
+	}
+	for lv, cnt := range countObjectLabelValues(ol, validateResult.Warnings) {
+		internal_metrics.NewKluctlValidateWarnings(ns, name, lv).Set(float64(cnt))
+	}
+}
+
+func (pt *preparedTarget) exportDriftDetectionResultMetricsToProm(driftDetectionResult *result.DriftDetectionResult) {
+	ns, name := pt.pp.obj.Namespace, pt.pp.obj.Name
+	ol := pt.pp.r.ObjectMetricsLabels
+
+	counts := map[internal_metrics.ObjectLabelValues]int{
+		// ensure the metric is always present, even if nothing drifted
+		{}: 0,
+	}
+	for _, o := range driftDetectionResult.Objects {
+		counts[ol.Values(o.Ref)]++
+	}
+
+	internal_metrics.ResetKluctlDriftObjectMetrics(ns, name)
+	for lv, cnt := range counts {
+		internal_metrics.NewKluctlDriftedObjects(ns, name, lv).Set(float64(cnt))
+	}
+}
+
+// countObjectLabelValues counts the given errors/warnings per object label values. The metric without object labels
+// is always included, so that a value of zero is reported when no errors/warnings exist.
+func countObjectLabelValues(ol internal_metrics.ObjectLabels, l []result.DeploymentError) map[internal_metrics.ObjectLabelValues]int {
+	counts := map[internal_metrics.ObjectLabelValues]int{
+		{}: 0,
+	}
+	for _, e := range l {
+		counts[ol.Values(e.Ref)]++
+	}
+	return counts
 }
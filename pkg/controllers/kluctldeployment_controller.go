@@ -10,10 +10,12 @@ import (
 	ssh_pool "github.com/kluctl/kluctl/v2/pkg/git/ssh-pool"
 	"github.com/kluctl/kluctl/v2/pkg/results"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/tracing"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/metrics"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"go.opentelemetry.io/otel/attribute"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
@@ -39,6 +41,15 @@ type KluctlDeploymentReconciler struct {
 	DefaultServiceAccount string
 	DryRun                bool
 
+	// ObjectMetricsLabels configures the labels of per-object metrics, e.g. validation errors and drifted objects
+	ObjectMetricsLabels internal_metrics.ObjectLabels
+
+	// StatusEvents causes status output to be logged as structured events instead of plain messages
+	StatusEvents bool
+
+	// HelmAllowExecPostRenderers allows exec post-renderers configured in helm-chart.yaml files
+	HelmAllowExecPostRenderers bool
+
 	SshPool *ssh_pool.SshPool
 
 	ResultStore results.ResultStore
@@ -58,17 +69,32 @@ type KluctlDeploymentReconcilerOpts struct {
 // +kubebuilder:rbac:groups="",resources=configmaps;secrets;serviceaccounts,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
-func (r *KluctlDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KluctlDeploymentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, retErr error) {
 	reconcileStart := time.Now()
 
+	ctx, span := tracing.Start(ctx, "reconcile",
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("kluctl.deployment.name", req.Name))
+	defer func() {
+		tracing.End(span, retErr)
+	}()
+
 	// we reuse the reconcileID as command result id
 	reconcileID := string(controller.ReconcileIDFromContext(ctx))
 
 	log := ctrl.LoggerFrom(ctx)
 
-	ctx = status.NewContext(ctx, status.NewSimpleStatusHandler(func(level status.Level, message string) {
-		log.Info(message)
-	}, false))
+	var sh status.StatusHandler
+	if r.StatusEvents {
+		sh = status.NewEventStatusHandler(func(e *status.Event) {
+			log.Info(e.Message, "statusEvent", e)
+		}, false)
+	} else {
+		sh = status.NewSimpleStatusHandler(func(level status.Level, message string) {
+			log.Info(message)
+		}, false)
+	}
+	ctx = status.NewContext(ctx, sh)
 
 	obj := &kluctlv1.KluctlDeployment{}
 	// we must use ApiReader here to ensure that we don't get stale objects from the cache, which can easily happen
@@ -419,6 +445,8 @@ func (r *KluctlDeploymentReconciler) finalize(ctx context.Context, obj *kluctlv1
 	delete(r.resourceVersionsMap, client.ObjectKeyFromObject(obj))
 	r.mutex.Unlock()
 
+	internal_metrics.DeleteKluctlDeploymentMetrics(obj.Namespace, obj.Name)
+
 	// Remove our finalizer from the list and update it
 	patch := client.MergeFrom(obj.DeepCopy())
 	controllerutil.RemoveFinalizer(obj, kluctlv1.KluctlDeploymentFinalizer)
@@ -507,4 +535,9 @@ func (r *KluctlDeploymentReconciler) exportDeploymentObjectToProm(obj *kluctlv1.
 		internal_metrics.NewKluctlSourceSpec(obj.Namespace, obj.Name,
 			*obj.Spec.Source.URL, obj.Spec.Source.Path, obj.Spec.Source.Ref.String()).Set(0.0)
 	}
+	// restore the last successful deployment timestamp after controller restarts
+	lastDeployResult, err := obj.Status.GetLastDeployResult()
+	if err == nil && lastDeployResult != nil && results.IsSuccessfulDeploy(lastDeployResult) {
+		internal_metrics.NewKluctlLastSuccessfulDeployTimestamp(obj.Namespace, obj.Name).Set(float64(lastDeployResult.Command.EndTime.Unix()))
+	}
 }
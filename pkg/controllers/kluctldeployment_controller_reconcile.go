@@ -2,11 +2,14 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	json_patch "github.com/evanphx/json-patch/v5"
+	"github.com/google/uuid"
 	"github.com/hashicorp/go-multierror"
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
@@ -34,6 +37,7 @@ func (r *KluctlDeploymentReconciler) startHandleManualRequest(ctx context.Contex
 	}
 
 	log.Info(fmt.Sprintf("Processing %s: %s", requestAnnotation, v))
+	fieldManager := getAnnotationFieldManager(obj, requestAnnotation)
 
 	// first remove the annotation so that it doesn't get re-processed
 	err := r.patch(ctx, key, false, func(obj *kluctlv1.KluctlDeployment) error {
@@ -68,9 +72,10 @@ func (r *KluctlDeploymentReconciler) startHandleManualRequest(ctx context.Contex
 
 	if rr == nil || rr.Request.RequestValue != v {
 		rr = &kluctlv1.ManualRequestResult{
-			Request:     mr,
-			StartTime:   metav1.Now(),
-			ReconcileId: reconcileId,
+			Request:             mr,
+			StartTime:           metav1.Now(),
+			ReconcileId:         reconcileId,
+			RequestFieldManager: fieldManager,
 		}
 		err := r.patchStatus(ctx, key, func(status *kluctlv1.KluctlDeploymentStatus) error {
 			*getResultPtr(status) = rr
@@ -85,7 +90,7 @@ func (r *KluctlDeploymentReconciler) startHandleManualRequest(ctx context.Contex
 }
 
 func (r *KluctlDeploymentReconciler) finishHandleManualRequest(ctx context.Context,
-	obj *kluctlv1.KluctlDeployment, rr *kluctlv1.ManualRequestResult, resultId string, commandErr error, getResultPtr getResultPtrCallback) error {
+	obj *kluctlv1.KluctlDeployment, rr *kluctlv1.ManualRequestResult, requestAnnotation string, resultId string, commandErr error, getResultPtr getResultPtrCallback) error {
 	key := client.ObjectKeyFromObject(obj)
 
 	if rr == nil {
@@ -102,10 +107,91 @@ func (r *KluctlDeploymentReconciler) finishHandleManualRequest(ctx context.Conte
 	if commandErr != nil {
 		rr.CommandError = commandErr.Error()
 	}
-	return r.patchStatus(ctx, key, func(status *kluctlv1.KluctlDeploymentStatus) error {
+	err := r.patchStatus(ctx, key, func(status *kluctlv1.KluctlDeploymentStatus) error {
 		*getResultPtr(status) = rr
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	err = r.writeManualRequestAuditEvent(ctx, obj, rr, requestAnnotation)
+	if err != nil {
+		// failing to write the audit event should not fail the request itself
+		log.Error(err, "Failed to write audit event")
+	}
+	return nil
+}
+
+// getAnnotationFieldManager returns the field manager that last wrote the given annotation, as recorded in the
+// managedFields of the object
+func getAnnotationFieldManager(obj client.Object, annotation string) string {
+	var manager string
+	var managerTime *metav1.Time
+	for _, mf := range obj.GetManagedFields() {
+		if mf.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]any
+		err := json.Unmarshal(mf.FieldsV1.Raw, &fields)
+		if err != nil {
+			continue
+		}
+		metadata, _ := fields["f:metadata"].(map[string]any)
+		annotations, _ := metadata["f:annotations"].(map[string]any)
+		if _, ok := annotations["f:"+annotation]; !ok {
+			continue
+		}
+		if manager == "" || (mf.Time != nil && (managerTime == nil || managerTime.Before(mf.Time))) {
+			manager = mf.Manager
+			managerTime = mf.Time
+		}
+	}
+	return manager
+}
+
+var manualRequestAuditActions = map[string]string{
+	kluctlv1.KluctlRequestReconcileAnnotation: result.AuditActionReconcile,
+	kluctlv1.KluctlRequestDeployAnnotation:    result.AuditActionDeploy,
+	kluctlv1.KluctlRequestPruneAnnotation:     result.AuditActionPrune,
+	kluctlv1.KluctlRequestValidateAnnotation:  result.AuditActionValidate,
+	kluctlv1.KluctlRequestDiffAnnotation:      result.AuditActionDiff,
+}
+
+func (r *KluctlDeploymentReconciler) writeManualRequestAuditEvent(ctx context.Context, obj *kluctlv1.KluctlDeployment, rr *kluctlv1.ManualRequestResult, requestAnnotation string) error {
+	if r.ResultStore == nil {
+		return nil
+	}
+
+	clusterId, err := k8s.GetClusterId(ctx, r.Client)
+	if err != nil {
+		return err
+	}
+
+	// the user and source from the annotation can not be trusted, so they are only recorded as claimed values
+	e := &result.AuditEvent{
+		Id:            uuid.NewString(),
+		Time:          rr.StartTime,
+		Source:        result.AuditSourceAnnotation,
+		Action:        manualRequestAuditActions[requestAnnotation],
+		ClaimedUser:   rr.Request.RequestedBy,
+		ClaimedSource: rr.Request.Source,
+		FieldManager:  rr.RequestFieldManager,
+		KluctlDeployment: result.KluctlDeploymentInfo{
+			Name:      obj.Name,
+			Namespace: obj.Namespace,
+			ClusterId: clusterId,
+		},
+		ProjectKey:      obj.Status.ProjectKey,
+		TargetKey:       obj.Status.TargetKey,
+		RequestValue:    rr.Request.RequestValue,
+		ReconcileId:     rr.ReconcileId,
+		CommandResultId: rr.ResultId,
+		Error:           rr.CommandError,
+	}
+
+	ctrl.LoggerFrom(ctx).Info(fmt.Sprintf("Writing audit event %s", e.Id))
+	return r.ResultStore.WriteAuditEvent(e)
 }
 
 func (r *KluctlDeploymentReconciler) applyOverridePatch(ctx context.Context, obj *kluctlv1.KluctlDeployment, mr *kluctlv1.ManualRequest) error {
@@ -189,7 +275,7 @@ func (r *KluctlDeploymentReconciler) reconcileManualRequest(ctx context.Context,
 		if err2 != nil {
 			err = multierror.Append(err, err2)
 		}
-		err2 = r.finishHandleManualRequest(ctx, obj, req, resultId, err, getResultPtr)
+		err2 = r.finishHandleManualRequest(ctx, obj, req, annotationName, resultId, err, getResultPtr)
 		if err2 != nil {
 			err = multierror.Append(err, err2)
 		}
@@ -225,7 +311,7 @@ func (r *KluctlDeploymentReconciler) reconcileManualRequest(ctx context.Context,
 		resultId = x.Id
 	}
 
-	err = r.finishHandleManualRequest(ctx, obj, req, resultId, cmdErr, getResultPtr)
+	err = r.finishHandleManualRequest(ctx, obj, req, annotationName, resultId, cmdErr, getResultPtr)
 	if err != nil {
 		if cmdErr != nil {
 			err = multierror.Append(err, cmdErr)
@@ -485,6 +571,17 @@ func (r *KluctlDeploymentReconciler) reconcileFullRequest2(rr *kluctlv1.ManualRe
 			return nil, kluctlv1.ValidateFailedReason, err
 		}
 		validateResult := pt.kluctlValidate(targetContext, deployResult)
+		if obj.Spec.HelmTest && deployResult != nil && !obj.Spec.DryRun {
+			err = r.patchProgressingCondition(ctx, obj, "Performing kluctl helm-test", false)
+			if err != nil {
+				return nil, kluctlv1.ValidateFailedReason, err
+			}
+			helmTestResult := pt.kluctlHelmTest(targetContext)
+			validateResult.HelmTests = append(validateResult.HelmTests, helmTestResult.HelmTests...)
+			validateResult.Errors = append(validateResult.Errors, helmTestResult.Errors...)
+			validateResult.Warnings = append(validateResult.Warnings, helmTestResult.Warnings...)
+			validateResult.Ready = validateResult.Ready && helmTestResult.Ready
+		}
 		err = pt.writeValidateResult(ctx, validateResult, rr, reconcileId, objectsHash)
 		if err != nil {
 			log.Error(err, "Failed to write deploy result")
@@ -516,6 +613,7 @@ func (r *KluctlDeploymentReconciler) reconcileFullRequest2(rr *kluctlv1.ManualRe
 		}
 		driftDetectionResult := diffResult.BuildDriftDetectionResult()
 		obj.Status.SetLastDriftDetectionResult(driftDetectionResult)
+		pt.exportDriftDetectionResultMetricsToProm(driftDetectionResult)
 
 		err = r.buildErrorFromResult(diffResult.Errors, diffResult.Warnings, "diff")
 		if err != nil {
@@ -15,6 +15,14 @@ const (
 	PruneDurationKey          = "prune_duration_seconds"
 	DeleteDurationKey         = "delete_duration_seconds"
 	ValidateDurationKey       = "validate_duration_seconds"
+
+	ValidateErrorsKey                = "validate_errors"
+	ValidateWarningsKey              = "validate_warnings"
+	ValidateReadyKey                 = "validate_ready"
+	DriftedObjectsKey                = "drifted_objects"
+	LastSuccessfulDeployTimestampKey = "last_successful_deploy_timestamp_seconds"
+	SourceFetchDurationKey           = "source_fetch_duration_seconds"
+	SourceCacheRequestsKey           = "source_cache_requests_total"
 )
 
 var (
@@ -71,6 +79,48 @@ var (
 		Name:      ValidateDurationKey,
 		Help:      "How long a single validate takes in seconds.",
 	}, []string{"namespace", "name"})
+
+	validateErrors = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      ValidateErrorsKey,
+		Help:      "How many validation errors (e.g. unready objects) were reported by the last validation of a single deployment.",
+	}, objectLabelNames)
+
+	validateWarnings = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      ValidateWarningsKey,
+		Help:      "How many validation warnings were reported by the last validation of a single deployment.",
+	}, objectLabelNames)
+
+	validateReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      ValidateReadyKey,
+		Help:      "Was the last validation of a single deployment successful. Zero means not ready and one means ready.",
+	}, []string{"namespace", "name"})
+
+	driftedObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      DriftedObjectsKey,
+		Help:      "How many objects were found to be drifted by the last drift detection of a single deployment.",
+	}, objectLabelNames)
+
+	lastSuccessfulDeployTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      LastSuccessfulDeployTimestampKey,
+		Help:      "Unix timestamp of the last successful deployment of a single deployment.",
+	}, []string{"namespace", "name"})
+
+	sourceFetchDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      SourceFetchDurationKey,
+		Help:      "How long fetching the source of a single deployment takes in seconds.",
+	}, []string{"namespace", "name", "source_type"})
+
+	sourceCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KluctlDeploymentControllerSubsystem,
+		Name:      SourceCacheRequestsKey,
+		Help:      "How many git and OCI repositories were requested from the source cache. The result label is either 'hit' or 'miss'.",
+	}, []string{"namespace", "name", "source_type", "result"})
 )
 
 func init() {
@@ -83,6 +133,13 @@ func init() {
 	metrics.Registry.MustRegister(pruneDuration)
 	metrics.Registry.MustRegister(deleteDuration)
 	metrics.Registry.MustRegister(validateDuration)
+	metrics.Registry.MustRegister(validateErrors)
+	metrics.Registry.MustRegister(validateWarnings)
+	metrics.Registry.MustRegister(validateReady)
+	metrics.Registry.MustRegister(driftedObjects)
+	metrics.Registry.MustRegister(lastSuccessfulDeployTimestamp)
+	metrics.Registry.MustRegister(sourceFetchDuration)
+	metrics.Registry.MustRegister(sourceCacheRequests)
 }
 
 func NewKluctlDeploymentDuration(namespace string, name string, mode string) prometheus.Observer {
@@ -120,3 +177,60 @@ func NewKluctlDeleteDuration(namespace string, name string) prometheus.Observer
 func NewKluctlValidateDuration(namespace string, name string) prometheus.Observer {
 	return validateDuration.WithLabelValues(namespace, name)
 }
+
+func NewKluctlValidateErrors(namespace string, name string, ol ObjectLabelValues) prometheus.Gauge {
+	return validateErrors.WithLabelValues(ol.labelValues(namespace, name)...)
+}
+
+func NewKluctlValidateWarnings(namespace string, name string, ol ObjectLabelValues) prometheus.Gauge {
+	return validateWarnings.WithLabelValues(ol.labelValues(namespace, name)...)
+}
+
+func NewKluctlValidateReady(namespace string, name string) prometheus.Gauge {
+	return validateReady.WithLabelValues(namespace, name)
+}
+
+func NewKluctlDriftedObjects(namespace string, name string, ol ObjectLabelValues) prometheus.Gauge {
+	return driftedObjects.WithLabelValues(ol.labelValues(namespace, name)...)
+}
+
+func NewKluctlLastSuccessfulDeployTimestamp(namespace string, name string) prometheus.Gauge {
+	return lastSuccessfulDeployTimestamp.WithLabelValues(namespace, name)
+}
+
+func NewKluctlSourceFetchDuration(namespace string, name string, sourceType string) prometheus.Observer {
+	return sourceFetchDuration.WithLabelValues(namespace, name, sourceType)
+}
+
+func NewKluctlSourceCacheRequests(namespace string, name string, sourceType string, hit bool) prometheus.Counter {
+	r := "miss"
+	if hit {
+		r = "hit"
+	}
+	return sourceCacheRequests.WithLabelValues(namespace, name, sourceType, r)
+}
+
+// ResetKluctlValidateObjectMetrics removes all per-object validation metrics of a single deployment. This must be
+// called before the metrics of a new validation are set, as objects might have vanished in the meantime.
+func ResetKluctlValidateObjectMetrics(namespace string, name string) {
+	l := prometheus.Labels{"namespace": namespace, "name": name}
+	validateErrors.DeletePartialMatch(l)
+	validateWarnings.DeletePartialMatch(l)
+}
+
+// ResetKluctlDriftObjectMetrics removes all per-object drift metrics of a single deployment.
+func ResetKluctlDriftObjectMetrics(namespace string, name string) {
+	driftedObjects.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
+
+// DeleteKluctlDeploymentMetrics removes all metrics of a single deployment that would otherwise report stale values
+// forever after the deployment was deleted.
+func DeleteKluctlDeploymentMetrics(namespace string, name string) {
+	ResetKluctlValidateObjectMetrics(namespace, name)
+	ResetKluctlDriftObjectMetrics(namespace, name)
+	l := prometheus.Labels{"namespace": namespace, "name": name}
+	validateReady.DeletePartialMatch(l)
+	lastSuccessfulDeployTimestamp.DeletePartialMatch(l)
+	sourceFetchDuration.DeletePartialMatch(l)
+	sourceCacheRequests.DeletePartialMatch(l)
+}
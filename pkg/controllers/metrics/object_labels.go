@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+)
+
+// ObjectLabels configures which labels of the affected objects are added to per-object metrics, e.g. validation errors
+// and drifted objects. More labels allow more specific alerts, but also increase the cardinality of the metrics.
+type ObjectLabels string
+
+const (
+	// ObjectLabelsNone only exports totals per deployment
+	ObjectLabelsNone ObjectLabels = "none"
+	// ObjectLabelsKind adds the object group and kind
+	ObjectLabelsKind ObjectLabels = "kind"
+	// ObjectLabelsNamespace adds the object group, kind and namespace
+	ObjectLabelsNamespace ObjectLabels = "namespace"
+	// ObjectLabelsName adds the object group, kind, namespace and name
+	ObjectLabelsName ObjectLabels = "name"
+)
+
+var objectLabelNames = []string{"namespace", "name", "object_group", "object_kind", "object_namespace", "object_name"}
+
+func ParseObjectLabels(s string) (ObjectLabels, error) {
+	switch ObjectLabels(s) {
+	case ObjectLabelsNone, ObjectLabelsKind, ObjectLabelsNamespace, ObjectLabelsName:
+		return ObjectLabels(s), nil
+	default:
+		return "", fmt.Errorf("invalid object labels '%s', must be one of none, kind, namespace or name", s)
+	}
+}
+
+// ObjectLabelValues holds the object label values of a per-object metric. Labels that are not enabled by ObjectLabels
+// are left empty.
+type ObjectLabelValues struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// Values returns the label values for the given object ref, according to the configured ObjectLabels.
+func (l ObjectLabels) Values(ref k8s.ObjectRef) ObjectLabelValues {
+	var ret ObjectLabelValues
+	switch l {
+	case ObjectLabelsName:
+		ret.Name = ref.Name
+		fallthrough
+	case ObjectLabelsNamespace:
+		ret.Namespace = ref.Namespace
+		fallthrough
+	case ObjectLabelsKind:
+		ret.Group = ref.Group
+		ret.Kind = ref.Kind
+	}
+	return ret
+}
+
+func (v ObjectLabelValues) labelValues(namespace string, name string) []string {
+	return []string{namespace, name, v.Group, v.Kind, v.Namespace, v.Name}
+}
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"fmt"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/csaupgrade"
+	"sort"
+	"sync"
+)
+
+// DefaultAdoptFieldManagers is the list of field managers from which field ownership is transferred to kluctl when
+// no explicit list is provided. These are the managers used by Helm and the most common kubectl sub-commands.
+var DefaultAdoptFieldManagers = []string{
+	"helm",
+	"kubectl-client-side-apply",
+	"kubectl-create",
+	"kubectl-edit",
+	"kubectl-patch",
+	"kubectl-replace",
+}
+
+type AdoptCandidate struct {
+	Ref k8s2.ObjectRef
+
+	// FieldManagers contains all field managers that currently own fields of the object
+	FieldManagers []string
+	// TransferManagers contains the field managers from which field ownership will be transferred to kluctl
+	TransferManagers []string
+}
+
+type AdoptCommand struct {
+	targetCtx *target_context.TargetContext
+
+	FieldManagers      []string
+	RemoveHelmMetadata bool
+}
+
+func NewAdoptCommand(targetCtx *target_context.TargetContext) *AdoptCommand {
+	return &AdoptCommand{
+		targetCtx: targetCtx,
+	}
+}
+
+func (cmd *AdoptCommand) Run(confirmCb func(candidates []AdoptCandidate) error) *result.CommandResult {
+	var wg sync.WaitGroup
+
+	dew := utils2.NewDeploymentErrorsAndWarnings()
+
+	r := newCommandResult(cmd.targetCtx, cmd.targetCtx.KluctlProject.LoadTime, "adopt")
+
+	defer func() {
+		finishCommandResult(r, cmd.targetCtx, dew)
+	}()
+
+	discriminator := cmd.targetCtx.Target.Discriminator
+	if discriminator == "" {
+		dew.AddError(k8s2.ObjectRef{}, fmt.Errorf("adopting without a discriminator is not supported"))
+		return r
+	}
+
+	ru := utils2.NewRemoteObjectsUtil(cmd.targetCtx.SharedContext.Ctx, dew)
+	err := ru.UpdateRemoteObjects(cmd.targetCtx.SharedContext.K, &discriminator, cmd.targetCtx.DeploymentCollection.LocalObjectRefs(), false)
+	if err != nil {
+		dew.AddError(k8s2.ObjectRef{}, err)
+		return r
+	}
+
+	fieldManagers := cmd.FieldManagers
+	if len(fieldManagers) == 0 {
+		fieldManagers = DefaultAdoptFieldManagers
+	}
+	transferManagers := sets.New(fieldManagers...)
+
+	var candidates []AdoptCandidate
+	for _, d := range cmd.targetCtx.DeploymentCollection.Deployments {
+		for _, o := range d.Objects {
+			if o.GetK8sAnnotation("kluctl.io/hook") != nil || o.GetK8sAnnotation("helm.sh/hook") != nil {
+				// hooks are re-created by kluctl anyway
+				continue
+			}
+			ref := o.GetK8sRef()
+			remote := ru.GetRemoteObject(ref)
+			if remote == nil {
+				// will be created by kluctl
+				continue
+			}
+			if x := remote.GetK8sLabel("kluctl.io/discriminator"); x != nil && *x == discriminator {
+				// already managed by this target
+				continue
+			}
+			candidates = append(candidates, buildAdoptCandidate(ref, remote, transferManagers))
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Ref.String() < candidates[j].Ref.String()
+	})
+
+	if confirmCb != nil {
+		err = confirmCb(candidates)
+		if err != nil {
+			dew.AddError(k8s2.ObjectRef{}, err)
+			return r
+		}
+	}
+
+	au := utils2.NewApplyDeploymentsUtil(cmd.targetCtx.SharedContext.Ctx, dew, ru, cmd.targetCtx.SharedContext.K, &utils2.ApplyUtilOptions{})
+
+	for _, c := range candidates {
+		c := c
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			au := au.NewApplyUtil(cmd.targetCtx.SharedContext.Ctx, nil)
+			au.ReplaceObject(c.Ref, ru.GetRemoteObject(c.Ref), func(o *uo.UnstructuredObject) (*uo.UnstructuredObject, error) {
+				return cmd.adoptObject(o, discriminator, c.TransferManagers)
+			})
+		}()
+	}
+	wg.Wait()
+
+	du := utils2.NewDiffUtil(dew, ru, au.GetAppliedObjectsMap())
+	du.DiffDeploymentItems(cmd.targetCtx.DeploymentCollection.Deployments)
+
+	r.Objects = collectObjects(cmd.targetCtx.DeploymentCollection, ru, au, du, nil, nil)
+
+	return r
+}
+
+func buildAdoptCandidate(ref k8s2.ObjectRef, remote *uo.UnstructuredObject, transferManagers sets.Set[string]) AdoptCandidate {
+	c := AdoptCandidate{
+		Ref: ref,
+	}
+	allManagers := sets.New[string]()
+	toTransfer := sets.New[string]()
+	for _, mf := range remote.ToUnstructured().GetManagedFields() {
+		allManagers.Insert(mf.Manager)
+		if mf.Manager == "kluctl" {
+			continue
+		}
+		// csaupgrade only handles managers of the Update operation on the main resource
+		if mf.Operation == metav1.ManagedFieldsOperationUpdate && mf.Subresource == "" && transferManagers.Has(mf.Manager) {
+			toTransfer.Insert(mf.Manager)
+		}
+	}
+	c.FieldManagers = sets.List(allManagers)
+	c.TransferManagers = sets.List(toTransfer)
+	return c
+}
+
+func (cmd *AdoptCommand) adoptObject(o *uo.UnstructuredObject, discriminator string, transferManagers []string) (*uo.UnstructuredObject, error) {
+	u := o.ToUnstructured()
+	if len(transferManagers) != 0 {
+		err := csaupgrade.UpgradeManagedFields(u, sets.New(transferManagers...), "kluctl")
+		if err != nil {
+			return nil, err
+		}
+	}
+	o = uo.FromUnstructured(u)
+
+	o.SetK8sLabel("kluctl.io/discriminator", discriminator)
+
+	if cmd.RemoveHelmMetadata {
+		if x := o.GetK8sLabel("app.kubernetes.io/managed-by"); x != nil && *x == "Helm" {
+			o.RemoveK8sLabel("app.kubernetes.io/managed-by")
+		}
+		o.RemoveK8sAnnotation("meta.helm.sh/release-name")
+		o.RemoveK8sAnnotation("meta.helm.sh/release-namespace")
+	}
+	return o, nil
+}
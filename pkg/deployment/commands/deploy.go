@@ -21,6 +21,9 @@ type DeployCommand struct {
 	NoWait              bool
 	Prune               bool
 	WaitPrune           bool
+
+	ApplyConcurrency     int
+	ApplyItemConcurrency int
 }
 
 func NewDeployCommand(targetCtx *target_context.TargetContext) *DeployCommand {
@@ -29,7 +32,10 @@ func NewDeployCommand(targetCtx *target_context.TargetContext) *DeployCommand {
 	}
 }
 
-func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult) error) *result.CommandResult {
+// Run performs the deployment. If diffResultCb is not nil, a dry-run diff is performed first and passed to the
+// callback. The callback can abort the deployment by returning an error and can exclude individual objects from
+// being applied by returning their refs.
+func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult) ([]k8s2.ObjectRef, error)) *result.CommandResult {
 	dew := utils2.NewDeploymentErrorsAndWarnings()
 
 	r := newCommandResult(cmd.targetCtx, cmd.targetCtx.KluctlProject.LoadTime, "deploy")
@@ -64,6 +70,8 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 		AbortOnError:        false,
 		ReadinessTimeout:    cmd.ReadinessTimeout,
 		NoWait:              cmd.NoWait,
+		Concurrency:         cmd.ApplyConcurrency,
+		ItemConcurrency:     cmd.ApplyItemConcurrency,
 	}
 
 	if diffResultCb != nil {
@@ -76,17 +84,25 @@ func (cmd *DeployCommand) Run(diffResultCb func(diffResult *result.CommandResult
 
 		orphanObjects, err := FindOrphanObjects(cmd.targetCtx.SharedContext.K, ru, cmd.targetCtx.DeploymentCollection)
 		diffResult := &result.CommandResult{
-			Objects:    collectObjects(cmd.targetCtx.DeploymentCollection, ru, au, du, orphanObjects, nil),
-			Errors:     diffDew.GetErrorsList(),
-			Warnings:   diffDew.GetWarningsList(),
-			SeenImages: cmd.targetCtx.DeploymentCollection.Images.SeenImages(false),
+			Objects:         collectObjects(cmd.targetCtx.DeploymentCollection, ru, au, du, orphanObjects, nil),
+			Errors:          diffDew.GetErrorsList(),
+			Warnings:        diffDew.GetWarningsList(),
+			SeenImages:      cmd.targetCtx.DeploymentCollection.Images.SeenImages(false),
+			HelmLookups:     cmd.targetCtx.DeploymentCollection.HelmLookups(),
+			SensitiveFields: cmd.targetCtx.DeploymentCollection.SensitiveFields,
 		}
 
-		err = diffResultCb(diffResult)
+		skipObjects, err := diffResultCb(diffResult)
 		if err != nil {
 			dew.AddError(k8s2.ObjectRef{}, err)
 			return r
 		}
+		if len(skipObjects) != 0 {
+			o.SkipObjects = map[k8s2.ObjectRef]bool{}
+			for _, ref := range skipObjects {
+				o.SkipObjects[ref] = true
+			}
+		}
 	}
 
 	// modify options to become a deploy
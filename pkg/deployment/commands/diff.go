@@ -19,6 +19,9 @@ type DiffCommand struct {
 	IgnoreLabels        bool
 	IgnoreAnnotations   bool
 
+	ApplyConcurrency     int
+	ApplyItemConcurrency int
+
 	SkipResourceVersions map[k8s2.ObjectRef]string
 }
 
@@ -60,6 +63,8 @@ func (cmd *DiffCommand) Run() *result.CommandResult {
 		AbortOnError:         false,
 		ReadinessTimeout:     0,
 		SkipResourceVersions: cmd.SkipResourceVersions,
+		Concurrency:          cmd.ApplyConcurrency,
+		ItemConcurrency:      cmd.ApplyItemConcurrency,
 	}
 	au := utils.NewApplyDeploymentsUtil(cmd.targetCtx.SharedContext.Ctx, dew, ru, cmd.targetCtx.SharedContext.K, o)
 	au.ApplyDeployments(cmd.targetCtx.DeploymentCollection.Deployments)
@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/helm"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// HelmImportRelease describes a Helm release that was found in the cluster and that matches a helm-chart.yaml
+// of the project.
+type HelmImportRelease struct {
+	ReleaseName       string
+	Namespace         string
+	DeploymentItemDir string
+
+	InstalledChartName    string
+	InstalledChartVersion string
+	ChartName             string
+	ChartVersion          string
+
+	// ValueChanges contains the differences between the values of the installed release and the project's
+	// helm-values.yaml
+	ValueChanges []result.Change
+
+	// NotAdoptedObjects contains all objects of the deployment item which are not yet managed by the target
+	NotAdoptedObjects []k8s2.ObjectRef
+
+	// ManifestOnlyObjects contains all objects of the installed release which are not rendered by the project anymore,
+	// e.g. because they were removed in a newer chart version. These would be orphaned by importing the release.
+	ManifestOnlyObjects []k8s2.ObjectRef
+
+	// SecretRefs contains all Helm release Secrets, which are deleted when importing the release
+	SecretRefs []k8s2.ObjectRef
+}
+
+func (r *HelmImportRelease) HasDifferences() bool {
+	return r.InstalledChartName != r.ChartName || r.InstalledChartVersion != r.ChartVersion || len(r.ValueChanges) != 0 || len(r.ManifestOnlyObjects) != 0
+}
+
+// CanImport returns true if all objects of the release got adopted by kluctl and no object of the installed release
+// would be orphaned
+func (r *HelmImportRelease) CanImport() bool {
+	return len(r.NotAdoptedObjects) == 0 && len(r.ManifestOnlyObjects) == 0
+}
+
+type HelmImportCommand struct {
+	targetCtx *target_context.TargetContext
+}
+
+func NewHelmImportCommand(targetCtx *target_context.TargetContext) *HelmImportCommand {
+	return &HelmImportCommand{
+		targetCtx: targetCtx,
+	}
+}
+
+func (cmd *HelmImportCommand) Run(confirmCb func(releases []*HelmImportRelease) error) *result.CommandResult {
+	dew := utils2.NewDeploymentErrorsAndWarnings()
+
+	r := newCommandResult(cmd.targetCtx, cmd.targetCtx.KluctlProject.LoadTime, "helm-import")
+
+	defer func() {
+		finishCommandResult(r, cmd.targetCtx, dew)
+	}()
+
+	discriminator := cmd.targetCtx.Target.Discriminator
+	if discriminator == "" {
+		dew.AddError(k8s2.ObjectRef{}, fmt.Errorf("importing Helm releases without a discriminator is not supported"))
+		return r
+	}
+
+	ru := utils2.NewRemoteObjectsUtil(cmd.targetCtx.SharedContext.Ctx, dew)
+	err := ru.UpdateRemoteObjects(cmd.targetCtx.SharedContext.K, &discriminator, cmd.targetCtx.DeploymentCollection.LocalObjectRefs(), false)
+	if err != nil {
+		dew.AddError(k8s2.ObjectRef{}, err)
+		return r
+	}
+
+	var releases []*HelmImportRelease
+	for _, d := range cmd.targetCtx.DeploymentCollection.Deployments {
+		hrs, err := d.ListHelmReleases()
+		if err != nil {
+			dew.AddError(k8s2.ObjectRef{}, err)
+			continue
+		}
+		for _, hr := range hrs {
+			x, err := cmd.buildImportRelease(d, hr, ru, discriminator)
+			if err != nil {
+				dew.AddError(k8s2.ObjectRef{}, fmt.Errorf("helm release %s: %w", hr.Config.ReleaseName, err))
+				continue
+			}
+			if x != nil {
+				releases = append(releases, x)
+			}
+		}
+	}
+	if len(dew.GetErrorsList()) != 0 {
+		return r
+	}
+
+	if confirmCb != nil {
+		err = confirmCb(releases)
+		if err != nil {
+			dew.AddError(k8s2.ObjectRef{}, err)
+			return r
+		}
+	}
+
+	var toDelete []k8s2.ObjectRef
+	for _, x := range releases {
+		if len(x.NotAdoptedObjects) != 0 {
+			dew.AddWarning(k8s2.ObjectRef{}, fmt.Errorf("helm release %s in namespace %s has %d objects that are not adopted yet, skipping import", x.ReleaseName, x.Namespace, len(x.NotAdoptedObjects)))
+		}
+		if len(x.ManifestOnlyObjects) != 0 {
+			dew.AddWarning(k8s2.ObjectRef{}, fmt.Errorf("helm release %s in namespace %s has %d objects that are not rendered by the project, skipping import", x.ReleaseName, x.Namespace, len(x.ManifestOnlyObjects)))
+		}
+		if !x.CanImport() {
+			continue
+		}
+		toDelete = append(toDelete, x.SecretRefs...)
+	}
+
+	deleted := utils2.DeleteObjects(cmd.targetCtx.SharedContext.Ctx, cmd.targetCtx.SharedContext.K, toDelete, dew, false)
+
+	r.Objects = collectObjects(cmd.targetCtx.DeploymentCollection, ru, nil, nil, nil, deleted)
+
+	return r
+}
+
+func (cmd *HelmImportCommand) buildImportRelease(d *deployment.DeploymentItem, hr *helm.Release, ru *utils2.RemoteObjectUtils, discriminator string) (*HelmImportRelease, error) {
+	namespace := ""
+	if hr.Config.Namespace != nil {
+		namespace = *hr.Config.Namespace
+	}
+
+	installed, err := helm.ListInstalledReleases(cmd.targetCtx.SharedContext.K, hr.Config.ReleaseName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if len(installed) == 0 {
+		return nil, nil
+	}
+	if len(installed) != 1 {
+		return nil, fmt.Errorf("found multiple installed releases with the same name, please specify the namespace in helm-chart.yaml")
+	}
+	ir := installed[0]
+	if ir.Release == nil || ir.Release.Chart == nil || ir.Release.Chart.Metadata == nil {
+		return nil, fmt.Errorf("no deployed revision found in namespace %s", ir.Namespace)
+	}
+
+	x := &HelmImportRelease{
+		ReleaseName:           ir.Name,
+		Namespace:             ir.Namespace,
+		DeploymentItemDir:     d.RelToProjectItemDir,
+		InstalledChartName:    ir.Release.Chart.Metadata.Name,
+		InstalledChartVersion: ir.Release.Chart.Metadata.Version,
+		ChartName:             hr.Chart.GetChartName(),
+		ChartVersion:          hr.Config.ChartVersion,
+		SecretRefs:            ir.SecretRefs,
+	}
+	if hr.Chart.IsLocalChart() {
+		x.ChartVersion, err = hr.Chart.GetLocalChartVersion()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	values, err := hr.LoadValues(cmd.targetCtx.SharedContext.Ctx, cmd.targetCtx.SharedContext.SopsDecrypter)
+	if err != nil {
+		return nil, err
+	}
+	installedValues := ir.Release.Config
+	if installedValues == nil {
+		installedValues = map[string]interface{}{}
+	}
+	x.ValueChanges, err = diff.Diff(uo.FromMap(installedValues), uo.FromMap(values))
+	if err != nil {
+		return nil, err
+	}
+
+	manifestObjects, err := ir.GetManifestObjects()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest of installed release: %w", err)
+	}
+	type key struct {
+		gk        schema.GroupKind
+		namespace string
+		name      string
+	}
+	rendered := map[key]bool{}
+	for _, o := range d.Objects {
+		ref := o.GetK8sRef()
+		rendered[key{gk: ref.GroupKind(), namespace: ref.Namespace, name: ref.Name}] = true
+	}
+	for _, o := range manifestObjects {
+		ref := o.GetK8sRef()
+		if ref.Namespace == "" {
+			// Helm installs namespaced objects without a namespace into the release namespace
+			isNamespaced := cmd.targetCtx.SharedContext.K.IsNamespaced(ref.GroupVersionKind())
+			if isNamespaced == nil || *isNamespaced {
+				ref.Namespace = ir.Namespace
+			}
+		}
+		if !rendered[key{gk: ref.GroupKind(), namespace: ref.Namespace, name: ref.Name}] {
+			x.ManifestOnlyObjects = append(x.ManifestOnlyObjects, ref)
+		}
+	}
+
+	for _, o := range d.Objects {
+		ref := o.GetK8sRef()
+		remote := ru.GetRemoteObject(ref)
+		if remote == nil {
+			continue
+		}
+		if l := remote.GetK8sLabel("kluctl.io/discriminator"); l == nil || *l != discriminator {
+			x.NotAdoptedObjects = append(x.NotAdoptedObjects, ref)
+		}
+	}
+
+	return x, nil
+}
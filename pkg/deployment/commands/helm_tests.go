@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	utils2 "github.com/kluctl/kluctl/v2/pkg/deployment/utils"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HelmTestCommand runs the test hooks (helm.sh/hook: test) of all Helm Charts found in the deployment, which is the
+// equivalent of `helm test`
+type HelmTestCommand struct {
+	targetCtx *target_context.TargetContext
+
+	// Timeout specifies how long to wait for a single test to finish
+	Timeout time.Duration
+	// NoCleanup causes test objects to not be deleted after the tests have finished
+	NoCleanup bool
+
+	dew *utils2.DeploymentErrorsAndWarnings
+	ru  *utils2.RemoteObjectUtils
+}
+
+func NewHelmTestCommand(targetCtx *target_context.TargetContext) *HelmTestCommand {
+	cmd := &HelmTestCommand{
+		targetCtx: targetCtx,
+		Timeout:   5 * time.Minute,
+		dew:       utils2.NewDeploymentErrorsAndWarnings(),
+	}
+	cmd.ru = utils2.NewRemoteObjectsUtil(targetCtx.SharedContext.Ctx, cmd.dew)
+	return cmd
+}
+
+func (cmd *HelmTestCommand) Run(ctx context.Context) *result.ValidateResult {
+	ret := newValidateCommandResult(cmd.targetCtx, time.Now())
+	ret.Ready = true
+
+	defer func() {
+		finishValidateResult(ret, cmd.targetCtx, cmd.dew)
+	}()
+
+	ad := utils2.NewApplyDeploymentsUtil(ctx, cmd.dew, cmd.ru, cmd.targetCtx.SharedContext.K, &utils2.ApplyUtilOptions{
+		ReadinessTimeout: cmd.Timeout,
+	})
+
+	for _, d := range cmd.targetCtx.DeploymentCollection.Deployments {
+		if !d.CheckInclusionForDeploy() {
+			continue
+		}
+		for _, th := range d.HelmTestHooks {
+			ret.HelmTests = append(ret.HelmTests, cmd.runReleaseTests(ctx, ad, th)...)
+		}
+	}
+
+	for _, r := range ret.HelmTests {
+		if !r.Succeeded {
+			ret.Ready = false
+		}
+	}
+
+	return ret
+}
+
+func (cmd *HelmTestCommand) runReleaseTests(ctx context.Context, ad *utils2.ApplyDeploymentsUtil, th deployment.HelmTestHooks) []result.HelmTestResult {
+	k := cmd.targetCtx.SharedContext.K
+
+	s := status.Startf(ctx, "Running Helm tests for release %s", th.ReleaseName)
+	defer s.Failed()
+
+	au := ad.NewApplyUtil(ctx, s)
+	objects := cmd.sortTestHooks(th.Objects)
+
+	var ret []result.HelmTestResult
+	failed := false
+	for _, o := range objects {
+		ref := o.GetK8sRef()
+
+		// test objects are always re-created, as Pods and Jobs can not be updated
+		au.DeleteObject(ref, true)
+		au.ApplyObject(o, true, true)
+		if au.HadError(ref) {
+			ret = append(ret, result.HelmTestResult{ReleaseName: th.ReleaseName, Ref: ref})
+			failed = true
+			continue
+		}
+		if !isHelmTestWorkload(ref) {
+			continue
+		}
+
+		s.UpdateAndInfoFallbackf("Waiting for Helm test %s", ref.String())
+		r := result.HelmTestResult{
+			ReleaseName: th.ReleaseName,
+			Ref:         ref,
+			Succeeded:   au.WaitReadiness(ref, cmd.Timeout),
+		}
+
+		logs, err := cmd.collectLogs(k, ref)
+		if err != nil {
+			cmd.dew.AddWarning(ref, fmt.Errorf("failed to retrieve logs: %w", err))
+		}
+		r.Logs = logs
+
+		if r.Succeeded {
+			s.InfoFallbackf("Helm test %s succeeded", ref.String())
+		} else {
+			s.InfoFallbackf("Helm test %s failed", ref.String())
+			failed = true
+		}
+		ret = append(ret, r)
+	}
+
+	if !cmd.NoCleanup {
+		for i := len(objects) - 1; i >= 0; i-- {
+			au.DeleteObject(objects[i].GetK8sRef(), true)
+		}
+	}
+
+	if !failed {
+		s.Success()
+	}
+	return ret
+}
+
+// sortTestHooks sorts the test hooks by their hook weight, the same way Helm does it
+func (cmd *HelmTestCommand) sortTestHooks(objects []*uo.UnstructuredObject) []*uo.UnstructuredObject {
+	weights := map[*uo.UnstructuredObject]int{}
+	for _, o := range objects {
+		weightStr := o.GetK8sAnnotation("helm.sh/hook-weight")
+		if weightStr == nil {
+			continue
+		}
+		weight, err := strconv.ParseInt(*weightStr, 10, 32)
+		if err != nil {
+			cmd.dew.AddWarning(o.GetK8sRef(), fmt.Errorf("failed to parse hook weight: %w", err))
+			continue
+		}
+		weights[o] = int(weight)
+	}
+
+	ret := append([]*uo.UnstructuredObject(nil), objects...)
+	sort.SliceStable(ret, func(i, j int) bool {
+		if weights[ret[i]] != weights[ret[j]] {
+			return weights[ret[i]] < weights[ret[j]]
+		}
+		return ret[i].GetK8sName() < ret[j].GetK8sName()
+	})
+	return ret
+}
+
+func isHelmTestWorkload(ref k8s2.ObjectRef) bool {
+	switch ref.GroupKind() {
+	case schema.GroupKind{Group: "", Kind: "Pod"}, schema.GroupKind{Group: "batch", Kind: "Job"}:
+		return true
+	}
+	return false
+}
+
+func (cmd *HelmTestCommand) collectLogs(k *k8s.K8sCluster, ref k8s2.ObjectRef) (string, error) {
+	if ref.GroupKind() == (schema.GroupKind{Group: "", Kind: "Pod"}) {
+		return k.GetPodLogs(ref.Namespace, ref.Name)
+	}
+
+	pods, apiWarnings, err := k.ListMetadata(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, ref.Namespace, map[string]string{
+		"job-name": ref.Name,
+	})
+	cmd.dew.AddApiWarnings(ref, apiWarnings)
+	if err != nil {
+		return "", err
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].GetK8sName() < pods[j].GetK8sName()
+	})
+
+	var ret strings.Builder
+	for _, pod := range pods {
+		logs, err := k.GetPodLogs(pod.GetK8sNamespace(), pod.GetK8sName())
+		if err != nil {
+			return ret.String(), err
+		}
+		ret.WriteString(fmt.Sprintf("==> pod %s <==\n", pod.GetK8sName()))
+		ret.WriteString(logs)
+	}
+	return ret.String(), nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/git"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/kluctl_project/target-context"
+	"github.com/kluctl/kluctl/v2/pkg/tracing"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,6 +29,7 @@ func newCommandResult(targetCtx *target_context.TargetContext, startTime time.Ti
 	r.Command.IncludeDeploymentDirs = targetCtx.Params.Inclusion.GetIncludes("deploymentItemDir")
 	r.Command.ExcludeDeploymentDirs = targetCtx.Params.Inclusion.GetExcludes("deploymentItemDir")
 	r.Command.DryRun = targetCtx.Params.DryRun
+	r.Command.TraceId = tracing.TraceId(targetCtx.SharedContext.Ctx)
 
 	r.Deployment = &targetCtx.DeploymentProject.Config
 
@@ -111,6 +113,9 @@ func finishCommandResult(r *result.CommandResult, targetCtx *target_context.Targ
 	r.Warnings = append(r.Warnings, dew.GetWarningsList()...)
 	if targetCtx != nil {
 		r.SeenImages = targetCtx.DeploymentCollection.Images.SeenImages(false)
+		r.HelmLookups = targetCtx.DeploymentCollection.HelmLookups()
+		r.SensitiveFields = targetCtx.DeploymentCollection.SensitiveFields
+		r.Warnings = append(r.Warnings, targetCtx.DeploymentCollection.SensitiveFieldsWarnings()...)
 	}
 	r.Command.EndTime = metav1.Now()
 }
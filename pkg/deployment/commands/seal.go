@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
 	"github.com/kluctl/kluctl/v2/pkg/seal"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"path/filepath"
 )
 
@@ -12,6 +13,27 @@ type SealCommand struct {
 	outputPattern    string
 	renderDir        string
 	sealedSecretsDir string
+
+	// Rotate causes only sealed files to be sealed that are missing or were sealed for a different cert
+	Rotate bool
+	// OutputPatternOverride causes sealed files to be written to the given output pattern instead of the one
+	// configured in the deployment projects
+	OutputPatternOverride string
+}
+
+// SealSummary contains the paths of all sealed files processed by a SealCommand
+type SealSummary struct {
+	// Sealed contains all files that did not exist before
+	Sealed []string
+	// Rotated contains all files that were sealed for outdated certs before
+	Rotated []SealRotatedFile
+	// UpToDate contains all files that were sealed for the current cert before
+	UpToDate []string
+}
+
+type SealRotatedFile struct {
+	Path               string
+	OutdatedCertHashes []string
 }
 
 func NewSealCommand(c *deployment.DeploymentCollection, outputPattern string, renderDir string, sealedSecretsDir string) *SealCommand {
@@ -23,26 +45,53 @@ func NewSealCommand(c *deployment.DeploymentCollection, outputPattern string, re
 	}
 }
 
-func (cmd *SealCommand) Run(sealer *seal.Sealer) error {
+func (cmd *SealCommand) Run(sealer *seal.Sealer) (*SealSummary, error) {
+	var summary SealSummary
+
 	for _, di := range cmd.c.Deployments {
 		sealedSecrets, err := di.ListSealedSecrets("")
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for _, relPath := range sealedSecrets {
 			sealmeFile := filepath.Join(di.RenderedDir, relPath+deployment.SealmeExt)
-			targetFile, err := di.BuildSealedSecretPath(relPath)
+			var targetFile string
+			if cmd.OutputPatternOverride != "" {
+				targetFile, err = di.BuildSealedSecretPathForPattern(relPath, cmd.OutputPatternOverride)
+			} else {
+				targetFile, err = di.BuildSealedSecretPath(relPath)
+			}
 			if err != nil {
-				return err
+				return nil, err
+			}
+
+			if !utils.Exists(targetFile) {
+				summary.Sealed = append(summary.Sealed, targetFile)
+			} else {
+				outdated, err := sealer.GetOutdatedCertHashes(targetFile)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read %s: %w", filepath.Base(targetFile), err)
+				}
+				if len(outdated) == 0 {
+					summary.UpToDate = append(summary.UpToDate, targetFile)
+					if cmd.Rotate {
+						continue
+					}
+				} else {
+					summary.Rotated = append(summary.Rotated, SealRotatedFile{
+						Path:               targetFile,
+						OutdatedCertHashes: outdated,
+					})
+				}
 			}
 
 			err = sealer.SealFile(sealmeFile, targetFile)
 			if err != nil {
-				return fmt.Errorf("failed sealing %s: %w", filepath.Base(relPath), err)
+				return nil, fmt.Errorf("failed sealing %s: %w", filepath.Base(relPath), err)
 			}
 		}
 	}
 
-	return nil
+	return &summary, nil
 }
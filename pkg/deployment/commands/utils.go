@@ -54,6 +54,11 @@ func collectObjects(c *deployment.DeploymentCollection, ru *utils.RemoteObjectUt
 			o := getOrCreate(dn)
 			o.Hook = true
 		}
+		for _, x := range au.GetSkippedObjects() {
+			dn := du.GetDiffRef(x)
+			o := getOrCreate(dn)
+			o.Skipped = true
+		}
 		for _, x := range au.GetDeletedObjects() {
 			dn, ok := remoteDiffNames[x]
 			if !ok {
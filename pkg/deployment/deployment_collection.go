@@ -10,6 +10,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
@@ -28,6 +29,9 @@ type DeploymentCollection struct {
 
 	Deployments []*DeploymentItem
 	mutex       sync.Mutex
+
+	// SensitiveFields contains all fields of rendered objects that contain values from sensitive vars sources
+	SensitiveFields []result.SensitiveField
 }
 
 func NewDeploymentCollection(ctx SharedContext, project *DeploymentProject, images *Images, inclusion *utils.Inclusion, forSeal bool) (*DeploymentCollection, error) {
@@ -342,6 +346,15 @@ func (c *DeploymentCollection) LocalObjectRefs() []k8s2.ObjectRef {
 	return ret
 }
 
+// HelmLookups returns all objects that were looked up while rendering Helm Charts
+func (c *DeploymentCollection) HelmLookups() []result.HelmLookup {
+	var ret []result.HelmLookup
+	for _, d := range c.Deployments {
+		ret = append(ret, d.HelmLookups...)
+	}
+	return ret
+}
+
 func (c *DeploymentCollection) Prepare() error {
 	err := c.RenderDeployments()
 	if err != nil {
@@ -375,6 +388,7 @@ func (c *DeploymentCollection) Prepare() error {
 	if err != nil {
 		return err
 	}
+	c.SensitiveFields = c.findSensitiveFields()
 	return nil
 }
 
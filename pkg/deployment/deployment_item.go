@@ -6,13 +6,16 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/helm"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/sops"
+	"github.com/kluctl/kluctl/v2/pkg/tracing"
 	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/kustomize"
 	securefs "github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/kustomize/filesys"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/vars"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"go.opentelemetry.io/otel/attribute"
 	"io/fs"
 	"os"
 	"path"
@@ -39,6 +42,9 @@ type DeploymentItem struct {
 	Objects []*uo.UnstructuredObject
 	Tags    *utils.OrderedMap[string, bool]
 
+	HelmLookups   []result.HelmLookup
+	HelmTestHooks []HelmTestHooks
+
 	RenderedSourceRootDir string
 	RelToSourceItemDir    string
 	RelToProjectItemDir   string
@@ -47,6 +53,12 @@ type DeploymentItem struct {
 	renderedYamlPath      string
 }
 
+// HelmTestHooks contains the rendered test hooks of a single Helm release
+type HelmTestHooks struct {
+	ReleaseName string
+	Objects     []*uo.UnstructuredObject
+}
+
 func NewDeploymentItem(ctx SharedContext, project *DeploymentProject, collection *DeploymentCollection, config *types.DeploymentItemConfig, dir *string, index int) (*DeploymentItem, error) {
 	di := &DeploymentItem{
 		ctx:       ctx,
@@ -115,12 +127,24 @@ func (di *DeploymentItem) getCommonAnnotations() map[string]string {
 	return a
 }
 
-func (di *DeploymentItem) render(forSeal bool) error {
+// TraceAttributes returns the span attributes used to identify the deployment item.
+func (di *DeploymentItem) TraceAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("kluctl.deployment_item", di.RelToProjectItemDir),
+	}
+}
+
+func (di *DeploymentItem) render(forSeal bool) (err error) {
 	if di.dir == nil {
 		return nil
 	}
 
-	err := os.MkdirAll(di.RenderedDir, 0o700)
+	_, span := tracing.Start(di.ctx.Ctx, "render-templates", di.TraceAttributes()...)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	err = os.MkdirAll(di.RenderedDir, 0o700)
 	if err != nil {
 		return err
 	}
@@ -191,10 +215,11 @@ func (di *DeploymentItem) newHelmRelease(subDir string) (*helm.Release, error) {
 		helmChartsDir = filepath.Join(di.Project.source.dir, ".helm-charts")
 	}
 
-	hr, err := helm.NewRelease(di.ctx.Ctx, di.Project.source.dir, filepath.Join(di.RelToSourceItemDir, subDir), configPath, helmChartsDir, di.ctx.HelmAuthProvider, di.ctx.OciAuthProvider)
+	hr, err := helm.NewRelease(di.ctx.Ctx, di.Project.source.dir, filepath.Join(di.RelToSourceItemDir, subDir), configPath, helmChartsDir, di.ctx.HelmAuthProvider, di.ctx.OciAuthProvider, di.ctx.HelmRequireVerification)
 	if err != nil {
 		return nil, err
 	}
+	hr.AllowExecPostRenderer = di.ctx.HelmAllowExecPostRenderers
 	return hr, nil
 }
 
@@ -235,7 +260,39 @@ func (di *DeploymentItem) renderHelmCharts() error {
 
 		di.Config.RenderedHelmChartConfig = hr.Config
 
-		return hr.Render(di.ctx.Ctx, di.ctx.K, di.ctx.K8sVersion, di.ctx.SopsDecrypter)
+		attrs := append(di.TraceAttributes(),
+			attribute.String("helm.chart", hr.Chart.GetChartName()),
+			attribute.String("helm.release", hr.Config.ReleaseName))
+		ctx, span := tracing.Start(di.ctx.Ctx, "helm-render", attrs...)
+		err = hr.Render(ctx, di.ctx.K, di.ctx.K8sVersion, di.ctx.SopsDecrypter)
+		tracing.End(span, err)
+		if err != nil {
+			return err
+		}
+		di.HelmLookups = append(di.HelmLookups, hr.Lookups...)
+
+		if len(hr.TestHooks) != 0 {
+			namespace := "default"
+			if hr.Config.Namespace != nil {
+				namespace = *hr.Config.Namespace
+			}
+			for _, o := range hr.TestHooks {
+				if o.GetK8sNamespace() != "" {
+					continue
+				}
+				if di.ctx.K != nil {
+					if isNamespaced := di.ctx.K.IsNamespaced(o.GetK8sGVK()); isNamespaced != nil && !*isNamespaced {
+						continue
+					}
+				}
+				o.SetK8sNamespace(namespace)
+			}
+			di.HelmTestHooks = append(di.HelmTestHooks, HelmTestHooks{
+				ReleaseName: hr.Config.ReleaseName,
+				Objects:     hr.TestHooks,
+			})
+		}
+		return nil
 	})
 	if err != nil {
 		return err
@@ -243,6 +300,36 @@ func (di *DeploymentItem) renderHelmCharts() error {
 	return nil
 }
 
+// ListHelmReleases returns all Helm releases found in the rendered deployment item
+func (di *DeploymentItem) ListHelmReleases() ([]*helm.Release, error) {
+	if di.dir == nil {
+		return nil, nil
+	}
+
+	var ret []*helm.Release
+	err := filepath.Walk(di.RenderedDir, func(p string, info fs.FileInfo, err error) error {
+		if !di.isHelmChartYaml(p) {
+			return nil
+		}
+
+		subDir, err := filepath.Rel(di.RenderedDir, filepath.Dir(p))
+		if err != nil {
+			return err
+		}
+
+		hr, err := di.newHelmRelease(subDir)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, hr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
 func (di *DeploymentItem) ListSealedSecrets(subdir string) ([]string, error) {
 	var ret []string
 
@@ -310,7 +397,13 @@ func (di *DeploymentItem) ListSealedSecrets(subdir string) ([]string, error) {
 }
 
 func (di *DeploymentItem) BuildSealedSecretPath(relPath string) (string, error) {
-	sealedSecretsDir := di.Project.getRenderedOutputPattern()
+	return di.BuildSealedSecretPathForPattern(relPath, di.Project.getRenderedOutputPattern())
+}
+
+// BuildSealedSecretPathForPattern is the same as BuildSealedSecretPath, but uses the given output pattern instead
+// of the one configured in the deployment project
+func (di *DeploymentItem) BuildSealedSecretPathForPattern(relPath string, outputPattern string) (string, error) {
+	sealedSecretsDir := outputPattern
 	baseSourcePath := di.Project.ctx.SealedSecretsDir
 
 	relDir := filepath.Dir(relPath)
@@ -505,7 +598,7 @@ func (di *DeploymentItem) prepareKustomizationYaml() (*uo.UnstructuredObject, er
 	return ky, nil
 }
 
-func (di *DeploymentItem) buildKustomize() error {
+func (di *DeploymentItem) buildKustomize() (err error) {
 	if di.dir == nil {
 		return nil
 	}
@@ -513,6 +606,11 @@ func (di *DeploymentItem) buildKustomize() error {
 		return nil
 	}
 
+	_, span := tracing.Start(di.ctx.Ctx, "kustomize-build", di.TraceAttributes()...)
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	ky, err := di.prepareKustomizationYaml()
 	if err != nil {
 		return err
@@ -0,0 +1,224 @@
+package deployment
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minSensitiveValueLen is the minimum length of sensitive values to be matched. Shorter values (e.g. "true", "prod" or
+// small numbers) are usually not secret and would match too many unrelated fields.
+const minSensitiveValueLen = 8
+
+var secretGk = schema.GroupKind{Group: "", Kind: "Secret"}
+
+type sensitiveValue struct {
+	value      string
+	varPath    string
+	varsSource string
+
+	// variants contains the value itself and the encodings of it that are searched for
+	variants []string
+}
+
+// findSensitiveFields searches all rendered objects for fields that contain values originating from sensitive vars
+// sources. This is pure content matching, meaning that a field is reported if it contains a sensitive value (or its
+// base64 encoding) as a whole token, no matter how it got there. Values that were transformed in other ways while
+// templating are not found. Base64 encoded Secret data is decoded before searching.
+func (c *DeploymentCollection) findSensitiveFields() []result.SensitiveField {
+	values := collectSensitiveValues(&c.Project.Config)
+	if len(values) == 0 {
+		return nil
+	}
+
+	var ret []result.SensitiveField
+	for _, d := range c.Deployments {
+		for _, o := range d.Objects {
+			ret = append(ret, findSensitiveFieldsInObject(o, values)...)
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		a, b := ret[i], ret[j]
+		if a.Ref != b.Ref {
+			return a.Ref.String() < b.Ref.String()
+		}
+		if a.JsonPath != b.JsonPath {
+			return a.JsonPath < b.JsonPath
+		}
+		return a.VarPath < b.VarPath
+	})
+	return ret
+}
+
+// SensitiveFieldsWarnings returns a warning for every sensitive field found in an object that is not a Secret
+func (c *DeploymentCollection) SensitiveFieldsWarnings() []result.DeploymentError {
+	var ret []result.DeploymentError
+	for _, f := range c.SensitiveFields {
+		if f.Ref.GroupKind() == secretGk {
+			continue
+		}
+		ret = append(ret, result.DeploymentError{
+			Ref:     f.Ref,
+			Message: fmt.Sprintf("field %s contains the value of sensitive variable %s, but the object is not a Secret", f.JsonPath, f.VarPath),
+		})
+	}
+	return ret
+}
+
+func collectSensitiveValues(config *types.DeploymentProjectConfig) []sensitiveValue {
+	if config == nil {
+		return nil
+	}
+
+	var ret []sensitiveValue
+	add := func(varsList []*types.VarsSource) {
+		for _, vs := range varsList {
+			if !vs.RenderedSensitive || vs.RenderedVars == nil {
+				continue
+			}
+			desc := describeVarsSource(vs)
+			_ = vs.RenderedVars.NewIterator().IterateLeafs(func(it *uo.ObjectIterator) error {
+				if it.Value() == nil {
+					return nil
+				}
+				v := fmt.Sprint(it.Value())
+				if len(v) < minSensitiveValueLen {
+					return nil
+				}
+				ret = append(ret, sensitiveValue{
+					value:      v,
+					varPath:    it.KeyPath().ToJsonPath(),
+					varsSource: desc,
+					variants:   []string{v, base64.StdEncoding.EncodeToString([]byte(v))},
+				})
+				return nil
+			})
+		}
+	}
+
+	add(config.Vars)
+	for _, di := range config.Deployments {
+		add(di.Vars)
+		ret = append(ret, collectSensitiveValues(di.RenderedInclude)...)
+	}
+	return ret
+}
+
+func findSensitiveFieldsInObject(o *uo.UnstructuredObject, values []sensitiveValue) []result.SensitiveField {
+	ref := o.GetK8sRef()
+	isSecret := ref.GroupKind() == secretGk
+
+	var ret []result.SensitiveField
+	_ = o.NewIterator().IterateLeafs(func(it *uo.ObjectIterator) error {
+		s, ok := it.Value().(string)
+		if !ok {
+			return nil
+		}
+		kp := it.KeyPath()
+		if isSecret && len(kp) == 2 && kp[0] == "data" {
+			if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+				s = string(b)
+			}
+		}
+
+		seen := map[string]bool{}
+		for _, v := range values {
+			if seen[v.varPath] || !v.matches(s) {
+				continue
+			}
+			seen[v.varPath] = true
+			ret = append(ret, result.SensitiveField{
+				Ref:        ref,
+				JsonPath:   kp.ToJsonPath(),
+				VarPath:    v.varPath,
+				VarsSource: v.varsSource,
+			})
+		}
+		return nil
+	})
+	return ret
+}
+
+func (v *sensitiveValue) matches(s string) bool {
+	for _, x := range v.variants {
+		if containsToken(s, x) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsToken returns true if s contains v and the occurrence is not part of a larger word, e.g. "secret-value" is
+// found in "password=secret-value" but not in "secret-values".
+func containsToken(s string, v string) bool {
+	if v == "" {
+		return false
+	}
+	firstRune, _ := utf8.DecodeRuneInString(v)
+	lastRune, _ := utf8.DecodeLastRuneInString(v)
+
+	for offset := 0; ; {
+		i := strings.Index(s[offset:], v)
+		if i == -1 {
+			return false
+		}
+		start := offset + i
+		end := start + len(v)
+
+		boundaryBefore := true
+		if start > 0 && isWordRune(firstRune) {
+			r, _ := utf8.DecodeLastRuneInString(s[:start])
+			boundaryBefore = !isWordRune(r)
+		}
+		boundaryAfter := true
+		if end < len(s) && isWordRune(lastRune) {
+			r, _ := utf8.DecodeRuneInString(s[end:])
+			boundaryAfter = !isWordRune(r)
+		}
+		if boundaryBefore && boundaryAfter {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		offset = start + size
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func describeVarsSource(vs *types.VarsSource) string {
+	switch {
+	case vs.Values != nil:
+		return "values"
+	case vs.File != nil:
+		return fmt.Sprintf("file %s", *vs.File)
+	case vs.Git != nil:
+		return fmt.Sprintf("git %s", vs.Git.Path)
+	case vs.ClusterConfigMap != nil:
+		return fmt.Sprintf("clusterConfigMap %s/%s", vs.ClusterConfigMap.Namespace, vs.ClusterConfigMap.Name)
+	case vs.ClusterSecret != nil:
+		return fmt.Sprintf("clusterSecret %s/%s", vs.ClusterSecret.Namespace, vs.ClusterSecret.Name)
+	case vs.SystemEnvVars != nil:
+		return "systemEnvVars"
+	case vs.Http != nil:
+		return "http"
+	case vs.AwsSecretsManager != nil:
+		return fmt.Sprintf("awsSecretsManager %s", vs.AwsSecretsManager.SecretName)
+	case vs.GcpSecretManager != nil:
+		return fmt.Sprintf("gcpSecretManager %s", vs.GcpSecretManager.SecretName)
+	case vs.Vault != nil:
+		return fmt.Sprintf("vault %s", vs.Vault.Path)
+	case vs.AzureKeyVault != nil:
+		return fmt.Sprintf("azureKeyVault %s", vs.AzureKeyVault.SecretName)
+	}
+	return ""
+}
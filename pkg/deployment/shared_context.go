@@ -21,6 +21,9 @@ type SharedContext struct {
 	HelmAuthProvider helm_auth.HelmAuthProvider
 	OciAuthProvider  auth_provider.OciAuthProvider
 
+	HelmRequireVerification    bool
+	HelmAllowExecPostRenderers bool
+
 	Discriminator                     string
 	RenderDir                         string
 	SealedSecretsDir                  string
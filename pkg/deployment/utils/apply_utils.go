@@ -8,11 +8,13 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/diff"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/tracing"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/validation"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/sync/semaphore"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -36,6 +38,27 @@ type ApplyUtilOptions struct {
 	NoWait              bool
 
 	SkipResourceVersions map[k8s2.ObjectRef]string
+	SkipObjects          map[k8s2.ObjectRef]bool
+
+	// Concurrency specifies how many deployment items are applied in parallel. Defaults to 8 if <= 0.
+	Concurrency int
+	// ItemConcurrency specifies how many objects of a single deployment item are applied in parallel.
+	// Defaults to 1 if <= 0, which means that objects are applied in the order they were rendered.
+	ItemConcurrency int
+}
+
+func (o *ApplyUtilOptions) getConcurrency() int {
+	if o.Concurrency <= 0 {
+		return 8
+	}
+	return o.Concurrency
+}
+
+func (o *ApplyUtilOptions) getItemConcurrency() int {
+	if o.ItemConcurrency <= 0 {
+		return 1
+	}
+	return o.ItemConcurrency
 }
 
 type ApplyUtil struct {
@@ -49,6 +72,7 @@ type ApplyUtil struct {
 	appliedHookObjects map[k8s2.ObjectRef]*uo.UnstructuredObject
 	deletedObjects     map[k8s2.ObjectRef]bool
 	deletedHookObjects map[k8s2.ObjectRef]bool
+	skippedObjects     map[k8s2.ObjectRef]*uo.UnstructuredObject
 	mutex              sync.Mutex
 
 	abortSignal   *atomic.Value
@@ -105,6 +129,7 @@ func (ad *ApplyDeploymentsUtil) NewApplyUtil(ctx context.Context, statusCtx *sta
 		appliedHookObjects: map[k8s2.ObjectRef]*uo.UnstructuredObject{},
 		deletedObjects:     map[k8s2.ObjectRef]bool{},
 		deletedHookObjects: map[k8s2.ObjectRef]bool{},
+		skippedObjects:     map[k8s2.ObjectRef]*uo.UnstructuredObject{},
 		abortSignal:        &ad.abortSignal,
 		allNamespaces:      &ad.allNamespaces,
 		allCRDs:            &ad.allCRDs,
@@ -196,6 +221,9 @@ func (a *ApplyUtil) DeleteObject(ref k8s2.ObjectRef, hook bool) bool {
 
 	// now simulate deletion of objects that got applied in the same run
 
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
 	wasApplied := false
 	if hook {
 		if _, ok := a.appliedObjects[ref]; ok {
@@ -213,8 +241,6 @@ func (a *ApplyUtil) DeleteObject(ref k8s2.ObjectRef, hook bool) bool {
 
 	// it got applied, so we need to pretend it actually got deleted
 
-	a.mutex.Lock()
-	defer a.mutex.Unlock()
 	if hook {
 		a.deletedHookObjects[ref] = true
 	} else {
@@ -339,6 +365,18 @@ func (a *ApplyUtil) retryApplyWithConflicts(x *uo.UnstructuredObject, hook bool,
 func (a *ApplyUtil) ApplyObject(x *uo.UnstructuredObject, replaced bool, hook bool) {
 	ref := x.GetK8sRef()
 
+	startTime := time.Now()
+	_, span := tracing.Start(a.ctx, "apply-object", tracing.ObjectRefAttributes(ref)...)
+	defer func() {
+		e := status.ObjectEvent{Type: status.ObjectEventApplied, Ref: ref, Hook: hook, Duration: time.Since(startTime)}
+		if a.HadError(ref) {
+			span.SetStatus(codes.Error, "apply failed")
+			e.Type = status.ObjectEventApplyFailed
+		}
+		status.ReportObjectEvent(a.ctx, e)
+		span.End()
+	}()
+
 	x = a.k.FixObjectForPatch(x)
 	remoteObject := a.ru.GetRemoteObject(ref)
 
@@ -416,6 +454,15 @@ func (a *ApplyUtil) ApplyObject(x *uo.UnstructuredObject, replaced bool, hook bo
 	}
 }
 
+// isApplyOrderDependency returns true for objects that other objects might require to exist when being applied
+func isApplyOrderDependency(o *uo.UnstructuredObject) bool {
+	switch o.GetK8sGVK().GroupKind().String() {
+	case "Namespace", "CustomResourceDefinition.apiextensions.k8s.io":
+		return true
+	}
+	return false
+}
+
 func (a *ApplyUtil) handleObservedCRD(r *uo.UnstructuredObject) {
 	status.Tracef(a.ctx, "observed CRD %s", r.GetK8sName())
 
@@ -436,11 +483,23 @@ func (a *ApplyUtil) handleObservedCRD(r *uo.UnstructuredObject) {
 	}
 }
 
-func (a *ApplyUtil) WaitReadiness(ref k8s2.ObjectRef, timeout time.Duration) bool {
+func (a *ApplyUtil) WaitReadiness(ref k8s2.ObjectRef, timeout time.Duration) (ready bool) {
 	if a.o.DryRun {
 		return true
 	}
 
+	waitStart := time.Now()
+	_, span := tracing.Start(a.ctx, "wait-readiness", tracing.ObjectRefAttributes(ref)...)
+	defer func() {
+		e := status.ObjectEvent{Type: status.ObjectEventReady, Ref: ref, Duration: time.Since(waitStart)}
+		if !ready {
+			span.SetStatus(codes.Error, "object did not get ready")
+			e.Type = status.ObjectEventWaitFailed
+		}
+		status.ReportObjectEvent(a.ctx, e)
+		span.End()
+	}()
+
 	if timeout == 0 {
 		timeout = a.o.ReadinessTimeout
 	}
@@ -565,6 +624,10 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 		if _, ok := toDelete[o.GetK8sRef()]; ok {
 			continue
 		}
+		if a.o.SkipObjects[o.GetK8sRef()] {
+			a.skippedObjects[o.GetK8sRef()] = o
+			continue
+		}
 		applyObjects = append(applyObjects, o)
 	}
 
@@ -598,23 +661,63 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 	if len(applyObjects) != 0 {
 		a.sctx.InfoFallbackf("Applying %d objects", len(applyObjects))
 	}
+	var progressMutex sync.Mutex
 	startTime := time.Now()
 	didLog := false
-	for i, o := range applyObjects {
-		if a.abortSignal.Load().(bool) {
-			break
-		}
-
+	appliedCount := 0
+	applyObject := func(i int, o *uo.UnstructuredObject) {
 		ref := o.GetK8sRef()
 		a.sctx.Updatef("Applying object %s (%d of %d)", ref.String(), i+1, len(applyObjects))
 		a.ApplyObject(o, false, false)
+
+		progressMutex.Lock()
+		defer progressMutex.Unlock()
 		a.sctx.Increment()
-		if time.Now().Sub(startTime) >= 10*time.Second || (didLog && i == len(applyObjects)-1) {
-			a.sctx.InfoFallbackf("...applied %d of %d objects", i+1, len(applyObjects))
+		appliedCount++
+		if time.Now().Sub(startTime) >= 10*time.Second || (didLog && appliedCount == len(applyObjects)) {
+			a.sctx.InfoFallbackf("...applied %d of %d objects", appliedCount, len(applyObjects))
 			startTime = time.Now()
 			didLog = true
 		}
 	}
+
+	itemConcurrency := a.o.getItemConcurrency()
+	if itemConcurrency > 1 {
+		// other objects of the same item might depend on these, so they must be applied before anything is applied
+		// in parallel
+		for i, o := range applyObjects {
+			if a.abortSignal.Load().(bool) {
+				break
+			}
+			if isApplyOrderDependency(o) {
+				applyObject(i, o)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := semaphore.NewWeighted(int64(itemConcurrency))
+	for i_, o_ := range applyObjects {
+		i := i_
+		o := o_
+		if a.abortSignal.Load().(bool) {
+			break
+		}
+		if itemConcurrency > 1 && isApplyOrderDependency(o) {
+			continue
+		}
+
+		// with an item concurrency of 1, this ensures that objects are applied in order
+		_ = sem.Acquire(context.Background(), 1)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer sem.Release(1)
+			applyObject(i, o)
+		}()
+	}
+	wg.Wait()
 	// Wait for readiness if needed after we have applied all objects
 	for _, o := range applyObjects {
 		if a.abortSignal.Load().(bool) {
@@ -645,6 +748,9 @@ func (a *ApplyUtil) applyDeploymentItem(d *deployment.DeploymentItem) {
 	if len(a.deletedHookObjects) != 0 {
 		finalStatus += fmt.Sprintf(" Deleted %d hooks.", len(a.deletedHookObjects))
 	}
+	if len(a.skippedObjects) != 0 {
+		finalStatus += fmt.Sprintf(" Skipped %d objects.", len(a.skippedObjects))
+	}
 	if a.errorCount != 0 {
 		finalStatus += fmt.Sprintf(" Encountered %d errors.", a.errorCount)
 	}
@@ -679,8 +785,11 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments(deployments []*deployment.Deploy
 	s := status.Start(a.ctx, "Running server-side apply for all objects")
 	defer s.Failed()
 
+	ctx, span := tracing.Start(a.ctx, "apply")
+	defer span.End()
+
 	var wg sync.WaitGroup
-	sem := semaphore.NewWeighted(8)
+	sem := semaphore.NewWeighted(int64(a.o.getConcurrency()))
 
 	maxNameLen := 0
 	for _, d := range deployments {
@@ -709,12 +818,14 @@ func (a *ApplyDeploymentsUtil) ApplyDeployments(deployments []*deployment.Deploy
 				status.WithStatus("Initializing"),
 			)
 		}
-		a2 := a.NewApplyUtil(a.ctx, sctx)
+		itemCtx, itemSpan := tracing.Start(ctx, "apply-deployment-item", d.TraceAttributes()...)
+		a2 := a.NewApplyUtil(itemCtx, sctx)
 
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			defer sem.Release(1)
+			defer itemSpan.End()
 
 			a2.applyDeploymentItem(d)
 
@@ -844,6 +955,12 @@ func (ad *ApplyDeploymentsUtil) GetAppliedHookObjects() []*uo.UnstructuredObject
 	})
 }
 
+func (ad *ApplyDeploymentsUtil) GetSkippedObjects() []*uo.UnstructuredObject {
+	return ad.collectObjects(func(au *ApplyUtil) map[k8s2.ObjectRef]*uo.UnstructuredObject {
+		return au.skippedObjects
+	})
+}
+
 func (ad *ApplyDeploymentsUtil) GetDeletedObjects() []k8s2.ObjectRef {
 	ad.resultsMutex.Lock()
 	defer ad.resultsMutex.Unlock()
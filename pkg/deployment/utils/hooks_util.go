@@ -3,9 +3,11 @@ package utils
 import (
 	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/deployment"
+	"github.com/kluctl/kluctl/v2/pkg/tracing"
 	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"go.opentelemetry.io/otel/attribute"
 	"sort"
 	"strconv"
 	"strings"
@@ -63,6 +65,9 @@ func (u *HooksUtil) DetermineHooks(d *deployment.DeploymentItem, hooks []string)
 }
 
 func (u *HooksUtil) RunHooks(hooks []*hook) {
+	_, span := tracing.Start(u.a.ctx, "hooks", attribute.Int("kluctl.hooks_count", len(hooks)))
+	defer span.End()
+
 	var deleteBeforeObjects []*hook
 	var applyObjects []*hook
 
@@ -19,7 +19,17 @@ type Obfuscator struct {
 }
 
 func (o *Obfuscator) ObfuscateResult(r *result.CommandResult) error {
-	for _, x := range r.Objects {
+	sensitivePaths := map[k8s.ObjectRef][]string{}
+	for _, f := range r.SensitiveFields {
+		if f.Ref.GroupKind() == secretGk {
+			// already fully obfuscated
+			continue
+		}
+		sensitivePaths[f.Ref] = append(sensitivePaths[f.Ref], f.JsonPath)
+	}
+
+	for i := range r.Objects {
+		x := &r.Objects[i]
 		var err error
 		x.Rendered, err = o.ObfuscateObject(x.Rendered)
 		if err != nil {
@@ -37,6 +47,27 @@ func (o *Obfuscator) ObfuscateResult(r *result.CommandResult) error {
 		if err != nil {
 			return err
 		}
+
+		paths := sensitivePaths[x.Ref]
+		if len(paths) == 0 {
+			continue
+		}
+		x.Rendered, err = o.obfuscateSensitiveFields(x.Rendered, paths)
+		if err != nil {
+			return err
+		}
+		x.Remote, err = o.obfuscateSensitiveFields(x.Remote, paths)
+		if err != nil {
+			return err
+		}
+		x.Applied, err = o.obfuscateSensitiveFields(x.Applied, paths)
+		if err != nil {
+			return err
+		}
+		err = o.obfuscateSensitiveChanges(x.Changes, paths)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -152,3 +183,93 @@ func (o *Obfuscator) obfuscateSecret(x *uo.UnstructuredObject) (*uo.Unstructured
 	}
 	return x, nil
 }
+
+// obfuscateSensitiveFields replaces the values at the given paths, which were reported as containing values from
+// sensitive vars sources
+func (o *Obfuscator) obfuscateSensitiveFields(x *uo.UnstructuredObject, paths []string) (*uo.UnstructuredObject, error) {
+	if x == nil {
+		return nil, nil
+	}
+	x = x.Clone()
+	for _, p := range paths {
+		j, err := uo.NewMyJsonPath(p)
+		if err != nil {
+			return x, err
+		}
+		if _, ok := j.GetFirst(x); !ok {
+			continue
+		}
+		err = j.Set(x, "*****")
+		if err != nil {
+			return x, err
+		}
+	}
+	return x, nil
+}
+
+func (o *Obfuscator) obfuscateSensitiveChanges(changes []result.Change, paths []string) error {
+	replaceValue := func(j *apiextensionsv1.JSON, rel jp.Expr, v string) (*apiextensionsv1.JSON, error) {
+		if j == nil {
+			return nil, nil
+		}
+		if len(rel) == 1 {
+			// the change itself points to the sensitive field
+			return &apiextensionsv1.JSON{Raw: []byte(fmt.Sprintf("%q", v))}, nil
+		}
+
+		var x any
+		err := json.Unmarshal(j.Raw, &x)
+		if err != nil {
+			return nil, err
+		}
+		if len(rel.Get(x)) == 0 {
+			return j, nil
+		}
+		err = rel.Set(x, v)
+		if err != nil {
+			return nil, err
+		}
+		b, err := json.Marshal(x)
+		if err != nil {
+			return nil, err
+		}
+		return &apiextensionsv1.JSON{Raw: b}, nil
+	}
+
+	for i := range changes {
+		c := &changes[i]
+		changed := false
+		for _, p := range paths {
+			if p != c.JsonPath && !strings.HasPrefix(p, c.JsonPath+".") && !strings.HasPrefix(p, c.JsonPath+"[") {
+				continue
+			}
+			// path of the sensitive field relative to the changed value
+			rel, err := jp.ParseString("$" + strings.TrimPrefix(p, c.JsonPath))
+			if err != nil {
+				return err
+			}
+			c.NewValue, err = replaceValue(c.NewValue, rel, "*****a")
+			if err != nil {
+				return err
+			}
+			c.OldValue, err = replaceValue(c.OldValue, rel, "*****b")
+			if err != nil {
+				return err
+			}
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+
+		_ = updateUnifiedDiff(c)
+		c.UnifiedDiff = strings.ReplaceAll(c.UnifiedDiff, "*****a", "***** (obfuscated)")
+		c.UnifiedDiff = strings.ReplaceAll(c.UnifiedDiff, "*****b", "***** (obfuscated)")
+		for _, v := range []*apiextensionsv1.JSON{c.NewValue, c.OldValue} {
+			if v != nil {
+				v.Raw = []byte(strings.NewReplacer(`"*****a"`, `"*****"`, `"*****b"`, `"*****"`).Replace(string(v.Raw)))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package diff
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildConfigMap(data map[string]any) *uo.UnstructuredObject {
+	return uo.FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      "cm",
+			"namespace": "ns",
+		},
+		"data": data,
+	})
+}
+
+func TestObfuscateSensitiveFields(t *testing.T) {
+	oldObj := buildConfigMap(map[string]any{
+		"password": "old-password",
+		"other":    "old-other",
+	})
+	newObj := buildConfigMap(map[string]any{
+		"password": "new-password",
+		"other":    "new-other",
+		"nested":   "{\"x\": \"new-password\"}",
+	})
+	ref := newObj.GetK8sRef()
+
+	changes, err := Diff(oldObj, newObj)
+	assert.NoError(t, err)
+
+	r := &result.CommandResult{
+		Objects: []result.ResultObject{
+			{
+				BaseObject: result.BaseObject{Ref: ref, Changes: changes},
+				Rendered:   newObj,
+				Remote:     oldObj,
+			},
+		},
+		SensitiveFields: []result.SensitiveField{
+			{Ref: ref, JsonPath: "data.password", VarPath: "secrets.password"},
+			{Ref: ref, JsonPath: "data.nested", VarPath: "secrets.password"},
+		},
+	}
+
+	var o Obfuscator
+	err = o.ObfuscateResult(r)
+	assert.NoError(t, err)
+
+	x := r.Objects[0]
+	assert.Equal(t, map[string]any{
+		"password": "*****",
+		"other":    "new-other",
+		"nested":   "*****",
+	}, x.Rendered.Object["data"])
+	assert.Equal(t, map[string]any{
+		"password": "*****",
+		"other":    "old-other",
+	}, x.Remote.Object["data"])
+
+	// the original objects must not be modified
+	assert.Equal(t, "new-password", newObj.Object["data"].(map[string]any)["password"])
+
+	for _, c := range x.Changes {
+		switch c.JsonPath {
+		case "data.password":
+			assert.Equal(t, `"*****"`, string(c.NewValue.Raw))
+			assert.Equal(t, `"*****"`, string(c.OldValue.Raw))
+			assert.Contains(t, c.UnifiedDiff, "***** (obfuscated)")
+			assert.NotContains(t, c.UnifiedDiff, "password")
+		case "data.nested":
+			assert.Equal(t, `"*****"`, string(c.NewValue.Raw))
+			assert.NotContains(t, c.UnifiedDiff, "new-password")
+		case "data.other":
+			assert.Equal(t, `"new-other"`, string(c.NewValue.Raw))
+		default:
+			t.Errorf("unexpected change %s", c.JsonPath)
+		}
+	}
+}
+
+func TestObfuscateSensitiveFieldsParentChange(t *testing.T) {
+	newObj := buildConfigMap(map[string]any{
+		"password": "new-password",
+		"other":    "new-other",
+	})
+	ref := newObj.GetK8sRef()
+
+	oldObj := buildConfigMap(nil)
+	delete(oldObj.Object, "data")
+
+	changes, err := Diff(oldObj, newObj)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "data", changes[0].JsonPath)
+
+	r := &result.CommandResult{
+		Objects: []result.ResultObject{
+			{BaseObject: result.BaseObject{Ref: ref, Changes: changes}},
+		},
+		SensitiveFields: []result.SensitiveField{
+			{Ref: ref, JsonPath: "data.password", VarPath: "secrets.password"},
+		},
+	}
+
+	var o Obfuscator
+	err = o.ObfuscateResult(r)
+	assert.NoError(t, err)
+
+	c := r.Objects[0].Changes[0]
+	assert.JSONEq(t, `{"password": "*****", "other": "new-other"}`, string(c.NewValue.Raw))
+	assert.Contains(t, c.UnifiedDiff, "***** (obfuscated)")
+	assert.Contains(t, c.UnifiedDiff, "new-other")
+	assert.NotContains(t, c.UnifiedDiff, "new-password")
+}
@@ -96,6 +96,12 @@ func (g *MirroredGitRepo) SetUpdated(u bool) {
 	g.hasUpdated = u
 }
 
+// HasMirror returns true if the mirror was already cloned before, so that Update only needs to fetch new objects.
+func (g *MirroredGitRepo) HasMirror() bool {
+	st, err := os.Stat(filepath.Join(g.mirrorDir, ".cache2.init"))
+	return err == nil && st.Mode().IsRegular()
+}
+
 func (g *MirroredGitRepo) Lock() error {
 	g.mutex.Lock()
 	defer g.mutex.Unlock()
@@ -0,0 +1,52 @@
+package pullrequest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type gitHubProvider struct {
+	c    *apiClient
+	repo string
+}
+
+type gitHubPullRequest struct {
+	Number  int    `json:"number"`
+	HtmlUrl string `json:"html_url"`
+}
+
+func (p *gitHubProvider) CreateOrUpdate(ctx context.Context, pr PullRequest) (string, error) {
+	owner, _, _ := strings.Cut(p.repo, "/")
+
+	q := url.Values{}
+	q.Set("state", "open")
+	q.Set("head", owner+":"+pr.SourceBranch)
+
+	var existing []gitHubPullRequest
+	err := p.c.do(ctx, "GET", fmt.Sprintf("/repos/%s/pulls?%s", p.repo, q.Encode()), nil, &existing)
+	if err != nil {
+		return "", err
+	}
+
+	var ret gitHubPullRequest
+	if len(existing) != 0 {
+		err = p.c.do(ctx, "PATCH", fmt.Sprintf("/repos/%s/pulls/%d", p.repo, existing[0].Number), map[string]any{
+			"title": pr.Title,
+			"body":  pr.Body,
+			"base":  pr.TargetBranch,
+		}, &ret)
+	} else {
+		err = p.c.do(ctx, "POST", fmt.Sprintf("/repos/%s/pulls", p.repo), map[string]any{
+			"title": pr.Title,
+			"body":  pr.Body,
+			"head":  pr.SourceBranch,
+			"base":  pr.TargetBranch,
+		}, &ret)
+	}
+	if err != nil {
+		return "", err
+	}
+	return ret.HtmlUrl, nil
+}
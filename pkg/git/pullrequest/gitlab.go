@@ -0,0 +1,51 @@
+package pullrequest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+type gitLabProvider struct {
+	c    *apiClient
+	repo string
+}
+
+type gitLabMergeRequest struct {
+	Iid    int    `json:"iid"`
+	WebUrl string `json:"web_url"`
+}
+
+func (p *gitLabProvider) CreateOrUpdate(ctx context.Context, pr PullRequest) (string, error) {
+	project := url.PathEscape(p.repo)
+
+	q := url.Values{}
+	q.Set("state", "opened")
+	q.Set("source_branch", pr.SourceBranch)
+
+	var existing []gitLabMergeRequest
+	err := p.c.do(ctx, "GET", fmt.Sprintf("/projects/%s/merge_requests?%s", project, q.Encode()), nil, &existing)
+	if err != nil {
+		return "", err
+	}
+
+	var ret gitLabMergeRequest
+	if len(existing) != 0 {
+		err = p.c.do(ctx, "PUT", fmt.Sprintf("/projects/%s/merge_requests/%d", project, existing[0].Iid), map[string]any{
+			"title":         pr.Title,
+			"description":   pr.Body,
+			"target_branch": pr.TargetBranch,
+		}, &ret)
+	} else {
+		err = p.c.do(ctx, "POST", fmt.Sprintf("/projects/%s/merge_requests", project), map[string]any{
+			"title":         pr.Title,
+			"description":   pr.Body,
+			"source_branch": pr.SourceBranch,
+			"target_branch": pr.TargetBranch,
+		}, &ret)
+	}
+	if err != nil {
+		return "", err
+	}
+	return ret.WebUrl, nil
+}
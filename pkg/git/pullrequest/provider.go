@@ -0,0 +1,118 @@
+package pullrequest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+var defaultApiUrls = map[string]string{
+	ProviderGitHub: "https://api.github.com",
+	ProviderGitLab: "https://gitlab.com/api/v4",
+}
+
+type PullRequest struct {
+	Title        string
+	Body         string
+	SourceBranch string
+	TargetBranch string
+}
+
+type Provider interface {
+	// CreateOrUpdate creates a new pull request or updates the title and body of an already open pull request with
+	// the same source branch. It returns the web url of the pull request.
+	CreateOrUpdate(ctx context.Context, pr PullRequest) (string, error)
+}
+
+// NewProvider creates a Provider for the given provider type. If apiUrl is empty, the public API of the provider
+// is used. repo is the "owner/name" of a GitHub repository or the full path of a GitLab project.
+func NewProvider(providerType string, apiUrl string, repo string, token string) (Provider, error) {
+	if apiUrl == "" {
+		apiUrl = defaultApiUrls[providerType]
+	}
+	if repo == "" {
+		return nil, fmt.Errorf("missing repository for pull request provider")
+	}
+
+	c := &apiClient{
+		apiUrl: strings.TrimSuffix(apiUrl, "/"),
+		client: http.DefaultClient,
+	}
+
+	switch providerType {
+	case ProviderGitHub:
+		c.headers = map[string]string{
+			"Accept": "application/vnd.github+json",
+		}
+		if token != "" {
+			c.headers["Authorization"] = "Bearer " + token
+		}
+		return &gitHubProvider{c: c, repo: repo}, nil
+	case ProviderGitLab:
+		c.headers = map[string]string{}
+		if token != "" {
+			c.headers["PRIVATE-TOKEN"] = token
+		}
+		return &gitLabProvider{c: c, repo: repo}, nil
+	default:
+		return nil, fmt.Errorf("unsupported pull request provider %s", providerType)
+	}
+}
+
+type apiClient struct {
+	apiUrl  string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (c *apiClient) do(ctx context.Context, method string, path string, body any, result any) error {
+	var bodyReader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.apiUrl+path, bodyReader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s failed with status %d: %s", method, req.URL.Path, resp.StatusCode, string(respBody))
+	}
+	if result != nil {
+		err = json.Unmarshal(respBody, result)
+		if err != nil {
+			return fmt.Errorf("failed to decode response of %s %s: %w", method, req.URL.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package pullrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakePr struct {
+	Number int
+	Source string
+	Target string
+	Title  string
+	Body   string
+}
+
+type fakeServer struct {
+	t        *testing.T
+	mutex    sync.Mutex
+	prs      []*fakePr
+	gotToken string
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	isGitLab := strings.HasPrefix(r.URL.EscapedPath(), "/projects/my-group%2Fmy-repo/")
+	if isGitLab {
+		s.gotToken = r.Header.Get("PRIVATE-TOKEN")
+	} else {
+		s.gotToken = r.Header.Get("Authorization")
+	}
+
+	var body map[string]string
+	if r.Method != "GET" {
+		assert.NoError(s.t, json.NewDecoder(r.Body).Decode(&body))
+	}
+
+	writePr := func(pr *fakePr) {
+		if isGitLab {
+			_ = json.NewEncoder(w).Encode(map[string]any{"iid": pr.Number, "web_url": fmt.Sprintf("http://gitlab/mr/%d", pr.Number)})
+		} else {
+			_ = json.NewEncoder(w).Encode(map[string]any{"number": pr.Number, "html_url": fmt.Sprintf("http://github/pr/%d", pr.Number)})
+		}
+	}
+
+	switch {
+	case r.Method == "GET":
+		var source string
+		if isGitLab {
+			source = r.URL.Query().Get("source_branch")
+		} else {
+			source = strings.TrimPrefix(r.URL.Query().Get("head"), "my-group:")
+		}
+		var ret []map[string]any
+		for _, pr := range s.prs {
+			if pr.Source == source {
+				ret = append(ret, map[string]any{"number": pr.Number, "iid": pr.Number})
+			}
+		}
+		_ = json.NewEncoder(w).Encode(ret)
+	case r.Method == "POST":
+		pr := &fakePr{Number: len(s.prs) + 1}
+		if isGitLab {
+			pr.Source, pr.Target, pr.Title, pr.Body = body["source_branch"], body["target_branch"], body["title"], body["description"]
+		} else {
+			pr.Source, pr.Target, pr.Title, pr.Body = body["head"], body["base"], body["title"], body["body"]
+		}
+		s.prs = append(s.prs, pr)
+		w.WriteHeader(http.StatusCreated)
+		writePr(pr)
+	case r.Method == "PATCH" || r.Method == "PUT":
+		pr := s.prs[0]
+		if isGitLab {
+			pr.Title, pr.Body = body["title"], body["description"]
+		} else {
+			pr.Title, pr.Body = body["title"], body["body"]
+		}
+		writePr(pr)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func testProvider(t *testing.T, providerType string, expectedToken string, expectedUrl string) {
+	fs := &fakeServer{t: t}
+	s := httptest.NewServer(fs)
+	t.Cleanup(s.Close)
+
+	p, err := NewProvider(providerType, s.URL, "my-group/my-repo", "my-token")
+	assert.NoError(t, err)
+
+	u, err := p.CreateOrUpdate(context.Background(), PullRequest{
+		Title:        "title",
+		Body:         "body",
+		SourceBranch: "my-branch",
+		TargetBranch: "main",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUrl, u)
+	assert.Equal(t, expectedToken, fs.gotToken)
+	assert.Len(t, fs.prs, 1)
+	assert.Equal(t, fakePr{Number: 1, Source: "my-branch", Target: "main", Title: "title", Body: "body"}, *fs.prs[0])
+
+	u, err = p.CreateOrUpdate(context.Background(), PullRequest{
+		Title:        "title2",
+		Body:         "body2",
+		SourceBranch: "my-branch",
+		TargetBranch: "main",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedUrl, u)
+	assert.Len(t, fs.prs, 1)
+	assert.Equal(t, "title2", fs.prs[0].Title)
+	assert.Equal(t, "body2", fs.prs[0].Body)
+}
+
+func TestGitHubProvider(t *testing.T) {
+	testProvider(t, ProviderGitHub, "Bearer my-token", "http://github/pr/1")
+}
+
+func TestGitLabProvider(t *testing.T) {
+	testProvider(t, ProviderGitLab, "my-token", "http://gitlab/mr/1")
+}
+
+func TestProviderErrors(t *testing.T) {
+	_, err := NewProvider("invalid", "", "my-group/my-repo", "")
+	assert.ErrorContains(t, err, "unsupported pull request provider invalid")
+
+	_, err = NewProvider(ProviderGitHub, "", "", "")
+	assert.ErrorContains(t, err, "missing repository")
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte("bad credentials"))
+	}))
+	t.Cleanup(s.Close)
+
+	p, err := NewProvider(ProviderGitHub, s.URL, "my-group/my-repo", "")
+	assert.NoError(t, err)
+	_, err = p.CreateOrUpdate(context.Background(), PullRequest{SourceBranch: "b", TargetBranch: "main"})
+	assert.ErrorContains(t, err, "failed with status 401: bad credentials")
+}
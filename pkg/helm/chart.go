@@ -8,16 +8,20 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/helm/auth"
 	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/tracing"
+	"github.com/kluctl/kluctl/v2/pkg/types"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
 	cp "github.com/otiai10/copy"
 	"github.com/rogpeppe/go-internal/lockedfile"
+	"go.opentelemetry.io/otel/attribute"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/repo"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -36,16 +40,20 @@ type Chart struct {
 
 	credentialsId string
 
+	// verify contains the verification config with absolute paths
+	verify *types.HelmVerifyConfig
+
 	versions []string
 }
 
-func NewChart(repo string, localPath string, chartName string, helmAuthProvider auth.HelmAuthProvider, credentialsId string, ociAuthProvider auth_provider.OciAuthProvider) (*Chart, error) {
+func NewChart(repo string, localPath string, chartName string, helmAuthProvider auth.HelmAuthProvider, credentialsId string, ociAuthProvider auth_provider.OciAuthProvider, verify *types.HelmVerifyConfig) (*Chart, error) {
 	hc := &Chart{
 		repo:             repo,
 		localPath:        localPath,
 		helmAuthProvider: helmAuthProvider,
 		credentialsId:    credentialsId,
 		ociAuthProvider:  ociAuthProvider,
+		verify:           verify,
 	}
 
 	if localPath == "" && repo == "" {
@@ -163,7 +171,11 @@ func (c *Chart) GetChartName() string {
 	return c.chartName
 }
 
-func (c *Chart) newRegistryClient(ctx context.Context, settings *cli.EnvSettings) (*registry.Client, func(), error) {
+func (c *Chart) GetVerify() *types.HelmVerifyConfig {
+	return c.verify
+}
+
+func (c *Chart) newRegistryClient(ctx context.Context, settings *cli.EnvSettings, out io.Writer) (*registry.Client, func(), error) {
 	cleanup := func() {}
 
 	u, err := url.Parse(c.repo)
@@ -174,6 +186,7 @@ func (c *Chart) newRegistryClient(ctx context.Context, settings *cli.EnvSettings
 	opts := []registry.ClientOption{
 		registry.ClientOptDebug(settings.Debug),
 		registry.ClientOptEnableCache(true),
+		registry.ClientOptWriter(out),
 	}
 
 	if registry.IsOCI(c.repo) {
@@ -218,8 +231,10 @@ func (c *Chart) PullToTmp(ctx context.Context, version string) (*PulledChart, er
 	}
 	defer os.RemoveAll(tmpPullDir)
 
+	// the registry client reports the digest of pulled OCI charts to registryOut
+	var registryOut strings.Builder
 	settings := cli.New()
-	registryClient, cleanup, err := c.newRegistryClient(ctx, settings)
+	registryClient, cleanup, err := c.newRegistryClient(ctx, settings, &registryOut)
 	if err != nil {
 		return nil, err
 	}
@@ -236,6 +251,20 @@ func (c *Chart) PullToTmp(ctx context.Context, version string) (*PulledChart, er
 	a.DestDir = tmpPullDir
 	a.Version = version
 
+	var verification *chartVerification
+	switch c.getVerificationMethod() {
+	case verificationMethodProvenance:
+		a.Verify = true
+		a.Keyring = c.verify.Keyring
+		verification = &chartVerification{Method: verificationMethodProvenance}
+	case verificationMethodCosign:
+		digest, err := c.verifyCosign(ctx, version)
+		if err != nil {
+			return nil, err
+		}
+		verification = &chartVerification{Method: verificationMethodCosign, Digest: digest}
+	}
+
 	if c.credentialsId != "" {
 		if registry.IsOCI(c.repo) {
 			return nil, fmt.Errorf("OCI charts can currently only be authenticated via registry login and environment variables but not via cli arguments")
@@ -273,6 +302,18 @@ func (c *Chart) PullToTmp(ctx context.Context, version string) (*PulledChart, er
 	if err != nil {
 		return nil, err
 	}
+	if verification != nil {
+		switch verification.Method {
+		case verificationMethodProvenance:
+			verification.Digest = parseProvenanceHash(out)
+		case verificationMethodCosign:
+			// the tag might have been moved between verification and pull, so ensure we got what was verified
+			pulledDigest := parsePulledOciDigest(registryOut.String())
+			if pulledDigest != verification.Digest {
+				return nil, fmt.Errorf("digest of pulled Helm Chart %s:%s (%s) does not match the verified digest %s", c.repo, version, pulledDigest, verification.Digest)
+			}
+		}
+	}
 
 	chartDir, err := os.MkdirTemp(utils.GetTmpBaseDir(ctx), c.chartName+"-pulled-")
 	if err != nil {
@@ -291,14 +332,32 @@ func (c *Chart) PullToTmp(ctx context.Context, version string) (*PulledChart, er
 		}
 	}
 
+	if verification != nil {
+		verification.ContentDigest, err = hashChartDir(chartDir)
+		if err != nil {
+			return nil, err
+		}
+		err = writeVerificationMarker(chartDir, *verification)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return NewPulledChart(c, version, chartDir, true), nil
 }
 
-func (c *Chart) Pull(ctx context.Context, pc *PulledChart) error {
+func (c *Chart) Pull(ctx context.Context, pc *PulledChart) (err error) {
 	if c.IsLocalChart() {
 		return fmt.Errorf("can not pull local charts")
 	}
 
+	ctx, span := tracing.Start(ctx, "helm-pull",
+		attribute.String("helm.chart", c.GetChartName()),
+		attribute.String("helm.chart_version", pc.version))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
 	newPulled, err := c.PullToTmp(ctx, pc.version)
 	if err != nil {
 		return err
@@ -336,7 +395,7 @@ func (c *Chart) doPullCached(ctx context.Context, version string) (*PulledChart,
 		_ = lock.Close()
 		return nil, nil, err
 	}
-	if !needsPull {
+	if !needsPull && cached.CheckVerification() == nil {
 		return cached, lock, nil
 	}
 
@@ -415,18 +474,9 @@ func (c *Chart) QueryVersions(ctx context.Context) error {
 }
 
 func (c *Chart) queryVersionsOci(ctx context.Context) error {
-	var clientOpts []crane.Option
-	clientOpts = append(clientOpts, crane.WithContext(ctx))
-	if c.ociAuthProvider != nil {
-		auth, err := c.ociAuthProvider.FindAuthEntry(ctx, c.repo)
-		if err != nil {
-			return err
-		}
-		authOpts, err := auth.BuildCraneOptions()
-		if err != nil {
-			return nil
-		}
-		clientOpts = append(clientOpts, authOpts...)
+	clientOpts, err := c.buildCraneOptions(ctx)
+	if err != nil {
+		return err
 	}
 
 	imageName := strings.TrimPrefix(c.repo, "oci://")
@@ -16,6 +16,7 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
@@ -37,10 +38,22 @@ type Release struct {
 	Config     *types.HelmChartConfig
 	Chart      *Chart
 
-	baseChartsDir string
+	// Lookups contains all objects looked up by the chart's templates in the last call to RenderChart
+	Lookups []result.HelmLookup
+	// TestHooks contains the test hooks (helm.sh/hook: test) rendered in the last call to RenderChart. These are not
+	// part of the rendered objects and are only used by helm-test
+	TestHooks []*uo.UnstructuredObject
+
+	// AllowExecPostRenderer must be set to allow running the exec post-renderer configured in helm-chart.yaml. Exec
+	// post-renderers can run arbitrary commands, so they are disabled by default.
+	AllowExecPostRenderer bool
+
+	baseChartsDir       string
+	postRendererCommand string
+	lookupFixture       string
 }
 
-func NewRelease(ctx context.Context, projectRoot string, relDirInProject string, configFile string, baseChartsDir string, helmAuthProvider auth.HelmAuthProvider, ociAuthProvider auth_provider.OciAuthProvider) (*Release, error) {
+func NewRelease(ctx context.Context, projectRoot string, relDirInProject string, configFile string, baseChartsDir string, helmAuthProvider auth.HelmAuthProvider, ociAuthProvider auth_provider.OciAuthProvider, requireVerification bool) (*Release, error) {
 	var config types.HelmChartConfig
 	err := yaml.ReadYamlFile(configFile, &config)
 	if err != nil {
@@ -77,10 +90,31 @@ func NewRelease(ctx context.Context, projectRoot string, relDirInProject string,
 	if credentialsId != "" {
 		status.Deprecation(ctx, "helm-release-credentials-id", "'credentialsId' in helm-chart.yaml is deprecated and support for it will be removed in a future version of Kluctl.")
 	}
-	chart, err := NewChart(config.Repo, localPath, config.ChartName, helmAuthProvider, credentialsId, ociAuthProvider)
+
+	var verify *types.HelmVerifyConfig
+	if config.Verify != nil {
+		verify = &types.HelmVerifyConfig{}
+		if config.Verify.Keyring != "" {
+			verify.Keyring, err = resolvePathInProject(projectRoot, filepath.Join(projectRoot, relDirInProject), config.Verify.Keyring)
+			if err != nil {
+				return nil, fmt.Errorf("invalid keyring: %w", err)
+			}
+		}
+		if config.Verify.CosignKey != "" {
+			verify.CosignKey, err = resolvePathInProject(projectRoot, filepath.Join(projectRoot, relDirInProject), config.Verify.CosignKey)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cosignKey: %w", err)
+			}
+		}
+	}
+
+	chart, err := NewChart(config.Repo, localPath, config.ChartName, helmAuthProvider, credentialsId, ociAuthProvider, verify)
 	if err != nil {
 		return nil, err
 	}
+	if requireVerification && verify == nil && !chart.IsLocalChart() {
+		return nil, fmt.Errorf("Helm Chart %s has no verification configured, which is required", chart.GetChartName())
+	}
 
 	hr := &Release{
 		ConfigFile:    configFile,
@@ -89,9 +123,39 @@ func NewRelease(ctx context.Context, projectRoot string, relDirInProject string,
 		Chart:         chart,
 	}
 
+	if config.LookupFixture != "" {
+		hr.lookupFixture, err = resolvePathInProject(projectRoot, filepath.Join(projectRoot, relDirInProject), config.LookupFixture)
+		if err != nil {
+			return nil, fmt.Errorf("invalid lookupFixture: %w", err)
+		}
+	}
+
+	if config.PostRenderer != nil && config.PostRenderer.Exec != nil {
+		hr.postRendererCommand, err = resolvePostRendererCommand(projectRoot, filepath.Join(projectRoot, relDirInProject), config.PostRenderer.Exec.Command)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return hr, nil
 }
 
+// resolvePathInProject resolves the relative path p against dir and ensures that the result is inside the project
+func resolvePathInProject(projectRoot string, dir string, p string) (string, error) {
+	if filepath.IsAbs(p) {
+		return "", fmt.Errorf("absolute path is not allowed in helm-chart.yaml")
+	}
+	ret, err := filepath.Abs(filepath.Join(dir, p))
+	if err != nil {
+		return "", err
+	}
+	err = utils.CheckInDir(projectRoot, ret)
+	if err != nil {
+		return "", err
+	}
+	return ret, nil
+}
+
 func (hr *Release) GetOutputPath() string {
 	output := "helm-rendered.yaml"
 	if hr.Config.Output != nil {
@@ -152,6 +216,11 @@ func (hr *Release) getPulledChart(ctx context.Context) (*PulledChart, error) {
 		s.Success()
 	}
 
+	err = pc.CheckVerification()
+	if err != nil {
+		return nil, fmt.Errorf("%w. Run 'kluctl helm-pull' to pull and verify it", err)
+	}
+
 	return pc, nil
 }
 
@@ -165,36 +234,52 @@ func (hr *Release) doRender(ctx context.Context, k *k8s.K8sCluster, k8sVersion s
 	if err != nil {
 		return err
 	}
-	valuesPath := yaml.FixPathExt(filepath.Join(filepath.Dir(hr.ConfigFile), "helm-values.yml"))
 
-	cfg, err := buildHelmConfig(k, nil)
+	parsed, err := hr.RenderChart(ctx, pc, k, k8sVersion, sopsDecrypter)
 	if err != nil {
 		return err
 	}
 
-	settings := cli.New()
-	valueOpts := values.Options{}
-
-	if utils.Exists(valuesPath) {
-		tmpValues, err := sops.MaybeDecryptFileToTmp(ctx, sopsDecrypter, valuesPath)
-		if err != nil {
-			return err
+	parsedI := make([]any, 0, len(parsed))
+	for _, o := range parsed {
+		if hr.Config.Namespace != nil {
+			// mark it for later namespace fixing
+			o.SetK8sAnnotation(InstallNamespaceAnnotation, *hr.Config.Namespace)
 		}
-		defer os.Remove(tmpValues)
-		valueOpts.ValueFiles = append(valueOpts.ValueFiles, tmpValues)
+		parsedI = append(parsedI, o)
+	}
+
+	rendered, err := yaml.WriteYamlAllBytes(parsedI)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(outputPath, rendered, 0o600)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RenderChart renders the given pulled chart with the values of the release and returns the rendered objects,
+// including hooks. Post-renderers and patches are applied as well.
+func (hr *Release) RenderChart(ctx context.Context, pc *PulledChart, k *k8s.K8sCluster, k8sVersion string, sopsDecrypter *decryptor.Decryptor) ([]*uo.UnstructuredObject, error) {
+	cfg, err := buildHelmConfig(k, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	var kubeVersion *chartutil.KubeVersion
 	if k != nil {
 		kubeVersion, err = chartutil.ParseKubeVersion(k.ServerVersion.String())
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 	if k8sVersion != "" {
 		kubeVersion, err = chartutil.ParseKubeVersion(k8sVersion)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -203,9 +288,22 @@ func (hr *Release) doRender(ctx context.Context, k *k8s.K8sCluster, k8sVersion s
 		namespace = *hr.Config.Namespace
 	}
 
+	lookup, err := hr.buildLookupRecorder(ctx, k, sopsDecrypter)
+	if err != nil {
+		return nil, err
+	}
+	if lookup != nil {
+		restConfig, err := lookup.buildRESTConfig(k)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RESTClientGetter = &lookupRESTClientGetter{config: restConfig}
+	}
+
 	client := action.NewInstall(cfg)
 	client.DryRun = true
-	if k != nil {
+	if lookup != nil {
+		// this causes Helm to perform lookups while rendering
 		client.DryRunOption = "server"
 	}
 	client.Namespace = namespace
@@ -215,7 +313,7 @@ func (hr *Release) doRender(ctx context.Context, k *k8s.K8sCluster, k8sVersion s
 	client.KubeVersion = kubeVersion
 	client.APIVersions, err = hr.getApiVersions(k)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if hr.Config.SkipCRDs {
@@ -224,20 +322,24 @@ func (hr *Release) doRender(ctx context.Context, k *k8s.K8sCluster, k8sVersion s
 		client.IncludeCRDs = true
 	}
 
-	p := getter.All(settings)
-	vals, err := valueOpts.MergeValues(p)
+	client.PostRenderer, err = hr.buildExecPostRenderer()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	vals, err := hr.LoadValues(ctx, sopsDecrypter)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check chart dependencies to make sure all are present in /charts
 	chartRequested, err := loader.Load(pc.dir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := checkIfInstallable(chartRequested); err != nil {
-		return err
+		return nil, err
 	}
 
 	if chartRequested.Metadata.Deprecated {
@@ -246,46 +348,77 @@ func (hr *Release) doRender(ctx context.Context, k *k8s.K8sCluster, k8sVersion s
 
 	rel, err := client.Run(chartRequested, vals)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	hr.Lookups = nil
+	if lookup != nil {
+		hr.Lookups = lookup.getLookups()
 	}
 
-	parsed, err := hr.parseRenderedManifests(rel.Manifest)
+	parsed, err := parseManifests(rel.Manifest)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	hr.TestHooks = nil
 	if !client.DisableHooks {
 		for _, m := range rel.Hooks {
+			parsedHooks, err := parseManifests(m.Manifest)
+			if err != nil {
+				return nil, err
+			}
 			if isTestHook(m) {
+				hr.TestHooks = append(hr.TestHooks, parsedHooks...)
 				continue
 			}
-			parsedHooks, err := hr.parseRenderedManifests(m.Manifest)
-			if err != nil {
-				return err
-			}
 			parsed = append(parsed, parsedHooks...)
 		}
 	}
 
-	parsedI := make([]any, 0, len(parsed))
-	for _, o := range parsed {
-		if hr.Config.Namespace != nil {
-			// mark it for later namespace fixing
-			o.SetK8sAnnotation(InstallNamespaceAnnotation, *hr.Config.Namespace)
-		}
-		parsedI = append(parsedI, o)
+	return hr.applyPatches(parsed)
+}
+
+// buildLookupRecorder returns nil if lookups are disabled or can not be served, in which case the lookup function
+// returns empty results
+func (hr *Release) buildLookupRecorder(ctx context.Context, k *k8s.K8sCluster, sopsDecrypter *decryptor.Decryptor) (*lookupRecorder, error) {
+	if hr.Config.EnableLookup != nil && !*hr.Config.EnableLookup {
+		return nil, nil
 	}
 
-	rendered, err := yaml.WriteYamlAllBytes(parsedI)
-	if err != nil {
-		return err
+	var fixture *lookupFixture
+	if k == nil {
+		if hr.lookupFixture == "" {
+			return nil, nil
+		}
+		var err error
+		fixture, err = loadLookupFixture(ctx, hr.lookupFixture, sopsDecrypter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load lookupFixture: %w", err)
+		}
 	}
 
-	err = os.WriteFile(outputPath, rendered, 0o600)
-	if err != nil {
-		return err
+	return newLookupRecorder(hr.Config.ReleaseName, k, fixture)
+}
+
+// LoadValues loads and decrypts the helm-values.yaml that belongs to the release. An empty map is returned if the
+// release has no values file.
+func (hr *Release) LoadValues(ctx context.Context, sopsDecrypter *decryptor.Decryptor) (map[string]interface{}, error) {
+	valuesPath := yaml.FixPathExt(filepath.Join(filepath.Dir(hr.ConfigFile), "helm-values.yml"))
+
+	settings := cli.New()
+	valueOpts := values.Options{}
+
+	if utils.Exists(valuesPath) {
+		tmpValues, err := sops.MaybeDecryptFileToTmp(ctx, sopsDecrypter, valuesPath)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(tmpValues)
+		valueOpts.ValueFiles = append(valueOpts.ValueFiles, tmpValues)
 	}
-	return nil
+
+	return valueOpts.MergeValues(getter.All(settings))
 }
 
 func (hr *Release) getApiVersions(k *k8s.K8sCluster) (chartutil.VersionSet, error) {
@@ -314,7 +447,7 @@ func (hr *Release) getApiVersions(k *k8s.K8sCluster) (chartutil.VersionSet, erro
 	return ret, nil
 }
 
-func (hr *Release) parseRenderedManifests(s string) ([]*uo.UnstructuredObject, error) {
+func parseManifests(s string) ([]*uo.UnstructuredObject, error) {
 	var parsed []*uo.UnstructuredObject
 
 	duplicatesRemoved, err := yaml.RemoveDuplicateFields(strings.NewReader(s))
@@ -0,0 +1,61 @@
+package helm
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testHookTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: normal
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: test-pod
+  annotations:
+    helm.sh/hook: test
+spec:
+  containers:
+    - name: test
+      image: busybox
+`
+
+func TestRenderChartTestHooks(t *testing.T) {
+	chartDir := writeTestChart(t, "0.1.0", "", "")
+	err := os.MkdirAll(filepath.Join(chartDir, "templates"), 0o700)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(chartDir, "templates", "objects.yaml"), []byte(testHookTemplate), 0o600)
+	assert.NoError(t, err)
+
+	c, err := NewChart("", chartDir, "", nil, "", nil, nil)
+	assert.NoError(t, err)
+
+	config := &types.HelmChartConfig{}
+	config.ReleaseName = "test"
+	hr := &Release{
+		ConfigFile: filepath.Join(t.TempDir(), "helm-chart.yaml"),
+		Config:     config,
+		Chart:      c,
+	}
+	pc := NewPulledChart(c, "0.1.0", chartDir, false)
+
+	objects, err := hr.RenderChart(context.Background(), pc, nil, "", nil)
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+	assert.Equal(t, "normal", objects[0].GetK8sName())
+
+	assert.Len(t, hr.TestHooks, 1)
+	assert.Equal(t, "Pod", hr.TestHooks[0].GetK8sGVK().Kind)
+	assert.Equal(t, "test-pod", hr.TestHooks[0].GetK8sName())
+
+	// re-rendering must not accumulate test hooks
+	_, err = hr.RenderChart(context.Background(), pc, nil, "", nil)
+	assert.NoError(t, err)
+	assert.Len(t, hr.TestHooks, 1)
+}
@@ -0,0 +1,137 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"helm.sh/helm/v3/pkg/release"
+	"io"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sort"
+	"strconv"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// InstalledRelease represents a Helm release that was installed via the Helm CLI and is tracked via the
+// sh.helm.release.v1.* Secrets.
+type InstalledRelease struct {
+	Name      string
+	Namespace string
+
+	// Release is the latest deployed revision of the release
+	Release *release.Release
+
+	// SecretRefs contains the refs to all Secrets (all revisions) that Helm uses to track the release
+	SecretRefs []k8s2.ObjectRef
+}
+
+// ListInstalledReleases lists all Helm release Secrets with the given release name and returns the installed
+// releases, grouped by namespace. If namespace is empty, all namespaces are searched.
+func ListInstalledReleases(k *k8s.K8sCluster, name string, namespace string) ([]*InstalledRelease, error) {
+	secrets, _, err := k.ListObjects(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, namespace, map[string]string{
+		"owner": "helm",
+		"name":  name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byNamespace := map[string]*InstalledRelease{}
+	latestVersion := map[string]int{}
+	for _, s := range secrets {
+		ns := s.GetK8sNamespace()
+		ir, ok := byNamespace[ns]
+		if !ok {
+			ir = &InstalledRelease{
+				Name:      name,
+				Namespace: ns,
+			}
+			byNamespace[ns] = ir
+		}
+		ir.SecretRefs = append(ir.SecretRefs, s.GetK8sRef())
+
+		if x := s.GetK8sLabel("status"); x == nil || *x != release.StatusDeployed.String() {
+			continue
+		}
+		version := 0
+		if x := s.GetK8sLabel("version"); x != nil {
+			version, _ = strconv.Atoi(*x)
+		}
+		if ir.Release != nil && version <= latestVersion[ns] {
+			continue
+		}
+
+		rel, err := decodeReleaseSecret(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode Helm release secret %s: %w", s.GetK8sRef().String(), err)
+		}
+		ir.Release = rel
+		latestVersion[ns] = version
+	}
+
+	var ret []*InstalledRelease
+	for _, ir := range byNamespace {
+		sort.Slice(ir.SecretRefs, func(i, j int) bool {
+			return ir.SecretRefs[i].String() < ir.SecretRefs[j].String()
+		})
+		ret = append(ret, ir)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Namespace < ret[j].Namespace
+	})
+	return ret, nil
+}
+
+// GetManifestObjects parses the manifest of the latest deployed revision and returns all objects that Helm
+// installed. Hooks are not part of the manifest and thus not returned. The namespace of namespaced objects might be
+// empty, in which case Helm installed them into the release namespace.
+func (ir *InstalledRelease) GetManifestObjects() ([]*uo.UnstructuredObject, error) {
+	if ir.Release == nil {
+		return nil, nil
+	}
+	return parseManifests(ir.Release.Manifest)
+}
+
+// decodeReleaseSecret decodes the release stored in a Helm release Secret. Helm stores the release as base64
+// encoded and optionally gzipped json, which is then base64 encoded again by Kubernetes.
+func decodeReleaseSecret(s *uo.UnstructuredObject) (*release.Release, error) {
+	data, ok, err := s.GetNestedString("data", "release")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("secret has no release data")
+	}
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	b, err = base64.StdEncoding.DecodeString(string(b))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > 3 && bytes.Equal(b[0:3], gzipMagic) {
+		r, err := gzip.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		b, err = io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var rel release.Release
+	err = json.Unmarshal(b, &rel)
+	if err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
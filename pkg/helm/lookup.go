@@ -0,0 +1,331 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/sops"
+	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
+	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"io"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// lookupRecorder records all requests performed by the Helm "lookup" template function. It only allows read-only
+// requests and either forwards them to the target cluster or serves them from fixture objects when rendering without
+// access to a cluster.
+type lookupRecorder struct {
+	releaseName string
+	mapper      meta.RESTMapper
+	fixture     *lookupFixture
+
+	mutex   sync.Mutex
+	lookups []result.HelmLookup
+}
+
+type lookupRoundTripper struct {
+	r    *lookupRecorder
+	next http.RoundTripper
+}
+
+// lookupRESTClientGetter implements action.RESTClientGetter. Helm only uses ToRESTConfig when rendering in client-only
+// mode.
+type lookupRESTClientGetter struct {
+	config *rest.Config
+}
+
+type lookupFixture struct {
+	objects []*uo.UnstructuredObject
+	kinds   map[schema.GroupVersionResource]string
+}
+
+// lookupRequest is a parsed Kubernetes API request path
+type lookupRequest struct {
+	gv        schema.GroupVersion
+	resource  string
+	namespace string
+	name      string
+}
+
+func newLookupRecorder(releaseName string, k *k8s.K8sCluster, fixture *lookupFixture) (*lookupRecorder, error) {
+	r := &lookupRecorder{
+		releaseName: releaseName,
+		fixture:     fixture,
+	}
+	if k != nil {
+		var err error
+		r.mapper, err = k.ToRESTMapper()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *lookupRecorder) buildRESTConfig(k *k8s.K8sCluster) (*rest.Config, error) {
+	if k == nil {
+		return &rest.Config{
+			Host:      "http://offline-lookup.invalid",
+			Transport: &lookupRoundTripper{r: r},
+		}, nil
+	}
+
+	config, err := k.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &lookupRoundTripper{r: r, next: rt}
+	})
+	return config, nil
+}
+
+func (r *lookupRecorder) getLookups() []result.HelmLookup {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]result.HelmLookup(nil), r.lookups...)
+}
+
+func (r *lookupRecorder) record(lr *lookupRequest, found bool) {
+	kind := lr.resource
+	if r.fixture != nil {
+		kind = r.fixture.kinds[lr.gv.WithResource(lr.resource)]
+		if kind == "" {
+			// the kind is neither known by Kubernetes nor by the fixture, so there is nothing meaningful to record
+			return
+		}
+	} else if r.mapper != nil {
+		gvk, err := r.mapper.KindFor(lr.gv.WithResource(lr.resource))
+		if err == nil {
+			kind = gvk.Kind
+		}
+	}
+
+	ref := k8s2.NewObjectRef(lr.gv.Group, lr.gv.Version, kind, lr.name, lr.namespace)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i := range r.lookups {
+		if r.lookups[i].Ref == ref {
+			r.lookups[i].Found = found
+			return
+		}
+	}
+	r.lookups = append(r.lookups, result.HelmLookup{
+		ReleaseName: r.releaseName,
+		Ref:         ref,
+		Found:       found,
+	})
+}
+
+func (rt *lookupRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return nil, fmt.Errorf("%s requests are not allowed while rendering Helm Charts", req.Method)
+	}
+
+	var resp *http.Response
+	var err error
+	if rt.r.fixture != nil {
+		resp, err = rt.r.fixture.serve(req)
+	} else {
+		resp, err = rt.next.RoundTrip(req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lr := parseLookupRequest(req.URL.Path)
+	if lr != nil && lr.resource != "" {
+		rt.r.record(lr, resp.StatusCode == http.StatusOK)
+	}
+	return resp, nil
+}
+
+func (g *lookupRESTClientGetter) ToRESTConfig() (*rest.Config, error) {
+	return g.config, nil
+}
+
+func (g *lookupRESTClientGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	return nil, fmt.Errorf("discovery is not supported while rendering Helm Charts")
+}
+
+func (g *lookupRESTClientGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	return nil, fmt.Errorf("RESTMapper is not supported while rendering Helm Charts")
+}
+
+// parseLookupRequest parses paths in the form of /api/<v>/[namespaces/<ns>/]<resource>[/<name>] and
+// /apis/<g>/<v>/[namespaces/<ns>/]<resource>[/<name>]. The resource is empty for discovery requests. nil is returned
+// for all other paths.
+func parseLookupRequest(p string) *lookupRequest {
+	s := strings.Split(strings.Trim(p, "/"), "/")
+
+	var ret lookupRequest
+	if len(s) >= 2 && s[0] == "api" {
+		ret.gv = schema.GroupVersion{Version: s[1]}
+		s = s[2:]
+	} else if len(s) >= 3 && s[0] == "apis" {
+		ret.gv = schema.GroupVersion{Group: s[1], Version: s[2]}
+		s = s[3:]
+	} else {
+		return nil
+	}
+
+	if len(s) >= 3 && s[0] == "namespaces" {
+		ret.namespace = s[1]
+		s = s[2:]
+	}
+	switch len(s) {
+	case 0:
+	case 1:
+		ret.resource = s[0]
+	case 2:
+		ret.resource = s[0]
+		ret.name = s[1]
+	default:
+		// sub-resources are not supported by lookup
+		return nil
+	}
+	return &ret
+}
+
+func loadLookupFixture(ctx context.Context, p string, sopsDecrypter *decryptor.Decryptor) (*lookupFixture, error) {
+	tmpFile, err := sops.MaybeDecryptFileToTmp(ctx, sopsDecrypter, p)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmpFile)
+
+	l, err := yaml.ReadYamlAllFile(tmpFile)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &lookupFixture{
+		kinds: map[schema.GroupVersionResource]string{},
+	}
+	addKind := func(gvk schema.GroupVersionKind) {
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		f.kinds[gvr] = gvk.Kind
+	}
+	for gvk := range scheme.Scheme.AllKnownTypes() {
+		addKind(gvk)
+	}
+
+	for _, x := range l {
+		if x == nil {
+			continue
+		}
+		m, ok := x.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("yaml object is not a map")
+		}
+		o := uo.FromMap(m)
+		f.objects = append(f.objects, o)
+		addKind(o.GetK8sGVK())
+	}
+	return f, nil
+}
+
+func (f *lookupFixture) serve(req *http.Request) (*http.Response, error) {
+	lr := parseLookupRequest(req.URL.Path)
+	if lr == nil || (lr.gv.Group == "" && lr.gv.Version != "v1") {
+		// the legacy API group only knows v1. Other versions are requested by Helm when a kind could not be resolved
+		// via discovery
+		return f.buildResponse(req, http.StatusNotFound, apierrors.NewNotFound(schema.GroupResource{}, req.URL.Path).ErrStatus)
+	}
+
+	if lr.resource == "" {
+		// discovery request for a single group version. Unknown group versions result in an empty list so that lookup
+		// returns nothing instead of failing. All resources are marked as namespaced, as the scope is unknown for
+		// fixture objects. This still works for cluster-scoped objects as these are looked up without a namespace.
+		rl := metav1.APIResourceList{
+			TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+			GroupVersion: lr.gv.String(),
+		}
+		for gvr, kind := range f.kinds {
+			if gvr.GroupVersion() != lr.gv {
+				continue
+			}
+			rl.APIResources = append(rl.APIResources, metav1.APIResource{
+				Name:       gvr.Resource,
+				Kind:       kind,
+				Namespaced: true,
+				Verbs:      metav1.Verbs{"get", "list"},
+			})
+		}
+		return f.buildResponse(req, http.StatusOK, rl)
+	}
+
+	kind, ok := f.kinds[lr.gv.WithResource(lr.resource)]
+	if !ok {
+		gr := schema.GroupResource{Group: lr.gv.Group, Resource: lr.resource}
+		return f.buildResponse(req, http.StatusNotFound, apierrors.NewNotFound(gr, lr.name).ErrStatus)
+	}
+
+	var matching []any
+	for _, o := range f.objects {
+		gvk := o.GetK8sGVK()
+		if gvk.GroupVersion() != lr.gv || gvk.Kind != kind {
+			continue
+		}
+		if lr.namespace != "" && o.GetK8sNamespace() != lr.namespace {
+			continue
+		}
+		if lr.name != "" && o.GetK8sName() != lr.name {
+			continue
+		}
+		matching = append(matching, o.Object)
+	}
+
+	if lr.name != "" {
+		if len(matching) == 0 {
+			gr := schema.GroupResource{Group: lr.gv.Group, Resource: lr.resource}
+			return f.buildResponse(req, http.StatusNotFound, apierrors.NewNotFound(gr, lr.name).ErrStatus)
+		}
+		return f.buildResponse(req, http.StatusOK, matching[0])
+	}
+
+	if matching == nil {
+		matching = []any{}
+	}
+	return f.buildResponse(req, http.StatusOK, map[string]any{
+		"apiVersion": lr.gv.String(),
+		"kind":       kind + "List",
+		"metadata":   map[string]any{},
+		"items":      matching,
+	})
+}
+
+func (f *lookupFixture) buildResponse(req *http.Request, statusCode int, o any) (*http.Response, error) {
+	if s, ok := o.(metav1.Status); ok {
+		s.Kind = "Status"
+		s.APIVersion = "v1"
+		o = s
+	}
+	b, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(b)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,175 @@
+package helm
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const lookupTestTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: lookup-result
+data:
+  {{- $cm := lookup "v1" "ConfigMap" "ns1" "cm1" }}
+  {{- if $cm }}
+  found: {{ $cm.data.a | quote }}
+  {{- end }}
+  {{- $secret := lookup "v1" "Secret" "ns1" "missing" }}
+  {{- if not $secret }}
+  missing: "true"
+  {{- end }}
+  {{- $list := lookup "v1" "ConfigMap" "ns1" "" }}
+  count: {{ len (default (list) $list.items) | quote }}
+  {{- $crd := lookup "example.com/v1" "MyResource" "" "x" }}
+  {{- if not $crd }}
+  unknownGroup: "true"
+  {{- end }}
+`
+
+const lookupTestFixture = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm1
+  namespace: ns1
+data:
+  a: fixtureValue
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm2
+  namespace: ns1
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm3
+  namespace: ns2
+`
+
+func TestParseLookupRequest(t *testing.T) {
+	tests := []struct {
+		path string
+		want *lookupRequest
+	}{
+		{path: "/api/v1", want: &lookupRequest{gv: schema.GroupVersion{Version: "v1"}}},
+		{path: "/apis/apps/v1", want: &lookupRequest{gv: schema.GroupVersion{Group: "apps", Version: "v1"}}},
+		{path: "/api/v1/namespaces", want: &lookupRequest{gv: schema.GroupVersion{Version: "v1"}, resource: "namespaces"}},
+		{path: "/api/v1/namespaces/ns1", want: &lookupRequest{gv: schema.GroupVersion{Version: "v1"}, resource: "namespaces", name: "ns1"}},
+		{path: "/api/v1/namespaces/ns1/configmaps", want: &lookupRequest{gv: schema.GroupVersion{Version: "v1"}, resource: "configmaps", namespace: "ns1"}},
+		{path: "/api/v1/namespaces/ns1/configmaps/cm1", want: &lookupRequest{gv: schema.GroupVersion{Version: "v1"}, resource: "configmaps", namespace: "ns1", name: "cm1"}},
+		{path: "/apis/apps/v1/namespaces/ns1/deployments/d1", want: &lookupRequest{gv: schema.GroupVersion{Group: "apps", Version: "v1"}, resource: "deployments", namespace: "ns1", name: "d1"}},
+		{path: "/apis/rbac.authorization.k8s.io/v1/clusterroles/r1", want: &lookupRequest{gv: schema.GroupVersion{Group: "rbac.authorization.k8s.io", Version: "v1"}, resource: "clusterroles", name: "r1"}},
+		{path: "/apis/apps/v1/namespaces/ns1/deployments/d1/status", want: nil},
+		{path: "/api", want: nil},
+		{path: "/version", want: nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.want, parseLookupRequest(tc.path))
+		})
+	}
+}
+
+func newLookupTestRelease(t *testing.T, config types.HelmChartConfig) (*Release, *PulledChart) {
+	chartDir := writeTestChart(t, "0.1.0", "", "")
+	err := os.MkdirAll(filepath.Join(chartDir, "templates"), 0o700)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(chartDir, "templates", "cm.yaml"), []byte(lookupTestTemplate), 0o600)
+	assert.NoError(t, err)
+
+	releaseDir := t.TempDir()
+	err = os.WriteFile(filepath.Join(releaseDir, "fixture.yaml"), []byte(lookupTestFixture), 0o600)
+	assert.NoError(t, err)
+
+	c, err := NewChart("", chartDir, "", nil, "", nil, nil)
+	assert.NoError(t, err)
+
+	config.ReleaseName = "test"
+	hr := &Release{
+		ConfigFile: filepath.Join(releaseDir, "helm-chart.yaml"),
+		Config:     &config,
+		Chart:      c,
+	}
+	if config.LookupFixture != "" {
+		hr.lookupFixture = filepath.Join(releaseDir, config.LookupFixture)
+	}
+	return hr, NewPulledChart(c, "0.1.0", chartDir, false)
+}
+
+func renderLookupResult(t *testing.T, hr *Release, pc *PulledChart) map[string]string {
+	objects, err := hr.RenderChart(context.Background(), pc, nil, "", nil)
+	assert.NoError(t, err)
+	assert.Len(t, objects, 1)
+
+	ret, _, err := objects[0].GetNestedStringMapCopy("data")
+	assert.NoError(t, err)
+	return ret
+}
+
+func TestLookupFixture(t *testing.T) {
+	hr, pc := newLookupTestRelease(t, types.HelmChartConfig{
+		HelmChartConfig2: types.HelmChartConfig2{
+			LookupFixture: "fixture.yaml",
+		},
+	})
+
+	data := renderLookupResult(t, hr, pc)
+	assert.Equal(t, map[string]string{
+		"found":        "fixtureValue",
+		"missing":      "true",
+		"count":        "2",
+		"unknownGroup": "true",
+	}, data)
+
+	assert.Contains(t, hr.Lookups, result.HelmLookup{
+		ReleaseName: "test",
+		Ref:         k8s.NewObjectRef("", "v1", "ConfigMap", "cm1", "ns1"),
+		Found:       true,
+	})
+	assert.Contains(t, hr.Lookups, result.HelmLookup{
+		ReleaseName: "test",
+		Ref:         k8s.NewObjectRef("", "v1", "ConfigMap", "", "ns1"),
+		Found:       true,
+	})
+	assert.Contains(t, hr.Lookups, result.HelmLookup{
+		ReleaseName: "test",
+		Ref:         k8s.NewObjectRef("", "v1", "Secret", "missing", "ns1"),
+		Found:       false,
+	})
+	assert.Len(t, hr.Lookups, 3)
+}
+
+func TestLookupWithoutFixture(t *testing.T) {
+	hr, pc := newLookupTestRelease(t, types.HelmChartConfig{})
+
+	data := renderLookupResult(t, hr, pc)
+	assert.Equal(t, map[string]string{
+		"missing":      "true",
+		"count":        "0",
+		"unknownGroup": "true",
+	}, data)
+	assert.Empty(t, hr.Lookups)
+}
+
+func TestLookupDisabled(t *testing.T) {
+	enableLookup := false
+	hr, pc := newLookupTestRelease(t, types.HelmChartConfig{
+		HelmChartConfig2: types.HelmChartConfig2{
+			EnableLookup:  &enableLookup,
+			LookupFixture: "fixture.yaml",
+		},
+	})
+
+	data := renderLookupResult(t, hr, pc)
+	assert.Equal(t, "0", data["count"])
+	assert.NotContains(t, data, "found")
+	assert.Empty(t, hr.Lookups)
+}
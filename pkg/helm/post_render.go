@@ -0,0 +1,90 @@
+package helm
+
+import (
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/utils/flux_utils/kustomize"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"helm.sh/helm/v3/pkg/postrender"
+	"path/filepath"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"strings"
+)
+
+// resolvePostRendererCommand resolves the command of the exec post-renderer. Commands that contain a path separator
+// are resolved relative to the directory of the helm-chart.yaml and must be inside the project. All other commands are
+// looked up in PATH.
+func resolvePostRendererCommand(projectRoot string, configDir string, command string) (string, error) {
+	if !strings.ContainsRune(command, '/') && !strings.ContainsRune(command, filepath.Separator) {
+		return command, nil
+	}
+	p, err := resolvePathInProject(projectRoot, configDir, command)
+	if err != nil {
+		return "", fmt.Errorf("invalid postRenderer command: %w", err)
+	}
+	return p, nil
+}
+
+func (hr *Release) buildExecPostRenderer() (postrender.PostRenderer, error) {
+	if hr.Config.PostRenderer == nil || hr.Config.PostRenderer.Exec == nil {
+		return nil, nil
+	}
+	if !hr.AllowExecPostRenderer {
+		return nil, fmt.Errorf("helm-chart.yaml configures an exec post-renderer, but exec post-renderers are not allowed. Pass --helm-allow-exec-post-renderers to allow them")
+	}
+	return postrender.NewExec(hr.postRendererCommand, hr.Config.PostRenderer.Exec.Args...)
+}
+
+// applyPatches applies the strategic merge and JSON6902 patches from the postRenderer config to the rendered objects.
+// This is done via an in-memory kustomize build, so the patches behave exactly like the patches in a
+// kustomization.yaml.
+func (hr *Release) applyPatches(objects []*uo.UnstructuredObject) ([]*uo.UnstructuredObject, error) {
+	if hr.Config.PostRenderer == nil || len(hr.Config.PostRenderer.Patches) == 0 {
+		return objects, nil
+	}
+
+	fs := filesys.MakeFsInMemory()
+
+	l := make([]any, 0, len(objects))
+	for _, o := range objects {
+		l = append(l, o)
+	}
+	b, err := yaml.WriteYamlAllBytes(l)
+	if err != nil {
+		return nil, err
+	}
+	err = fs.WriteFile("/helm/helm-rendered.yaml", b)
+	if err != nil {
+		return nil, err
+	}
+
+	kustomization := map[string]any{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  []string{"helm-rendered.yaml"},
+		"patches":    hr.Config.PostRenderer.Patches,
+	}
+	b, err = yaml.WriteYamlBytes(kustomization)
+	if err != nil {
+		return nil, err
+	}
+	err = fs.WriteFile("/helm/kustomization.yaml", b)
+	if err != nil {
+		return nil, err
+	}
+
+	rm, err := kustomize.Build(fs, "/helm")
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patches: %w", err)
+	}
+
+	var ret []*uo.UnstructuredObject
+	for _, r := range rm.Resources() {
+		m, err := r.Map()
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, uo.FromMap(m))
+	}
+	return ret, nil
+}
@@ -0,0 +1,151 @@
+package helm
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ValuesCheckResult contains the result of checking the helm-values.yaml of a release against a new chart version
+type ValuesCheckResult struct {
+	// SchemaError is set when the values do not validate against the values.schema.json of the new chart version
+	SchemaError error
+
+	// RemovedKeys contains the json paths of all values that exist in the old chart's default values but not in
+	// the new chart's default values
+	RemovedKeys []string
+
+	// DefaultValuesChanges contains the differences between the default values of the old and new chart versions
+	DefaultValuesChanges []result.Change
+
+	// TemplatedKeys contains the json paths of all values that contain jinja2 templates. These can't be rendered
+	// without a target and are thus excluded from schema validation.
+	TemplatedKeys []string
+}
+
+// ErrTemplatedValues is returned by CheckValues when helm-values.yaml can only be parsed after rendering templates
+var ErrTemplatedValues = fmt.Errorf("helm-values.yaml contains templates that must be rendered before it can be parsed")
+
+// CheckValues checks the helm-values.yaml of the release against the chart pulled as newPc. oldPc must point
+// to the currently used chart version.
+// helm-values.yaml is loaded without rendering templates and without decrypting it, so the check is done on the raw
+// values. Values that contain templates are excluded from schema validation.
+func (hr *Release) CheckValues(ctx context.Context, oldPc *PulledChart, newPc *PulledChart) (*ValuesCheckResult, error) {
+	values, err := hr.LoadValues(ctx, nil)
+	if err != nil {
+		raw, err2 := os.ReadFile(yaml.FixPathExt(filepath.Join(filepath.Dir(hr.ConfigFile), "helm-values.yml")))
+		if err2 == nil && isTemplatedString(string(raw)) {
+			return nil, ErrTemplatedValues
+		}
+		return nil, err
+	}
+	if _, ok := values["sops"]; ok {
+		return nil, fmt.Errorf("helm-values.yaml is encrypted via sops")
+	}
+
+	oldChart, err := loader.Load(oldPc.dir)
+	if err != nil {
+		return nil, err
+	}
+	newChart, err := loader.Load(newPc.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	oldDefaults, err := chartutil.CoalesceValues(oldChart, map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+	newDefaults, err := chartutil.CoalesceValues(newChart, map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+
+	var r ValuesCheckResult
+	untemplatedValues := removeTemplatedValues(values, nil, &r.TemplatedKeys)
+	merged, err := chartutil.CoalesceValues(newChart, untemplatedValues)
+	if err != nil {
+		return nil, err
+	}
+
+	r.SchemaError = chartutil.ValidateAgainstSchema(newChart, merged)
+	r.RemovedKeys = findRemovedValues(values, oldDefaults, newDefaults, nil)
+	r.DefaultValuesChanges, err = diff.Diff(uo.FromMap(oldDefaults), uo.FromMap(newDefaults))
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// findRemovedValues returns the json paths of all keys from values which exist in oldDefaults but not in newDefaults.
+// Keys that never existed in oldDefaults are ignored, as many charts document optional values only via comments.
+func findRemovedValues(values map[string]any, oldDefaults map[string]any, newDefaults map[string]any, path uo.KeyPath) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var ret []string
+	for _, k := range keys {
+		p := append(append(uo.KeyPath{}, path...), k)
+
+		oldV, ok := oldDefaults[k]
+		if !ok {
+			continue
+		}
+		newV, ok := newDefaults[k]
+		if !ok {
+			ret = append(ret, p.ToJsonPath())
+			continue
+		}
+
+		v, ok1 := values[k].(map[string]any)
+		oldM, ok2 := oldV.(map[string]any)
+		newM, ok3 := newV.(map[string]any)
+		if ok1 && ok2 && ok3 && len(newM) != 0 {
+			ret = append(ret, findRemovedValues(v, oldM, newM, p)...)
+		}
+	}
+	return ret
+}
+
+func isTemplatedString(s string) bool {
+	return strings.Contains(s, "{{") || strings.Contains(s, "{%")
+}
+
+// removeTemplatedValues returns a copy of values with all templated strings removed. The json paths of the removed
+// values are appended to templatedKeys.
+func removeTemplatedValues(values map[string]any, path uo.KeyPath, templatedKeys *[]string) map[string]any {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	ret := make(map[string]any, len(values))
+	for _, k := range keys {
+		p := append(append(uo.KeyPath{}, path...), k)
+		switch v := values[k].(type) {
+		case string:
+			if isTemplatedString(v) {
+				*templatedKeys = append(*templatedKeys, p.ToJsonPath())
+				continue
+			}
+		case map[string]any:
+			ret[k] = removeTemplatedValues(v, p, templatedKeys)
+			continue
+		}
+		ret[k] = values[k]
+	}
+	return ret
+}
@@ -0,0 +1,126 @@
+package helm
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestChart(t *testing.T, version string, values string, schema string) string {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("apiVersion: v2\nname: test-chart\nversion: "+version+"\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "values.yaml"), []byte(values), 0o600)
+	assert.NoError(t, err)
+	if schema != "" {
+		err = os.WriteFile(filepath.Join(dir, "values.schema.json"), []byte(schema), 0o600)
+		assert.NoError(t, err)
+	}
+	return dir
+}
+
+func TestCheckValues(t *testing.T) {
+	oldDir := writeTestChart(t, "0.1.0", `
+replicas: 1
+image:
+  tag: "1.0"
+  pullPolicy: IfNotPresent
+oldKey: x
+podAnnotations: {}
+`, "")
+	newDir := writeTestChart(t, "0.2.0", `
+replicas: 1
+image:
+  tag: "2.0"
+podAnnotations: {}
+`, `{
+  "type": "object",
+  "properties": {
+    "replicas": {"type": "integer"}
+  }
+}`)
+
+	releaseDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(releaseDir, "helm-values.yaml"), []byte(`
+replicas: "2"
+image:
+  pullPolicy: Always
+oldKey: y
+notInDefaults: z
+podAnnotations:
+  a: b
+`), 0o600)
+	assert.NoError(t, err)
+
+	c, err := NewChart("oci://example.com/charts/test-chart", "", "", nil, "", nil, nil)
+	assert.NoError(t, err)
+	hr := &Release{
+		ConfigFile: filepath.Join(releaseDir, "helm-chart.yaml"),
+		Config:     &types.HelmChartConfig{},
+		Chart:      c,
+	}
+
+	r, err := hr.CheckValues(context.Background(), NewPulledChart(c, "0.1.0", oldDir, false), NewPulledChart(c, "0.2.0", newDir, false))
+	assert.NoError(t, err)
+
+	assert.ErrorContains(t, r.SchemaError, "replicas: Invalid type. Expected: integer, given: string")
+	assert.Equal(t, []string{"image.pullPolicy", "oldKey"}, r.RemovedKeys)
+
+	var paths []string
+	for _, x := range r.DefaultValuesChanges {
+		paths = append(paths, x.JsonPath)
+	}
+	assert.Equal(t, []string{"image.pullPolicy", "image.tag", "oldKey"}, paths)
+
+	err = os.WriteFile(filepath.Join(releaseDir, "helm-values.yaml"), []byte("replicas: 2\n"), 0o600)
+	assert.NoError(t, err)
+	r, err = hr.CheckValues(context.Background(), NewPulledChart(c, "0.1.0", oldDir, false), NewPulledChart(c, "0.2.0", newDir, false))
+	assert.NoError(t, err)
+	assert.NoError(t, r.SchemaError)
+	assert.Empty(t, r.RemovedKeys)
+}
+
+func TestCheckValuesTemplated(t *testing.T) {
+	schema := `{
+  "type": "object",
+  "properties": {
+    "replicas": {"type": "integer"},
+    "image": {"type": "object", "properties": {"tag": {"type": "string"}}}
+  }
+}`
+	oldDir := writeTestChart(t, "0.1.0", "replicas: 1\nimage:\n  tag: \"1.0\"\n", "")
+	newDir := writeTestChart(t, "0.2.0", "replicas: 1\nimage:\n  tag: \"2.0\"\n", schema)
+
+	releaseDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(releaseDir, "helm-values.yaml"), []byte(`
+replicas: "{{ args.replicas }}"
+image:
+  tag: "2.1"
+`), 0o600)
+	assert.NoError(t, err)
+
+	c, err := NewChart("oci://example.com/charts/test-chart", "", "", nil, "", nil, nil)
+	assert.NoError(t, err)
+	hr := &Release{
+		ConfigFile: filepath.Join(releaseDir, "helm-chart.yaml"),
+		Config:     &types.HelmChartConfig{},
+		Chart:      c,
+	}
+
+	r, err := hr.CheckValues(context.Background(), NewPulledChart(c, "0.1.0", oldDir, false), NewPulledChart(c, "0.2.0", newDir, false))
+	assert.NoError(t, err)
+	assert.NoError(t, r.SchemaError)
+	assert.Equal(t, []string{"replicas"}, r.TemplatedKeys)
+
+	err = os.WriteFile(filepath.Join(releaseDir, "helm-values.yaml"), []byte(`
+{% if args.enabled %}
+replicas: 2
+{% endif %}
+`), 0o600)
+	assert.NoError(t, err)
+	_, err = hr.CheckValues(context.Background(), NewPulledChart(c, "0.1.0", oldDir, false), NewPulledChart(c, "0.2.0", newDir, false))
+	assert.ErrorIs(t, err, ErrTemplatedValues)
+}
@@ -0,0 +1,295 @@
+package helm
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	verificationMarkerFile = ".kluctl-verification.yaml"
+
+	verificationMethodProvenance = "provenance"
+	verificationMethodCosign     = "cosign"
+
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+)
+
+// chartVerification is written into the pulled chart dir after the chart got successfully verified. It is used to
+// ensure that pre-pulled and cached charts have been verified before they are used. ContentDigest is computed from
+// the extracted chart files right after verification and re-computed whenever the pulled chart is used, so that
+// modifications to the chart files are detected.
+type chartVerification struct {
+	Method        string `json:"method"`
+	Digest        string `json:"digest,omitempty"`
+	ContentDigest string `json:"contentDigest"`
+}
+
+type cosignSimpleSigning struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+}
+
+func (c *Chart) getVerificationMethod() string {
+	if c.verify == nil {
+		return ""
+	}
+	if c.verify.Keyring != "" {
+		return verificationMethodProvenance
+	}
+	return verificationMethodCosign
+}
+
+func (c *Chart) buildCraneOptions(ctx context.Context) ([]crane.Option, error) {
+	var clientOpts []crane.Option
+	clientOpts = append(clientOpts, crane.WithContext(ctx))
+	if c.ociAuthProvider != nil {
+		auth, err := c.ociAuthProvider.FindAuthEntry(ctx, c.repo)
+		if err != nil {
+			return nil, err
+		}
+		authOpts, err := auth.BuildCraneOptions()
+		if err != nil {
+			return nil, err
+		}
+		clientOpts = append(clientOpts, authOpts...)
+	}
+	return clientOpts, nil
+}
+
+// verifyCosign verifies that the OCI chart with the given version was signed with the configured cosign key and
+// returns the verified manifest digest.
+func (c *Chart) verifyCosign(ctx context.Context, version string) (string, error) {
+	pubKey, err := loadCosignPublicKey(c.verify.CosignKey)
+	if err != nil {
+		return "", err
+	}
+
+	opts, err := c.buildCraneOptions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	repo := strings.TrimPrefix(c.repo, "oci://")
+	digest, err := crane.Digest(repo+":"+version, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	err = verifyCosignSignature(repo, digest, pubKey, opts...)
+	if err != nil {
+		return "", fmt.Errorf("cosign verification of %s:%s failed: %w", repo, version, err)
+	}
+	return digest, nil
+}
+
+func loadCosignPublicKey(path string) (crypto.PublicKey, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM from %s", path)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// verifyCosignSignature looks up the cosign signature image (stored as sha256-<digest>.sig tag) and verifies that
+// at least one of the contained signatures is valid for the given digest and public key.
+func verifyCosignSignature(repo string, digest string, pubKey crypto.PublicKey, opts ...crane.Option) error {
+	sigRef := repo + ":" + strings.Replace(digest, ":", "-", 1) + ".sig"
+	img, err := crane.Pull(sigRef, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to pull signature %s: %w", sigRef, err)
+	}
+	m, err := img.Manifest()
+	if err != nil {
+		return err
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for i, l := range m.Layers {
+		sigB64, ok := l.Annotations[cosignSignatureAnnotation]
+		if !ok || i >= len(layers) {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(sigB64)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		r, err := layers[i].Compressed()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		payload, err := io.ReadAll(r)
+		_ = r.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		err = verifySignature(pubKey, payload, sig)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var ss cosignSimpleSigning
+		err = json.Unmarshal(payload, &ss)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if ss.Critical.Image.DockerManifestDigest != digest {
+			lastErr = fmt.Errorf("signed digest %s does not match %s", ss.Critical.Image.DockerManifestDigest, digest)
+			continue
+		}
+		return nil
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return fmt.Errorf("no signatures found in %s", sigRef)
+}
+
+func verifySignature(pubKey crypto.PublicKey, payload []byte, sig []byte) error {
+	h := sha256.Sum256(payload)
+	switch k := pubKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, h[:], sig) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, h[:], sig)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, payload, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", pubKey)
+	}
+}
+
+func writeVerificationMarker(dir string, v chartVerification) error {
+	return yaml.WriteYamlFile(filepath.Join(dir, verificationMarkerFile), &v)
+}
+
+// hashChartDir computes a digest over all files (names and contents) found in the given chart dir, excluding the
+// verification marker itself.
+func hashChartDir(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == verificationMarkerFile {
+			return nil
+		}
+
+		switch {
+		case d.IsDir():
+			_, _ = fmt.Fprintf(h, "d %s\n", rel)
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(h, "l %s %s\n", rel, target)
+		case d.Type().IsRegular():
+			b, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(h, "f %s %x\n", rel, sha256.Sum256(b))
+		default:
+			return fmt.Errorf("unexpected file type for %s", rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+// CheckVerification checks that the pulled chart got verified with the method that is configured for the chart.
+func (pc *PulledChart) CheckVerification() error {
+	method := pc.chart.getVerificationMethod()
+	if method == "" {
+		return nil
+	}
+
+	p := filepath.Join(pc.dir, verificationMarkerFile)
+	if !utils.Exists(p) {
+		return fmt.Errorf("Helm Chart %s with version %s has not been verified", pc.chart.GetChartName(), pc.version)
+	}
+	var v chartVerification
+	err := yaml.ReadYamlFile(p, &v)
+	if err != nil {
+		return err
+	}
+	if v.Method != method {
+		return fmt.Errorf("Helm Chart %s with version %s has been verified via %s while %s is required", pc.chart.GetChartName(), pc.version, v.Method, method)
+	}
+
+	contentDigest, err := hashChartDir(pc.dir)
+	if err != nil {
+		return err
+	}
+	if v.ContentDigest == "" || v.ContentDigest != contentDigest {
+		return fmt.Errorf("Helm Chart %s with version %s has been modified after it got verified", pc.chart.GetChartName(), pc.version)
+	}
+	return nil
+}
+
+func parseProvenanceHash(helmOut string) string {
+	return parseHelmOutput(helmOut, "Chart Hash Verified: ")
+}
+
+// parsePulledOciDigest returns the manifest digest that Helm reports after pulling an OCI chart
+func parsePulledOciDigest(helmOut string) string {
+	return parseHelmOutput(helmOut, "Digest: ")
+}
+
+func parseHelmOutput(helmOut string, prefix string) string {
+	for _, l := range strings.Split(helmOut, "\n") {
+		if x, ok := strings.CutPrefix(l, prefix); ok {
+			return strings.TrimSpace(x)
+		}
+	}
+	return ""
+}
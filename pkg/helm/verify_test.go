@@ -0,0 +1,144 @@
+package helm
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	types2 "github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func pushTestSignature(t *testing.T, repo string, digest string, signedDigest string, key *ecdsa.PrivateKey) {
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"%s"},"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, repo, signedDigest))
+	h := sha256.Sum256(payload)
+	sig, err := ecdsa.SignASN1(rand.Reader, key, h[:])
+	assert.NoError(t, err)
+
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(payload, "application/vnd.dev.cosign.simplesigning.v1+json"),
+		Annotations: map[string]string{
+			cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+		},
+		MediaType: types.MediaType("application/vnd.dev.cosign.simplesigning.v1+json"),
+	})
+	assert.NoError(t, err)
+
+	err = crane.Push(img, repo+":"+strings.Replace(digest, ":", "-", 1)+".sig")
+	assert.NoError(t, err)
+}
+
+func TestVerifyCosignSignature(t *testing.T) {
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+	u, err := url.Parse(s.URL)
+	assert.NoError(t, err)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	pushImage := func(repo string) string {
+		img, err := random.Image(16, 1)
+		assert.NoError(t, err)
+		err = crane.Push(img, repo+":0.1.0")
+		assert.NoError(t, err)
+		digest, err := crane.Digest(repo + ":0.1.0")
+		assert.NoError(t, err)
+		return digest
+	}
+
+	repo := u.Host + "/charts/signed"
+	digest := pushImage(repo)
+	pushTestSignature(t, repo, digest, digest, key)
+
+	err = verifyCosignSignature(repo, digest, &key.PublicKey)
+	assert.NoError(t, err)
+
+	err = verifyCosignSignature(repo, digest, &otherKey.PublicKey)
+	assert.ErrorContains(t, err, "invalid signature")
+
+	repo = u.Host + "/charts/wrong-digest"
+	digest = pushImage(repo)
+	pushTestSignature(t, repo, digest, "sha256:0000", key)
+	err = verifyCosignSignature(repo, digest, &key.PublicKey)
+	assert.ErrorContains(t, err, "does not match")
+
+	repo = u.Host + "/charts/unsigned"
+	digest = pushImage(repo)
+	err = verifyCosignSignature(repo, digest, &key.PublicKey)
+	assert.ErrorContains(t, err, "failed to pull signature")
+}
+
+func TestCheckVerification(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := NewChart("oci://example.com/charts/test", "", "", nil, "", nil, &types2.HelmVerifyConfig{CosignKey: "cosign.pub"})
+	assert.NoError(t, err)
+	pc := NewPulledChart(c, "0.1.0", dir, false)
+
+	assert.ErrorContains(t, pc.CheckVerification(), "has not been verified")
+
+	err = writeVerificationMarker(dir, chartVerification{Method: verificationMethodProvenance})
+	assert.NoError(t, err)
+	assert.ErrorContains(t, pc.CheckVerification(), "has been verified via provenance while cosign is required")
+
+	err = os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: test\n"), 0o600)
+	assert.NoError(t, err)
+	contentDigest, err := hashChartDir(dir)
+	assert.NoError(t, err)
+
+	err = writeVerificationMarker(dir, chartVerification{Method: verificationMethodCosign, Digest: "sha256:1234"})
+	assert.NoError(t, err)
+	assert.ErrorContains(t, pc.CheckVerification(), "has been modified after it got verified")
+
+	err = writeVerificationMarker(dir, chartVerification{Method: verificationMethodCosign, Digest: "sha256:1234", ContentDigest: contentDigest})
+	assert.NoError(t, err)
+	assert.NoError(t, pc.CheckVerification())
+
+	err = os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: test2\n"), 0o600)
+	assert.NoError(t, err)
+	assert.ErrorContains(t, pc.CheckVerification(), "has been modified after it got verified")
+
+	err = os.WriteFile(filepath.Join(dir, "Chart.yaml"), []byte("name: test\n"), 0o600)
+	assert.NoError(t, err)
+	assert.NoError(t, pc.CheckVerification())
+	err = os.MkdirAll(filepath.Join(dir, "templates"), 0o700)
+	assert.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "templates", "cm.yaml"), []byte("kind: ConfigMap\n"), 0o600)
+	assert.NoError(t, err)
+	assert.ErrorContains(t, pc.CheckVerification(), "has been modified after it got verified")
+
+	c, err = NewChart("oci://example.com/charts/test", "", "", nil, "", nil, nil)
+	assert.NoError(t, err)
+	pc = NewPulledChart(c, "0.1.0", t.TempDir(), false)
+	assert.NoError(t, pc.CheckVerification())
+}
+
+func TestParseProvenanceHash(t *testing.T) {
+	out := "Signed by: Test <test@example.com>\nUsing Key With Fingerprint: 1234\nChart Hash Verified: sha256:abcd\n"
+	assert.Equal(t, "sha256:abcd", parseProvenanceHash(out))
+	assert.Equal(t, "", parseProvenanceHash("something else"))
+}
+
+func TestParsePulledOciDigest(t *testing.T) {
+	out := "Pulled: example.com/charts/test:0.1.0\nDigest: sha256:abcd\n"
+	assert.Equal(t, "sha256:abcd", parsePulledOciDigest(out))
+	assert.Equal(t, "", parsePulledOciDigest("something else"))
+}
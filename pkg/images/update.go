@@ -0,0 +1,68 @@
+package images
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"strings"
+)
+
+// ListTags lists all tags of the given image repository. The OCI auth provider is used to look up credentials
+// and TLS settings for the registry.
+func ListTags(ctx context.Context, ociAuthProvider auth_provider.OciAuthProvider, repo string) ([]string, error) {
+	var clientOpts []crane.Option
+	clientOpts = append(clientOpts, crane.WithContext(ctx))
+	if ociAuthProvider != nil {
+		auth, err := ociAuthProvider.FindAuthEntry(ctx, "oci://"+repo)
+		if err != nil {
+			return nil, err
+		}
+		authOpts, err := auth.BuildCraneOptions()
+		if err != nil {
+			return nil, err
+		}
+		clientOpts = append(clientOpts, authOpts...)
+	}
+
+	return crane.ListTags(repo, clientOpts...)
+}
+
+// SplitImageTag splits the given image into the repository and tag part. The returned repository is kept in the
+// same form as it was specified, meaning that no default registry is added. If the image has no tag, "latest" is
+// returned as tag.
+func SplitImageTag(image string) (string, string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", "", err
+	}
+	tag, ok := ref.(name.Tag)
+	if !ok {
+		return "", "", fmt.Errorf("image %s is pinned by digest and can not be updated", image)
+	}
+	repo := strings.TrimSuffix(image, ":"+tag.TagStr())
+	return repo, tag.TagStr(), nil
+}
+
+// FindLatestImage queries the registry of the fixed image's result image and returns the result image with the
+// tag replaced by the latest tag according to the fixed image's update policy.
+func FindLatestImage(ctx context.Context, ociAuthProvider auth_provider.OciAuthProvider, fi *types.UpdatableFixedImage) (string, error) {
+	if fi.UpdatePolicy == nil {
+		return "", fmt.Errorf("no update policy specified")
+	}
+	repo, _, err := SplitImageTag(fi.ResultImage)
+	if err != nil {
+		return "", err
+	}
+	tags, err := ListTags(ctx, ociAuthProvider, repo)
+	if err != nil {
+		return "", err
+	}
+	latest, err := SelectLatestTag(fi.UpdatePolicy, tags)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", repo, err)
+	}
+	return repo + ":" + latest, nil
+}
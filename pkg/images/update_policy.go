@@ -0,0 +1,142 @@
+package images
+
+import (
+	"fmt"
+	"github.com/Masterminds/semver/v3"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+type tagCandidate struct {
+	tag   string
+	value string
+}
+
+// SelectLatestTag selects the latest tag from the given list of tags according to the update policy.
+// If regex is set, only matching tags are considered. If the regex contains a capture group, the value of the
+// first group is used for ordering instead of the full tag. The remaining tags are then ordered by semver
+// (only versions satisfying the constraint are considered), numerically or alphabetically if neither semver nor
+// numeric is set.
+func SelectLatestTag(policy *types.ImageUpdatePolicy, tags []string) (string, error) {
+	candidates, err := filterTags(policy, tags)
+	if err != nil {
+		return "", err
+	}
+
+	var latest string
+	if policy.Semver != nil {
+		latest, err = selectSemver(*policy.Semver, candidates)
+	} else if policy.Numeric != nil {
+		latest, err = selectNumeric(*policy.Numeric, candidates)
+	} else {
+		latest = selectAlphabetical(candidates)
+	}
+	if err != nil {
+		return "", err
+	}
+	if latest == "" {
+		return "", fmt.Errorf("no tag found that matches the update policy")
+	}
+	return latest, nil
+}
+
+func filterTags(policy *types.ImageUpdatePolicy, tags []string) ([]tagCandidate, error) {
+	if policy.Regex == nil {
+		ret := make([]tagCandidate, 0, len(tags))
+		for _, t := range tags {
+			ret = append(ret, tagCandidate{tag: t, value: t})
+		}
+		return ret, nil
+	}
+
+	r, err := regexp.Compile(*policy.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex '%s': %w", *policy.Regex, err)
+	}
+
+	var ret []tagCandidate
+	for _, t := range tags {
+		m := r.FindStringSubmatch(t)
+		if m == nil {
+			continue
+		}
+		v := t
+		if len(m) > 1 {
+			v = m[1]
+		}
+		ret = append(ret, tagCandidate{tag: t, value: v})
+	}
+	return ret, nil
+}
+
+func selectSemver(constraints string, candidates []tagCandidate) (string, error) {
+	c, err := semver.NewConstraint(constraints)
+	if err != nil {
+		return "", fmt.Errorf("invalid semver constraints '%s': %w", constraints, err)
+	}
+
+	var latest *semver.Version
+	var latestTag string
+	for _, x := range candidates {
+		v, err := semver.NewVersion(x.value)
+		if err != nil {
+			continue
+		}
+		if !c.Check(v) {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) || (v.Equal(latest) && x.tag > latestTag) {
+			latest = v
+			latestTag = x.tag
+		}
+	}
+	return latestTag, nil
+}
+
+func selectNumeric(order string, candidates []tagCandidate) (string, error) {
+	if order != "asc" && order != "desc" {
+		return "", fmt.Errorf("invalid numeric order '%s', must be 'asc' or 'desc'", order)
+	}
+
+	type numericCandidate struct {
+		tag   string
+		value float64
+	}
+	var l []numericCandidate
+	for _, x := range candidates {
+		v, err := strconv.ParseFloat(x.value, 64)
+		if err != nil {
+			continue
+		}
+		l = append(l, numericCandidate{tag: x.tag, value: v})
+	}
+	if len(l) == 0 {
+		return "", nil
+	}
+
+	sort.SliceStable(l, func(i, j int) bool {
+		if l[i].value == l[j].value {
+			return l[i].tag < l[j].tag
+		}
+		return l[i].value < l[j].value
+	})
+	if order == "asc" {
+		return l[len(l)-1].tag, nil
+	}
+	return l[0].tag, nil
+}
+
+func selectAlphabetical(candidates []tagCandidate) string {
+	var latest *tagCandidate
+	for i, x := range candidates {
+		if latest == nil || x.value > latest.value || (x.value == latest.value && x.tag > latest.tag) {
+			latest = &candidates[i]
+		}
+	}
+	if latest == nil {
+		return ""
+	}
+	return latest.tag
+}
@@ -0,0 +1,72 @@
+package images
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSelectLatestTag(t *testing.T) {
+	tags := []string{"latest", "1.0.0", "1.1.0", "1.2.0-rc.1", "2.0.0", "v2.1.0", "main-10", "main-9", "main-100", "dev-200"}
+
+	tests := []struct {
+		name     string
+		policy   types.ImageUpdatePolicy
+		expected string
+		err      string
+	}{
+		{name: "semver-any", policy: types.ImageUpdatePolicy{Semver: utils.StrPtr(">=0.0.0")}, expected: "v2.1.0"},
+		{name: "semver-range", policy: types.ImageUpdatePolicy{Semver: utils.StrPtr("~1")}, expected: "1.1.0"},
+		{name: "semver-prerelease", policy: types.ImageUpdatePolicy{Semver: utils.StrPtr(">=1.2.0-0 <1.3.0-0")}, expected: "1.2.0-rc.1"},
+		{name: "semver-regex", policy: types.ImageUpdatePolicy{Semver: utils.StrPtr(">=0.0.0"), Regex: utils.StrPtr(`^\d+\.\d+\.\d+$`)}, expected: "2.0.0"},
+		{name: "semver-none", policy: types.ImageUpdatePolicy{Semver: utils.StrPtr(">=3.0.0")}, err: "no tag found that matches the update policy"},
+		{name: "semver-invalid", policy: types.ImageUpdatePolicy{Semver: utils.StrPtr("abc")}, err: "invalid semver constraints 'abc'"},
+		{name: "numeric-asc", policy: types.ImageUpdatePolicy{Regex: utils.StrPtr(`^main-(\d+)$`), Numeric: utils.StrPtr("asc")}, expected: "main-100"},
+		{name: "numeric-desc", policy: types.ImageUpdatePolicy{Regex: utils.StrPtr(`^main-(\d+)$`), Numeric: utils.StrPtr("desc")}, expected: "main-9"},
+		{name: "numeric-invalid", policy: types.ImageUpdatePolicy{Numeric: utils.StrPtr("x")}, err: "invalid numeric order 'x'"},
+		{name: "regex-alphabetical", policy: types.ImageUpdatePolicy{Regex: utils.StrPtr(`^main-`)}, expected: "main-9"},
+		{name: "regex-group", policy: types.ImageUpdatePolicy{Regex: utils.StrPtr(`^[a-z]+-(\d+)$`)}, expected: "main-9"},
+		{name: "regex-invalid", policy: types.ImageUpdatePolicy{Regex: utils.StrPtr(`(`)}, err: "invalid regex '('"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			latest, err := SelectLatestTag(&tc.policy, tags)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, latest)
+		})
+	}
+}
+
+func TestSplitImageTag(t *testing.T) {
+	tests := []struct {
+		image string
+		repo  string
+		tag   string
+		err   bool
+	}{
+		{image: "nginx", repo: "nginx", tag: "latest"},
+		{image: "nginx:1.2.3", repo: "nginx", tag: "1.2.3"},
+		{image: "localhost:5000/my/image:v1", repo: "localhost:5000/my/image", tag: "v1"},
+		{image: "localhost:5000/my/image", repo: "localhost:5000/my/image", tag: "latest"},
+		{image: "nginx@sha256:0000000000000000000000000000000000000000000000000000000000000000", err: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.image, func(t *testing.T) {
+			repo, tag, err := SplitImageTag(tc.image)
+			if tc.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.repo, repo)
+			assert.Equal(t, tc.tag, tag)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package images
+
+import (
+	"context"
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/kluctl/kluctl/v2/pkg/oci/auth_provider"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func startTestRegistry(t *testing.T, repo string, tags ...string) string {
+	s := httptest.NewServer(registry.New())
+	t.Cleanup(s.Close)
+
+	u, err := url.Parse(s.URL)
+	assert.NoError(t, err)
+
+	img, err := random.Image(16, 1)
+	assert.NoError(t, err)
+	for _, tag := range tags {
+		err = crane.Push(img, u.Host+"/"+repo+":"+tag)
+		assert.NoError(t, err)
+	}
+	return u.Host
+}
+
+func TestFindLatestImage(t *testing.T) {
+	host := startTestRegistry(t, "my/image", "1.0.0", "1.1.0", "2.0.0")
+
+	ociAuthProvider := auth_provider.NewDefaultAuthProviders("KLUCTL_REGISTRY")
+
+	fi := &types.UpdatableFixedImage{
+		FixedImage: types.FixedImage{
+			Image:       utils.StrPtr("my/image"),
+			ResultImage: host + "/my/image:1.0.0",
+		},
+		UpdatePolicy: &types.ImageUpdatePolicy{
+			Semver: utils.StrPtr("<2.0.0"),
+		},
+	}
+
+	latest, err := FindLatestImage(context.Background(), ociAuthProvider, fi)
+	assert.NoError(t, err)
+	assert.Equal(t, host+"/my/image:1.1.0", latest)
+
+	fi.UpdatePolicy.Semver = utils.StrPtr(">=3.0.0")
+	_, err = FindLatestImage(context.Background(), ociAuthProvider, fi)
+	assert.ErrorContains(t, err, "no tag found that matches the update policy")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
 	"net/http"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -36,6 +37,23 @@ func (p *parallelClientEntry) HandleWarningHeader(code int, agent string, text s
 	})
 }
 
+// WithRateLimit returns a copy of the given config with a rate limiter that is shared between all clients created from
+// it. Without this, each client of the K8sCluster client pool would use its own QPS and burst limits. If qps is <= 0,
+// the config is returned unmodified. If burst is <= 0, it defaults to twice the qps.
+func WithRateLimit(config *rest.Config, qps int, burst int) *rest.Config {
+	if qps <= 0 {
+		return config
+	}
+	if burst <= 0 {
+		burst = qps * 2
+	}
+	config = rest.CopyConfig(config)
+	config.QPS = float32(qps)
+	config.Burst = burst
+	config.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(config.QPS, config.Burst)
+	return config
+}
+
 func newK8sClients(k *K8sCluster, count int) (*k8sClients, error) {
 	kc := &k8sClients{
 		k:          k,
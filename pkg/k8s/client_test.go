@@ -0,0 +1,94 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"net/http"
+	"net/http/httptest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	config := &rest.Config{Host: "https://example.com"}
+
+	assert.Same(t, config, WithRateLimit(config, 0, 10))
+
+	c := WithRateLimit(config, 5, 0)
+	assert.NotSame(t, config, c)
+	assert.Nil(t, config.RateLimiter)
+	assert.Equal(t, float32(5), c.QPS)
+	assert.Equal(t, 10, c.Burst)
+	assert.NotNil(t, c.RateLimiter)
+	assert.Equal(t, float32(5), c.RateLimiter.QPS())
+
+	c = WithRateLimit(config, 5, 7)
+	assert.Equal(t, 7, c.Burst)
+}
+
+func TestWithRateLimitSharedBetweenClients(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		cm := corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: "default"},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(&cm)
+	}))
+	defer server.Close()
+
+	mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+
+	// a burst of 4 without any meaningful refill during the test
+	config := WithRateLimit(&rest.Config{Host: server.URL}, 1, 4)
+	k := &K8sCluster{
+		ctx:    context.Background(),
+		config: config,
+		mapper: mapper,
+	}
+
+	kc, err := newK8sClients(k, 4)
+	assert.NoError(t, err)
+	defer kc.close()
+
+	// take all clients out of the pool so that every request goes through a different client
+	var entries []*parallelClientEntry
+	for i := 0; i < 4; i++ {
+		p := <-kc.clientPool
+		assert.Same(t, config.RateLimiter, p.config.RateLimiter)
+		entries = append(entries, p)
+	}
+	defer func() {
+		for _, p := range entries {
+			kc.clientPool <- p
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for _, p := range entries {
+		p := p
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var cm corev1.ConfigMap
+			err := p.client.Get(context.Background(), client.ObjectKey{Name: "cm", Namespace: "default"}, &cm)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(4), requests.Load())
+
+	// all clients took their token from the same bucket, so it must be exhausted now
+	assert.False(t, config.RateLimiter.TryAccept())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	corev1api "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -427,6 +428,36 @@ func (k *K8sCluster) ProxyGet(scheme, namespace, name, port, path string, params
 	return ret.Stream(k.ctx)
 }
 
+// GetPodLogs returns the logs of all containers of the given Pod
+func (k *K8sCluster) GetPodLogs(namespace string, name string) (string, error) {
+	var ret strings.Builder
+	_, err := k.clients.withClientFromPool(k.ctx, func(p *parallelClientEntry) error {
+		c, err := corev1.NewForConfigAndClient(p.config, p.httpClient)
+		if err != nil {
+			return err
+		}
+		pod, err := c.Pods(namespace).Get(k.ctx, name, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		for _, container := range pod.Spec.Containers {
+			b, err := c.Pods(namespace).GetLogs(name, &corev1api.PodLogOptions{Container: container.Name}).DoRaw(k.ctx)
+			if err != nil {
+				return err
+			}
+			if len(pod.Spec.Containers) > 1 {
+				ret.WriteString(fmt.Sprintf("==> container %s <==\n", container.Name))
+			}
+			ret.Write(b)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return ret.String(), nil
+}
+
 func (k *K8sCluster) IsNamespaced(gvk schema.GroupVersionKind) *bool {
 	var obj unstructured.Unstructured
 	obj.SetGroupVersionKind(gvk)
@@ -4,14 +4,21 @@ import (
 	"context"
 	"github.com/kluctl/go-jinja2"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"time"
 )
 
-func LoadKluctlProject(ctx context.Context, args LoadKluctlProjectArgs, j2 *jinja2.Jinja2) (*LoadedKluctlProject, error) {
+func LoadKluctlProject(ctx context.Context, args LoadKluctlProjectArgs, j2 *jinja2.Jinja2) (p *LoadedKluctlProject, err error) {
 	status.Trace(ctx, "enter LoadKluctlProject")
 	defer status.Trace(ctx, "leave LoadKluctlProject")
 
-	p := &LoadedKluctlProject{
+	ctx, span := tracing.Start(ctx, "load-project", attribute.String("kluctl.project_dir", args.ProjectDir))
+	defer func() {
+		tracing.End(span, err)
+	}()
+
+	p = &LoadedKluctlProject{
 		LoadArgs: args,
 		LoadTime: time.Now(),
 		J2:       j2,
@@ -19,7 +26,7 @@ func LoadKluctlProject(ctx context.Context, args LoadKluctlProjectArgs, j2 *jinj
 		OciRP:    args.OciRP,
 	}
 
-	err := p.loadKluctlProject(ctx)
+	err = p.loadKluctlProject(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -42,6 +42,9 @@ type TargetContextParams struct {
 	HelmAuthProvider   auth.HelmAuthProvider
 	OciAuthProvider    auth_provider.OciAuthProvider
 	RenderOutputDir    string
+
+	HelmRequireVerification    bool
+	HelmAllowExecPostRenderers bool
 }
 
 func NewTargetContext(ctx context.Context, p *kluctl_project.LoadedKluctlProject, contextName string, k *k8s.K8sCluster, params TargetContextParams) (*TargetContext, error) {
@@ -72,7 +75,7 @@ func NewTargetContext(ctx context.Context, p *kluctl_project.LoadedKluctlProject
 		target.Name = params.TargetNameOverride
 	}
 
-	params.Images.PrependFixedImages(target.Images)
+	params.Images.PrependFixedImages(types.ToFixedImages(target.Images))
 
 	target.Context = &contextName
 
@@ -113,6 +116,8 @@ func NewTargetContext(ctx context.Context, p *kluctl_project.LoadedKluctlProject
 		VarsLoader:                        varsLoader,
 		HelmAuthProvider:                  params.HelmAuthProvider,
 		OciAuthProvider:                   params.OciAuthProvider,
+		HelmRequireVerification:           params.HelmRequireVerification || (p.Config.Helm != nil && p.Config.Helm.RequireVerification),
+		HelmAllowExecPostRenderers:        params.HelmAllowExecPostRenderers,
 		Discriminator:                     target.Discriminator,
 		RenderDir:                         params.RenderOutputDir,
 		SealedSecretsDir:                  p.SealedSecretsDir,
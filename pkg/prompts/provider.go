@@ -1,12 +1,15 @@
 package prompts
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"github.com/kluctl/kluctl/v2/pkg/status"
 	"github.com/kluctl/kluctl/v2/pkg/utils/term"
+	"io"
 	"os"
 	"strings"
+	"sync"
 	"syscall"
 )
 
@@ -21,6 +24,16 @@ type SimplePromptProvider struct {
 	Out *os.File
 }
 
+// EventPromptProvider reports prompts as structured status events and reads the answers line by line from In. It is
+// meant to be used together with status handlers that emit machine-readable events, so that prompts don't end up as
+// plain text inside the event stream.
+type EventPromptProvider struct {
+	In io.Reader
+
+	mutex sync.Mutex
+	r     *bufio.Reader
+}
+
 func (pp *StatusAndStdinPromptProvider) Prompt(ctx context.Context, password bool, message string) (string, error) {
 	s := status.StartWithOptions(ctx,
 		status.WithLevel(status.LevelPrompt),
@@ -64,6 +77,24 @@ func (pp *SimplePromptProvider) Prompt(ctx context.Context, password bool, messa
 	}
 }
 
+func (pp *EventPromptProvider) Prompt(ctx context.Context, password bool, message string) (string, error) {
+	pp.mutex.Lock()
+	defer pp.mutex.Unlock()
+
+	if !status.ReportPrompt(ctx, message, password) {
+		return "", fmt.Errorf("status handler does not support prompts")
+	}
+
+	if pp.r == nil {
+		pp.r = bufio.NewReader(pp.In)
+	}
+	line, err := pp.r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
 type contextKey struct{}
 
 func NewContext(ctx context.Context, provider PromptProvider) context.Context {
@@ -35,8 +35,14 @@ type GitRepoCache struct {
 
 	cleanupDirs      []string
 	cleanupDirsMutex sync.Mutex
+
+	accessFunc CacheAccessFunc
 }
 
+// CacheAccessFunc is called whenever a repository is requested from a repo cache. hit is true if an already
+// cloned/pulled repository could be reused.
+type CacheAccessFunc func(hit bool)
+
 type GitCacheEntry struct {
 	rp         *GitRepoCache
 	url        types.GitUrl
@@ -71,6 +77,11 @@ func NewGitRepoCache(ctx context.Context, sshPool *ssh_pool.SshPool, authProvide
 	}
 }
 
+// SetCacheAccessFunc sets a function that is called on every cache access, e.g. to export cache hit ratios.
+func (rp *GitRepoCache) SetCacheAccessFunc(f CacheAccessFunc) {
+	rp.accessFunc = f
+}
+
 func (rp *GitRepoCache) Clear() {
 	rp.cleanupDirsMutex.Lock()
 	defer rp.cleanupDirsMutex.Unlock()
@@ -149,10 +160,14 @@ func (e *GitCacheEntry) Update() error {
 	}
 	defer e.mr.Unlock()
 
+	hit := true
 	if !e.mr.HasUpdated() {
 		if time.Now().Sub(e.mr.LastUpdateTime()) <= e.rp.updateInterval {
 			e.mr.SetUpdated(true)
 		} else {
+			// an already existing mirror only needs to be fetched instead of cloned
+			hit = e.mr.HasMirror()
+
 			url := e.mr.Url()
 			s := status.Startf(e.rp.ctx, "Updating git cache for %s", url.String())
 			defer s.Failed()
@@ -164,6 +179,9 @@ func (e *GitCacheEntry) Update() error {
 			s.Success()
 		}
 	}
+	if e.rp.accessFunc != nil {
+		e.rp.accessFunc(hit)
+	}
 
 	e.refs, err = e.mr.RemoteRefHashesMap()
 	if err != nil {
@@ -35,6 +35,8 @@ type OciRepoCache struct {
 
 	cleanupDirs      []string
 	cleanupDirsMutex sync.Mutex
+
+	accessFunc CacheAccessFunc
 }
 
 type OciCacheEntry struct {
@@ -58,6 +60,11 @@ func NewOciRepoCache(ctx context.Context, ociAuthProvider auth_provider.OciAuthP
 	}
 }
 
+// SetCacheAccessFunc sets a function that is called on every cache access, e.g. to export cache hit ratios.
+func (rp *OciRepoCache) SetCacheAccessFunc(f CacheAccessFunc) {
+	rp.accessFunc = f
+}
+
 func (rp *OciRepoCache) Clear() {
 	rp.cleanupDirsMutex.Lock()
 	defer rp.cleanupDirsMutex.Unlock()
@@ -164,6 +171,7 @@ func (e *OciCacheEntry) GetExtractedDir(ref *types.OciRef) (string, git.Checkout
 
 	ed, ok := e.pulledDirs[*ref]
 	if ok {
+		e.reportAccess(true)
 		return ed.dir, ed.info, nil
 	}
 
@@ -182,6 +190,7 @@ func (e *OciCacheEntry) GetExtractedDir(ref *types.OciRef) (string, git.Checkout
 
 	image := strings.TrimPrefix(e.url.String(), "oci://") + ":" + ref.String()
 
+	e.reportAccess(false)
 	md, err := e.ociClient.Pull(e.rp.ctx, image, ociDir)
 	if err != nil {
 		return "", git.CheckoutInfo{}, err
@@ -205,3 +214,9 @@ func (e *OciCacheEntry) GetExtractedDir(ref *types.OciRef) (string, git.Checkout
 	e.pulledDirs[*ref] = cd
 	return cd.dir, cd.info, nil
 }
+
+func (e *OciCacheEntry) reportAccess(hit bool) {
+	if e.rp.accessFunc != nil {
+		e.rp.accessFunc(hit)
+	}
+}
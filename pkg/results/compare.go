@@ -0,0 +1,194 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/diff"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"sort"
+)
+
+// CompareCommandResults compares the rendered objects, vars, images and git info of two command results. Both command
+// results must be loaded in non-reduced form, as otherwise the rendered objects are not fully available.
+func CompareCommandResults(oldCr *result.CommandResult, newCr *result.CommandResult) (*result.CommandResultComparison, error) {
+	ret := &result.CommandResultComparison{
+		OldResultId: oldCr.Id,
+		NewResultId: newCr.Id,
+		OldGitInfo:  oldCr.GitInfo,
+		NewGitInfo:  newCr.GitInfo,
+	}
+
+	varsChanges, err := diff.Diff(buildVarsObject(oldCr), buildVarsObject(newCr))
+	if err != nil {
+		return nil, err
+	}
+	ret.VarsChanges = varsChanges
+
+	ret.ImageChanges = compareImages(oldCr.SeenImages, newCr.SeenImages)
+
+	oldObjects := map[k8s.ObjectRef]*uo.UnstructuredObject{}
+	for _, o := range oldCr.Objects {
+		if o.Rendered != nil {
+			oldObjects[o.Ref] = o.Rendered
+		}
+	}
+	newObjects := map[k8s.ObjectRef]*uo.UnstructuredObject{}
+	for _, o := range newCr.Objects {
+		if o.Rendered != nil {
+			newObjects[o.Ref] = o.Rendered
+		}
+	}
+
+	for ref, newObject := range newObjects {
+		oldObject, ok := oldObjects[ref]
+		if !ok {
+			ret.AddedObjects = append(ret.AddedObjects, ref)
+			continue
+		}
+		changes, err := diff.Diff(oldObject, newObject)
+		if err != nil {
+			return nil, err
+		}
+		if len(changes) != 0 {
+			ret.ChangedObjects = append(ret.ChangedObjects, result.ChangedObject{
+				Ref:     ref,
+				Changes: changes,
+			})
+		}
+	}
+	for ref := range oldObjects {
+		if _, ok := newObjects[ref]; !ok {
+			ret.RemovedObjects = append(ret.RemovedObjects, ref)
+		}
+	}
+
+	sortRefs := func(refs []k8s.ObjectRef) {
+		sort.Slice(refs, func(i, j int) bool {
+			return refs[i].String() < refs[j].String()
+		})
+	}
+	sortRefs(ret.AddedObjects)
+	sortRefs(ret.RemovedObjects)
+	sort.Slice(ret.ChangedObjects, func(i, j int) bool {
+		return ret.ChangedObjects[i].Ref.String() < ret.ChangedObjects[j].Ref.String()
+	})
+
+	return ret, nil
+}
+
+// buildVarsObject builds an object that mirrors the deployment project structure, but only contains the args and the
+// rendered vars. This allows to use the same diff logic as used for objects.
+func buildVarsObject(cr *result.CommandResult) *uo.UnstructuredObject {
+	ret := uo.New()
+	if cr.Command.Args != nil {
+		_ = ret.SetNestedField(cr.Command.Args.Object, "args")
+	}
+	if cr.Deployment != nil {
+		_ = ret.SetNestedField(buildDeploymentVars(cr.Deployment), "deployment")
+	}
+	return ret
+}
+
+func buildDeploymentVars(d *types.DeploymentProjectConfig) map[string]any {
+	buildVarsList := func(vars []*types.VarsSource) []any {
+		ret := make([]any, 0, len(vars))
+		for _, v := range vars {
+			if v.RenderedSensitive || v.RenderedVars == nil {
+				// sensitive vars are never compared, as this would leak them into the comparison
+				ret = append(ret, map[string]any{})
+			} else {
+				ret = append(ret, v.RenderedVars.Object)
+			}
+		}
+		return ret
+	}
+
+	deployments := make([]any, 0, len(d.Deployments))
+	for _, di := range d.Deployments {
+		m := map[string]any{
+			"vars": buildVarsList(di.Vars),
+		}
+		if di.RenderedInclude != nil {
+			m["renderedInclude"] = buildDeploymentVars(di.RenderedInclude)
+		}
+		deployments = append(deployments, m)
+	}
+
+	return map[string]any{
+		"vars":        buildVarsList(d.Vars),
+		"deployments": deployments,
+	}
+}
+
+func compareImages(oldImages []types.FixedImage, newImages []types.FixedImage) []result.ImageChange {
+	type imageKey struct {
+		image     string
+		object    k8s.ObjectRef
+		container string
+	}
+
+	buildKey := func(fi *types.FixedImage) imageKey {
+		var k imageKey
+		if fi.Image != nil {
+			k.image = *fi.Image
+		} else if fi.ImageRegex != nil {
+			k.image = *fi.ImageRegex
+		}
+		if fi.Object != nil {
+			k.object = *fi.Object
+		}
+		if fi.Container != nil {
+			k.container = *fi.Container
+		}
+		return k
+	}
+
+	changes := map[imageKey]*result.ImageChange{}
+	getChange := func(k imageKey) *result.ImageChange {
+		c, ok := changes[k]
+		if !ok {
+			c = &result.ImageChange{
+				Image:     k.image,
+				Container: k.container,
+			}
+			if k.object != (k8s.ObjectRef{}) {
+				object := k.object
+				c.Object = &object
+			}
+			changes[k] = c
+		}
+		return c
+	}
+
+	for _, fi := range oldImages {
+		getChange(buildKey(&fi)).OldResultImage = fi.ResultImage
+	}
+	for _, fi := range newImages {
+		getChange(buildKey(&fi)).NewResultImage = fi.ResultImage
+	}
+
+	var ret []result.ImageChange
+	for _, c := range changes {
+		if c.OldResultImage != c.NewResultImage {
+			ret = append(ret, *c)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Image != ret[j].Image {
+			return ret[i].Image < ret[j].Image
+		}
+		var oi, oj string
+		if ret[i].Object != nil {
+			oi = ret[i].Object.String()
+		}
+		if ret[j].Object != nil {
+			oj = ret[j].Object.String()
+		}
+		if oi != oj {
+			return oi < oj
+		}
+		return ret[i].Container < ret[j].Container
+	})
+	return ret
+}
@@ -0,0 +1,107 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func buildTestConfigMap(name string, data map[string]any) result.ResultObject {
+	o := uo.FromMap(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": "default",
+		},
+		"data": data,
+	})
+	return result.ResultObject{
+		BaseObject: result.BaseObject{Ref: o.GetK8sRef()},
+		Rendered:   o,
+	}
+}
+
+func TestCompareCommandResults(t *testing.T) {
+	image := "nginx"
+	secretVars := &types.VarsSource{
+		RenderedSensitive: true,
+		RenderedVars:      uo.FromMap(map[string]any{"password": "secret1"}),
+	}
+
+	oldCr := &result.CommandResult{
+		Id:      "old",
+		GitInfo: result.GitInfo{Commit: "c1"},
+		Command: result.CommandInfo{
+			Args: uo.FromMap(map[string]any{"arg": "v1"}),
+		},
+		Deployment: &types.DeploymentProjectConfig{
+			Vars: []*types.VarsSource{
+				{RenderedVars: uo.FromMap(map[string]any{"replicas": 1})},
+				secretVars,
+			},
+		},
+		SeenImages: []types.FixedImage{
+			{Image: &image, ResultImage: "nginx:1.0"},
+		},
+		Objects: []result.ResultObject{
+			buildTestConfigMap("cm1", map[string]any{"key": "v1"}),
+			buildTestConfigMap("cm2", map[string]any{"key": "v1"}),
+			buildTestConfigMap("cm3", map[string]any{"key": "v1"}),
+		},
+	}
+
+	secretVars2 := secretVars.DeepCopy()
+	secretVars2.RenderedVars = uo.FromMap(map[string]any{"password": "secret2"})
+	newCr := &result.CommandResult{
+		Id:      "new",
+		GitInfo: result.GitInfo{Commit: "c2"},
+		Command: result.CommandInfo{
+			Args: uo.FromMap(map[string]any{"arg": "v2"}),
+		},
+		Deployment: &types.DeploymentProjectConfig{
+			Vars: []*types.VarsSource{
+				{RenderedVars: uo.FromMap(map[string]any{"replicas": 2})},
+				secretVars2,
+			},
+		},
+		SeenImages: []types.FixedImage{
+			{Image: &image, ResultImage: "nginx:1.1"},
+		},
+		Objects: []result.ResultObject{
+			buildTestConfigMap("cm1", map[string]any{"key": "v1"}),
+			buildTestConfigMap("cm2", map[string]any{"key": "v2"}),
+			buildTestConfigMap("cm4", map[string]any{"key": "v1"}),
+		},
+	}
+
+	c, err := CompareCommandResults(oldCr, newCr)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "old", c.OldResultId)
+	assert.Equal(t, "new", c.NewResultId)
+	assert.Equal(t, "c1", c.OldGitInfo.Commit)
+	assert.Equal(t, "c2", c.NewGitInfo.Commit)
+
+	var varsPaths []string
+	for _, x := range c.VarsChanges {
+		varsPaths = append(varsPaths, x.JsonPath)
+		assert.NotContains(t, x.UnifiedDiff, "secret")
+	}
+	assert.Equal(t, []string{"args.arg", "deployment.vars[0].replicas"}, varsPaths)
+
+	assert.Equal(t, []result.ImageChange{
+		{Image: "nginx", OldResultImage: "nginx:1.0", NewResultImage: "nginx:1.1"},
+	}, c.ImageChanges)
+
+	assert.Equal(t, []k8s.ObjectRef{newCr.Objects[2].Ref}, c.AddedObjects)
+	assert.Equal(t, []k8s.ObjectRef{oldCr.Objects[2].Ref}, c.RemovedObjects)
+	if assert.Len(t, c.ChangedObjects, 1) {
+		assert.Equal(t, newCr.Objects[1].Ref, c.ChangedObjects[0].Ref)
+		assert.Len(t, c.ChangedObjects[0].Changes, 1)
+		assert.Equal(t, "data.key", c.ChangedObjects[0].Changes[0].JsonPath)
+	}
+}
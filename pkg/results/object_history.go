@@ -0,0 +1,135 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
+	"reflect"
+)
+
+// ObjectHistoryBuilder builds the history of a single object across multiple command results. Command results must
+// be added from oldest to newest and must be loaded in non-reduced form, as the rendered objects are required to
+// distinguish between changes and drift.
+type ObjectHistoryBuilder struct {
+	ref k8s.ObjectRef
+
+	lastRendered map[result.TargetKey]*uo.UnstructuredObject
+	lastOrphan   map[result.TargetKey]bool
+
+	entries []result.ObjectHistoryEntry
+}
+
+// NewObjectHistoryBuilder creates a builder for the history of the object referenced by ref. The version of ref is
+// ignored when matching objects, as it might change between command results. The group is ignored if empty.
+func NewObjectHistoryBuilder(ref k8s.ObjectRef) *ObjectHistoryBuilder {
+	return &ObjectHistoryBuilder{
+		ref:          ref,
+		lastRendered: map[result.TargetKey]*uo.UnstructuredObject{},
+		lastOrphan:   map[result.TargetKey]bool{},
+	}
+}
+
+func (b *ObjectHistoryBuilder) matches(ref k8s.ObjectRef) bool {
+	if b.ref.Group != "" && b.ref.Group != ref.Group {
+		return false
+	}
+	return b.ref.Kind == ref.Kind && b.ref.Name == ref.Name && b.ref.Namespace == ref.Namespace
+}
+
+// Add processes the next command result. Dry-run results (e.g. from diff commands) are ignored, as they did not
+// modify the object.
+func (b *ObjectHistoryBuilder) Add(cr *result.CommandResult) {
+	if cr.Command.DryRun {
+		return
+	}
+
+	var o *result.ResultObject
+	for i := range cr.Objects {
+		if b.matches(cr.Objects[i].Ref) {
+			o = &cr.Objects[i]
+			break
+		}
+	}
+	if o == nil {
+		return
+	}
+
+	tk := cr.TargetKey
+	event := ""
+	if o.Deleted {
+		event = result.ObjectHistoryEventDeleted
+		delete(b.lastRendered, tk)
+		b.lastOrphan[tk] = false
+	} else if o.Orphan {
+		if !b.lastOrphan[tk] {
+			event = result.ObjectHistoryEventOrphaned
+		}
+		b.lastOrphan[tk] = true
+	} else {
+		if o.New {
+			event = result.ObjectHistoryEventCreated
+		} else if len(o.Changes) != 0 {
+			lastRendered := b.lastRendered[tk]
+			if lastRendered != nil && o.Rendered != nil && reflect.DeepEqual(lastRendered.Object, o.Rendered.Object) {
+				event = result.ObjectHistoryEventDrifted
+			} else {
+				event = result.ObjectHistoryEventChanged
+			}
+		}
+		if o.Rendered != nil {
+			b.lastRendered[tk] = o.Rendered
+		}
+		b.lastOrphan[tk] = false
+	}
+
+	if event == "" {
+		return
+	}
+
+	b.entries = append(b.entries, result.ObjectHistoryEntry{
+		ResultId:         cr.Id,
+		ProjectKey:       cr.ProjectKey,
+		TargetKey:        cr.TargetKey,
+		KluctlDeployment: cr.KluctlDeployment,
+		Command:          cr.Command.Command,
+		Initiator:        cr.Command.Initiator,
+		StartTime:        cr.Command.StartTime,
+		GitInfo:          cr.GitInfo,
+		Ref:              o.Ref,
+		Event:            event,
+		Changes:          o.Changes,
+	})
+}
+
+// Build returns the history entries, sorted from newest to oldest.
+func (b *ObjectHistoryBuilder) Build() []result.ObjectHistoryEntry {
+	ret := make([]result.ObjectHistoryEntry, len(b.entries))
+	for i, e := range b.entries {
+		ret[len(ret)-1-i] = e
+	}
+	return ret
+}
+
+// LoadObjectHistory loads the command results referenced by the given summaries and builds the history of the object
+// referenced by ref. The summaries must be sorted from newest to oldest, as returned by ListCommandResultSummaries.
+func LoadObjectHistory(store ResultStore, summaries []result.CommandResultSummary, ref k8s.ObjectRef) ([]result.ObjectHistoryEntry, error) {
+	b := NewObjectHistoryBuilder(ref)
+	for i := len(summaries) - 1; i >= 0; i-- {
+		if summaries[i].Command.DryRun {
+			continue
+		}
+		cr, err := store.GetCommandResult(GetCommandResultOptions{
+			Id:      summaries[i].Id,
+			Reduced: false,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if cr == nil {
+			// deleted in the meantime
+			continue
+		}
+		b.Add(cr)
+	}
+	return b.Build(), nil
+}
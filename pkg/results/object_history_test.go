@@ -0,0 +1,84 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestObjectHistoryBuilder(t *testing.T) {
+	changes := []result.Change{{Type: "update", JsonPath: "data.k"}}
+
+	withFlags := func(o result.ResultObject, f func(o *result.ResultObject)) result.ResultObject {
+		f(&o)
+		return o
+	}
+	buildCr := func(id string, commit string, dryRun bool, objects ...result.ResultObject) *result.CommandResult {
+		return &result.CommandResult{
+			Id:      id,
+			GitInfo: result.GitInfo{Commit: commit},
+			Command: result.CommandInfo{Command: "deploy", DryRun: dryRun},
+			Objects: objects,
+		}
+	}
+
+	other := buildTestConfigMap("other", map[string]any{"k": "v1"})
+
+	crs := []*result.CommandResult{
+		buildCr("r1", "c1", false, withFlags(buildTestConfigMap("cm", map[string]any{"k": "v1"}), func(o *result.ResultObject) {
+			o.New = true
+		}), other),
+		// nothing happened to cm
+		buildCr("r2", "c1", false, buildTestConfigMap("cm", map[string]any{"k": "v1"}), other),
+		// dry-runs are ignored
+		buildCr("r3", "c2", true, withFlags(buildTestConfigMap("cm", map[string]any{"k": "v2"}), func(o *result.ResultObject) {
+			o.Changes = changes
+		})),
+		buildCr("r4", "c2", false, withFlags(buildTestConfigMap("cm", map[string]any{"k": "v2"}), func(o *result.ResultObject) {
+			o.Changes = changes
+		})),
+		// same rendered object, but changes -> drift
+		buildCr("r5", "c2", false, withFlags(buildTestConfigMap("cm", map[string]any{"k": "v2"}), func(o *result.ResultObject) {
+			o.Changes = changes
+		})),
+		buildCr("r6", "c3", false, withFlags(buildTestConfigMap("cm", nil), func(o *result.ResultObject) {
+			o.Rendered = nil
+			o.Orphan = true
+		})),
+		// still orphan
+		buildCr("r7", "c3", false, withFlags(buildTestConfigMap("cm", nil), func(o *result.ResultObject) {
+			o.Rendered = nil
+			o.Orphan = true
+		})),
+		buildCr("r8", "c3", false, withFlags(buildTestConfigMap("cm", nil), func(o *result.ResultObject) {
+			o.Rendered = nil
+			o.Deleted = true
+		})),
+	}
+
+	b := NewObjectHistoryBuilder(k8s.ObjectRef{Kind: "ConfigMap", Name: "cm", Namespace: "default"})
+	for _, cr := range crs {
+		b.Add(cr)
+	}
+	entries := b.Build()
+
+	type entry struct {
+		id     string
+		commit string
+		event  string
+	}
+	var actual []entry
+	for _, e := range entries {
+		actual = append(actual, entry{e.ResultId, e.GitInfo.Commit, e.Event})
+	}
+	assert.Equal(t, []entry{
+		{"r8", "c3", result.ObjectHistoryEventDeleted},
+		{"r6", "c3", result.ObjectHistoryEventOrphaned},
+		{"r5", "c2", result.ObjectHistoryEventDrifted},
+		{"r4", "c2", result.ObjectHistoryEventChanged},
+		{"r1", "c1", result.ObjectHistoryEventCreated},
+	}, actual)
+	assert.Equal(t, changes, entries[3].Changes)
+	assert.Equal(t, "v1", entries[0].Ref.Version)
+}
@@ -3,6 +3,7 @@ package results
 import (
 	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
@@ -17,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	toolscache "k8s.io/client-go/tools/cache"
 	"path"
@@ -24,8 +26,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 type ResultStoreSecrets struct {
@@ -36,15 +40,14 @@ type ResultStoreSecrets struct {
 	validateResultsCache cache.Cache
 	clusterId            string
 
-	allowWrite               bool
-	writeNamespace           string
-	keepCommandResultsCount  int
-	keepValidateResultsCount int
+	allowWrite     bool
+	writeNamespace string
+	retention      RetentionOptions
 
 	mutex sync.Mutex
 }
 
-func NewResultStoreSecrets(ctx context.Context, config *rest.Config, client_ client.Client, allowWrite bool, writeNamespace string, keepCommandResultsCount int, keepValidateResultsCount int) (*ResultStoreSecrets, error) {
+func NewResultStoreSecrets(ctx context.Context, config *rest.Config, client_ client.Client, allowWrite bool, writeNamespace string, retention RetentionOptions) (*ResultStoreSecrets, error) {
 	clusterId, err := k8s.GetClusterId(ctx, client_)
 	if err != nil {
 		return nil, err
@@ -72,22 +75,26 @@ func NewResultStoreSecrets(ctx context.Context, config *rest.Config, client_ cli
 	c1.WaitForCacheSync(ctx)
 
 	s := &ResultStoreSecrets{
-		ctx:                      ctx,
-		client:                   client_,
-		allowWrite:               allowWrite,
-		writeNamespace:           writeNamespace,
-		cache:                    c1,
-		clusterId:                clusterId,
-		keepCommandResultsCount:  keepCommandResultsCount,
-		keepValidateResultsCount: keepValidateResultsCount,
+		ctx:            ctx,
+		client:         client_,
+		allowWrite:     allowWrite,
+		writeNamespace: writeNamespace,
+		cache:          c1,
+		clusterId:      clusterId,
+		retention:      retention,
 	}
 
 	return s, nil
 }
 
+const (
+	pinnedLabel    = "kluctl.io/result-pinned"
+	sizeAnnotation = "kluctl.io/result-size"
+)
+
 var invalidChars = regexp.MustCompile(`[^a-zA-Z0-9-]`)
 
-func (s *ResultStoreSecrets) buildName(prefix string, id string, projectKey result.ProjectKey) string {
+func buildResultName(prefix string, id string, projectKey result.ProjectKey) string {
 	name := ""
 
 	if projectKey.RepoKey.Path != "" {
@@ -179,7 +186,7 @@ func (s *ResultStoreSecrets) WriteCommandResult(cr *result.CommandResult) error
 			Kind:       "Secret",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      s.buildName("cr", cr.Id, cr.ProjectKey),
+			Name:      buildResultName("cr", cr.Id, cr.ProjectKey),
 			Namespace: s.writeNamespace,
 			Labels: map[string]string{
 				"kluctl.io/result":            "true",
@@ -187,6 +194,7 @@ func (s *ResultStoreSecrets) WriteCommandResult(cr *result.CommandResult) error
 			},
 			Annotations: map[string]string{
 				"kluctl.io/command-result-summary": summaryJson,
+				sizeAnnotation:                     fmt.Sprintf("%d", len(compressedCr)+len(compressedObjects)+len(summaryJson)),
 			},
 		},
 		Data: map[string][]byte{
@@ -210,7 +218,12 @@ func (s *ResultStoreSecrets) WriteCommandResult(cr *result.CommandResult) error
 		return err
 	}
 
-	err = s.cleanupOldCommandResults(cr.ProjectKey, cr.TargetKey)
+	deleted := map[string]bool{}
+	err = s.cleanupOldCommandResults(cr.ProjectKey, cr.TargetKey, deleted)
+	if err != nil {
+		return err
+	}
+	err = s.cleanupResultsSize(deleted)
 	if err != nil {
 		return err
 	}
@@ -238,6 +251,77 @@ func (s *ResultStoreSecrets) DeleteCommandResult(rsId string) error {
 	return nil
 }
 
+func (s *ResultStoreSecrets) DeleteValidateResult(vrId string) error {
+	if !s.allowWrite {
+		return fmt.Errorf("result store is read-only")
+	}
+	if vrId == "" {
+		return fmt.Errorf("empty vrId is not allowed")
+	}
+
+	var tmp corev1.Secret
+	err := s.client.DeleteAllOf(s.ctx, &tmp,
+		client.InNamespace(s.writeNamespace),
+		client.MatchingLabels{
+			"kluctl.io/validate-result-id": vrId,
+		})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *ResultStoreSecrets) SetCommandResultPinned(rsId string, pinned bool) error {
+	if !s.allowWrite {
+		return fmt.Errorf("result store is read-only")
+	}
+	return SetCommandResultPinnedSecret(s.ctx, s.client, rsId, pinned)
+}
+
+// SetCommandResultPinnedSecret pins or unpins the command result without requiring a writable ResultStore. Pinned
+// command results are never deleted by retention. It is used by components that only have read access to results but
+// still need to pin results, e.g. the webui.
+func SetCommandResultPinnedSecret(ctx context.Context, c client.Client, rsId string, pinned bool) error {
+	if rsId == "" {
+		return fmt.Errorf("empty rsId is not allowed")
+	}
+
+	var l metav1.PartialObjectMetadataList
+	l.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "SecretList"})
+	err := c.List(ctx, &l, client.MatchingLabels{
+		"kluctl.io/command-result-id": rsId,
+	})
+	if err != nil {
+		return err
+	}
+	if len(l.Items) == 0 {
+		return fmt.Errorf("command result %s not found", rsId)
+	}
+
+	var value any
+	if pinned {
+		value = "true"
+	}
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"labels": map[string]any{
+				pinnedLabel: value,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range l.Items {
+		err = c.Patch(ctx, &l.Items[i], client.RawPatch(k8stypes.MergePatchType, patch))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *ResultStoreSecrets) WriteValidateResult(vr *result.ValidateResult) error {
 	if !s.allowWrite {
 		return fmt.Errorf("result store is read-only")
@@ -269,7 +353,7 @@ func (s *ResultStoreSecrets) WriteValidateResult(vr *result.ValidateResult) erro
 			Kind:       "Secret",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      s.buildName("vr", vr.Id, vr.ProjectKey),
+			Name:      buildResultName("vr", vr.Id, vr.ProjectKey),
 			Namespace: s.writeNamespace,
 			Labels: map[string]string{
 				"kluctl.io/result":             "true",
@@ -277,6 +361,7 @@ func (s *ResultStoreSecrets) WriteValidateResult(vr *result.ValidateResult) erro
 			},
 			Annotations: map[string]string{
 				"kluctl.io/validate-result-summary": summaryJson,
+				sizeAnnotation:                      fmt.Sprintf("%d", len(compressedVr)+len(summaryJson)),
 			},
 		},
 		Data: map[string][]byte{
@@ -299,7 +384,12 @@ func (s *ResultStoreSecrets) WriteValidateResult(vr *result.ValidateResult) erro
 		return err
 	}
 
-	err = s.cleanupValidateResults(vr.ProjectKey, vr.TargetKey)
+	deleted := map[string]bool{}
+	err = s.cleanupValidateResults(vr.ProjectKey, vr.TargetKey, deleted)
+	if err != nil {
+		return err
+	}
+	err = s.cleanupResultsSize(deleted)
 	if err != nil {
 		return err
 	}
@@ -307,34 +397,118 @@ func (s *ResultStoreSecrets) WriteValidateResult(vr *result.ValidateResult) erro
 	return nil
 }
 
-func (s *ResultStoreSecrets) cleanupOldCommandResults(project result.ProjectKey, target result.TargetKey) error {
+func (s *ResultStoreSecrets) cleanupOldCommandResults(project result.ProjectKey, target result.TargetKey, deleted map[string]bool) error {
 	if !s.allowWrite {
 		return fmt.Errorf("result store is read-only")
 	}
 
-	results, err := s.ListCommandResultSummaries(ListResultSummariesOptions{
+	results, err := s.doListCommandResultSummaries(ListResultSummariesOptions{
 		ProjectFilter: &project,
 	})
 	if err != nil {
 		return err
 	}
 
-	cnt := 0
+	var entries []retentionEntry
+	foundSuccessfulDeploy := false
 	for _, rs := range results {
-		if rs.TargetKey != target {
+		if rs.summary.TargetKey != target || rs.pinned {
 			continue
 		}
-		cnt++
+		e := retentionEntry{
+			id:        rs.summary.Id,
+			startTime: rs.summary.Command.StartTime.Time,
+		}
+		if s.retention.KeepLastSuccessfulDeploy && !foundSuccessfulDeploy && IsSuccessfulDeploy(&rs.summary) {
+			e.protected = true
+			foundSuccessfulDeploy = true
+		}
+		entries = append(entries, e)
+	}
 
-		if cnt > s.keepCommandResultsCount {
-			err := s.client.DeleteAllOf(s.ctx, &corev1.Secret{}, client.InNamespace(s.writeNamespace), client.MatchingLabels{
-				"kluctl.io/command-result-id": rs.Id,
-			})
-			if err != nil {
-				status.Warningf(s.ctx, "Failed to delete old command result %s: %s", rs.Id, err)
-			} else {
-				status.Infof(s.ctx, "Deleted old command result %s", rs.Id)
-			}
+	for _, e := range selectExpiredResults(entries, s.retention.KeepCommandResultsCount, s.retention.KeepCommandResultsAge, time.Now()) {
+		s.deleteOldResult("kluctl.io/command-result-id", e.id, "command result", deleted)
+	}
+	return nil
+}
+
+// deleteOldResult deletes a result as part of the retention based cleanup. Failures are only logged, as they should
+// not fail the command that wrote the new result.
+func (s *ResultStoreSecrets) deleteOldResult(idLabel string, id string, t string, deleted map[string]bool) {
+	err := s.client.DeleteAllOf(s.ctx, &corev1.Secret{}, client.InNamespace(s.writeNamespace), client.MatchingLabels{
+		idLabel: id,
+	})
+	if err != nil {
+		status.Warningf(s.ctx, "Failed to delete old %s %s: %s", t, id, err)
+	} else {
+		status.Infof(s.ctx, "Deleted old %s %s", t, id)
+		deleted[id] = true
+	}
+}
+
+// cleanupResultsSize deletes the oldest command and validate results from the write namespace until the total size is
+// below MaxResultsSize. Pinned results, the last successful deployment and the newest result of each target are
+// never deleted. Results found in deleted are ignored, as the cache might not have caught up with the deletion yet.
+func (s *ResultStoreSecrets) cleanupResultsSize(deleted map[string]bool) error {
+	if s.retention.MaxResultsSize <= 0 {
+		return nil
+	}
+
+	commandResults, err := s.doListCommandResultSummaries(ListResultSummariesOptions{})
+	if err != nil {
+		return err
+	}
+	validateResults, err := s.doListValidateResultSummaries(ListResultSummariesOptions{})
+	if err != nil {
+		return err
+	}
+
+	type targetKey struct {
+		project  result.ProjectKey
+		target   result.TargetKey
+		validate bool
+	}
+	seenTargets := map[targetKey]bool{}
+	seenSuccessfulDeploys := map[targetKey]bool{}
+	isValidateResult := map[string]bool{}
+
+	var entries []retentionEntry
+	add := func(name client.ObjectKey, id string, startTime time.Time, size int64, pinned bool, tk targetKey, successfulDeploy bool) {
+		if name.Namespace != s.writeNamespace || deleted[id] {
+			return
+		}
+		e := retentionEntry{
+			id:        id,
+			startTime: startTime,
+			size:      size,
+			protected: pinned || !seenTargets[tk],
+		}
+		seenTargets[tk] = true
+		if s.retention.KeepLastSuccessfulDeploy && successfulDeploy && !seenSuccessfulDeploys[tk] {
+			e.protected = true
+			seenSuccessfulDeploys[tk] = true
+		}
+		entries = append(entries, e)
+	}
+
+	// both lists are sorted from newest to oldest, so the first entry of each target is the newest one
+	for _, rs := range commandResults {
+		add(rs.name, rs.summary.Id, rs.summary.Command.StartTime.Time, rs.size, rs.pinned,
+			targetKey{project: rs.summary.ProjectKey, target: rs.summary.TargetKey},
+			IsSuccessfulDeploy(&rs.summary))
+	}
+	for _, rs := range validateResults {
+		isValidateResult[rs.summary.Id] = true
+		add(rs.name, rs.summary.Id, rs.summary.StartTime.Time, rs.size, false,
+			targetKey{project: rs.summary.ProjectKey, target: rs.summary.TargetKey, validate: true},
+			false)
+	}
+
+	for _, e := range selectOversizedResults(entries, s.retention.MaxResultsSize) {
+		if isValidateResult[e.id] {
+			s.deleteOldResult("kluctl.io/validate-result-id", e.id, "validate result", deleted)
+		} else {
+			s.deleteOldResult("kluctl.io/command-result-id", e.id, "command result", deleted)
 		}
 	}
 	return nil
@@ -386,7 +560,7 @@ func (s *ResultStoreSecrets) cleanupOrphanedResults() error {
 	}
 
 	for _, e := range commandResults {
-		if e.summary.KluctlDeployment == nil {
+		if e.summary.KluctlDeployment == nil || e.pinned {
 			continue
 		}
 		tryDeleteResult(e.name, e.summary.KluctlDeployment, e.summary.Id, "command result")
@@ -424,33 +598,27 @@ func (s *ResultStoreSecrets) StartCleanupOrphans() error {
 	return nil
 }
 
-func (s *ResultStoreSecrets) cleanupValidateResults(project result.ProjectKey, target result.TargetKey) error {
-	results, err := s.ListValidateResultSummaries(ListResultSummariesOptions{
+func (s *ResultStoreSecrets) cleanupValidateResults(project result.ProjectKey, target result.TargetKey, deleted map[string]bool) error {
+	results, err := s.doListValidateResultSummaries(ListResultSummariesOptions{
 		ProjectFilter: &project,
 	})
 	if err != nil {
 		return err
 	}
 
-	cnt := 0
+	var entries []retentionEntry
 	for _, rs := range results {
-		rs := rs
-
-		if rs.TargetKey != target {
+		if rs.summary.TargetKey != target {
 			continue
 		}
-		cnt++
+		entries = append(entries, retentionEntry{
+			id:        rs.summary.Id,
+			startTime: rs.summary.StartTime.Time,
+		})
+	}
 
-		if cnt > s.keepValidateResultsCount {
-			err := s.client.DeleteAllOf(s.ctx, &corev1.Secret{}, client.InNamespace(s.writeNamespace), client.MatchingLabels{
-				"kluctl.io/validate-result-id": rs.Id,
-			})
-			if err != nil {
-				status.Warningf(s.ctx, "Failed to delete old validate result %s: %s", rs.Id, err)
-			} else {
-				status.Infof(s.ctx, "Deleted old validate result %s", rs.Id)
-			}
-		}
+	for _, e := range selectExpiredResults(entries, s.retention.KeepValidateResultsCount, s.retention.KeepValidateResultsAge, time.Now()) {
+		s.deleteOldResult("kluctl.io/validate-result-id", e.id, "validate result", deleted)
 	}
 	return nil
 }
@@ -458,6 +626,8 @@ func (s *ResultStoreSecrets) cleanupValidateResults(project result.ProjectKey, t
 type commandResultSummaryAndName struct {
 	name    client.ObjectKey
 	summary result.CommandResultSummary
+	size    int64
+	pinned  bool
 }
 
 func (s *ResultStoreSecrets) doListCommandResultSummaries(options ListResultSummariesOptions) ([]commandResultSummaryAndName, error) {
@@ -471,8 +641,8 @@ func (s *ResultStoreSecrets) doListCommandResultSummaries(options ListResultSumm
 	ret := make([]commandResultSummaryAndName, 0, len(l.Items))
 
 	for _, x := range l.Items {
-		summary, err := s.parseCommandSummary(x.GetAnnotations())
-		if err != nil {
+		summary, err := s.parseCommandSummary(&x)
+		if err != nil || summary == nil {
 			continue
 		}
 		if !FilterProject(summary.ProjectKey, options.ProjectFilter) {
@@ -482,6 +652,8 @@ func (s *ResultStoreSecrets) doListCommandResultSummaries(options ListResultSumm
 		ret = append(ret, commandResultSummaryAndName{
 			name:    client.ObjectKeyFromObject(&x),
 			summary: *summary,
+			size:    parseResultSize(&x),
+			pinned:  summary.Pinned,
 		})
 	}
 
@@ -506,7 +678,18 @@ func (s *ResultStoreSecrets) ListCommandResultSummaries(options ListResultSummar
 	return ret, nil
 }
 
-func (s *ResultStoreSecrets) parseCommandSummary(a map[string]string) (*result.CommandResultSummary, error) {
+// parseResultSize returns the size of the result as recorded when it was written. Results written by older versions of
+// Kluctl do not have the size recorded, in which case 0 is returned.
+func parseResultSize(o metav1.Object) int64 {
+	size, err := strconv.ParseInt(o.GetAnnotations()[sizeAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return size
+}
+
+func (s *ResultStoreSecrets) parseCommandSummary(o metav1.Object) (*result.CommandResultSummary, error) {
+	a := o.GetAnnotations()
 	if len(a) == 0 {
 		return nil, nil
 	}
@@ -520,6 +703,7 @@ func (s *ResultStoreSecrets) parseCommandSummary(a map[string]string) (*result.C
 	if err != nil {
 		return nil, err
 	}
+	summary.Pinned = o.GetLabels()[pinnedLabel] == "true"
 
 	return &summary, nil
 }
@@ -556,7 +740,7 @@ func (s *ResultStoreSecrets) WatchCommandResultSummaries(options ListResultSumma
 		if o == nil {
 			return nil
 		}
-		summary, err := s.parseCommandSummary(o.GetAnnotations())
+		summary, err := s.parseCommandSummary(o)
 		if err != nil || summary == nil {
 			return nil
 		}
@@ -680,6 +864,7 @@ func (s *ResultStoreSecrets) GetCommandResult(options GetCommandResultOptions) (
 type validateResultSummaryAndName struct {
 	name    client.ObjectKey
 	summary result.ValidateResultSummary
+	size    int64
 }
 
 func (s *ResultStoreSecrets) doListValidateResultSummaries(options ListResultSummariesOptions) ([]validateResultSummaryAndName, error) {
@@ -694,7 +879,7 @@ func (s *ResultStoreSecrets) doListValidateResultSummaries(options ListResultSum
 
 	for _, x := range l.Items {
 		summary, err := s.parseValidateSummary(x.GetAnnotations())
-		if err != nil {
+		if err != nil || summary == nil {
 			continue
 		}
 		if !FilterProject(summary.ProjectKey, options.ProjectFilter) {
@@ -704,6 +889,7 @@ func (s *ResultStoreSecrets) doListValidateResultSummaries(options ListResultSum
 		ret = append(ret, validateResultSummaryAndName{
 			name:    client.ObjectKeyFromObject(&x),
 			summary: *summary,
+			size:    parseResultSize(&x),
 		})
 	}
 
@@ -929,3 +1115,154 @@ func (s *ResultStoreSecrets) GetKluctlDeployment(clusterId string, name string,
 	}
 	return &k, nil
 }
+
+func (s *ResultStoreSecrets) WriteAuditEvent(e *result.AuditEvent) error {
+	if !s.allowWrite {
+		return fmt.Errorf("result store is read-only")
+	}
+
+	err := s.ensureWriteNamespace()
+	if err != nil {
+		return err
+	}
+
+	err = WriteAuditEventSecret(s.ctx, s.client, s.writeNamespace, e)
+	if err != nil {
+		return err
+	}
+
+	err = s.cleanupAuditEvents(e)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WriteAuditEventSecret writes the audit event into the given namespace without requiring a writable ResultStore. It is
+// used by components that only have read access to results but still need to record actions, e.g. the webui.
+func WriteAuditEventSecret(ctx context.Context, c client.Client, namespace string, e *result.AuditEvent) error {
+	eventJson, err := yaml.WriteJsonString(e)
+	if err != nil {
+		return err
+	}
+
+	var projectKey result.ProjectKey
+	if e.ProjectKey != nil {
+		projectKey = *e.ProjectKey
+	}
+
+	secret := corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      buildResultName("audit", e.Id, projectKey),
+			Namespace: namespace,
+			Labels: map[string]string{
+				"kluctl.io/result":                      "true",
+				"kluctl.io/audit-event-id":              e.Id,
+				"kluctl.io/result-deployment-name":      e.KluctlDeployment.Name,
+				"kluctl.io/result-deployment-namespace": e.KluctlDeployment.Namespace,
+			},
+			Annotations: map[string]string{
+				"kluctl.io/audit-event": eventJson,
+			},
+		},
+	}
+
+	return c.Patch(ctx, &secret, client.Apply, client.FieldOwner("kluctl-results"))
+}
+
+func (s *ResultStoreSecrets) cleanupAuditEvents(newEvent *result.AuditEvent) error {
+	if s.retention.KeepAuditEventsCount <= 0 {
+		return nil
+	}
+
+	events, err := s.doListAuditEvents(ListAuditEventsOptions{
+		KluctlDeployment: &newEvent.KluctlDeployment,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the cache might not contain the new event yet, but it must still be counted
+	keep := s.retention.KeepAuditEventsCount - 1
+	for _, e := range events {
+		if e.event.Id == newEvent.Id {
+			keep++
+			break
+		}
+	}
+
+	for i, e := range events {
+		if i < keep || e.name.Namespace != s.writeNamespace {
+			continue
+		}
+		err := s.client.DeleteAllOf(s.ctx, &corev1.Secret{}, client.InNamespace(s.writeNamespace), client.MatchingLabels{
+			"kluctl.io/audit-event-id": e.event.Id,
+		})
+		if err != nil {
+			status.Warningf(s.ctx, "Failed to delete old audit event %s: %s", e.event.Id, err)
+		} else {
+			status.Infof(s.ctx, "Deleted old audit event %s", e.event.Id)
+		}
+	}
+	return nil
+}
+
+type auditEventAndName struct {
+	name  client.ObjectKey
+	event result.AuditEvent
+}
+
+func (s *ResultStoreSecrets) doListAuditEvents(options ListAuditEventsOptions) ([]auditEventAndName, error) {
+	var l metav1.PartialObjectMetadataList
+	l.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "SecretList"})
+	err := s.cache.List(s.ctx, &l, client.HasLabels{"kluctl.io/audit-event-id"})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]auditEventAndName, 0, len(l.Items))
+
+	for _, x := range l.Items {
+		eventJson := x.GetAnnotations()["kluctl.io/audit-event"]
+		if eventJson == "" {
+			continue
+		}
+		var e result.AuditEvent
+		err = yaml.ReadYamlString(eventJson, &e)
+		if err != nil {
+			continue
+		}
+		if !filterAuditEvent(&e, options) {
+			continue
+		}
+
+		ret = append(ret, auditEventAndName{
+			name:  client.ObjectKeyFromObject(&x),
+			event: e,
+		})
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return lessAuditEvent(&ret[i].event, &ret[j].event)
+	})
+
+	return ret, nil
+}
+
+func (s *ResultStoreSecrets) ListAuditEvents(options ListAuditEventsOptions) ([]result.AuditEvent, error) {
+	events, err := s.doListAuditEvents(options)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]result.AuditEvent, 0, len(events))
+	for _, e := range events {
+		ret = append(ret, e.event)
+	}
+	return ret, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
 	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"sort"
 )
 
 type ListResultSummariesOptions struct {
@@ -19,6 +20,11 @@ type GetValidateResultOptions struct {
 	Id string `json:"id"`
 }
 
+type ListAuditEventsOptions struct {
+	// KluctlDeployment filters audit events by the KluctlDeployment. Empty fields match all values.
+	KluctlDeployment *result.KluctlDeploymentInfo `json:"kluctlDeployment,omitempty"`
+}
+
 type WatchCommandResultSummaryEvent struct {
 	Summary *result.CommandResultSummary `json:"summary"`
 	Delete  bool                         `json:"delete"`
@@ -39,6 +45,8 @@ type ResultStore interface {
 	WriteCommandResult(cr *result.CommandResult) error
 	WriteValidateResult(vr *result.ValidateResult) error
 	DeleteCommandResult(rsId string) error
+	DeleteValidateResult(vrId string) error
+	SetCommandResultPinned(rsId string, pinned bool) error
 
 	ListCommandResultSummaries(options ListResultSummariesOptions) ([]result.CommandResultSummary, error)
 	WatchCommandResultSummaries(options ListResultSummariesOptions) (<-chan WatchCommandResultSummaryEvent, context.CancelFunc, error)
@@ -51,6 +59,9 @@ type ResultStore interface {
 	ListKluctlDeployments() ([]WatchKluctlDeploymentEvent, error)
 	WatchKluctlDeployments() (<-chan WatchKluctlDeploymentEvent, context.CancelFunc, error)
 	GetKluctlDeployment(clusterId string, name string, namespace string) (*kluctlv1.KluctlDeployment, error)
+
+	WriteAuditEvent(e *result.AuditEvent) error
+	ListAuditEvents(options ListAuditEventsOptions) ([]result.AuditEvent, error)
 }
 
 func FilterProject(x result.ProjectKey, filter *result.ProjectKey) bool {
@@ -87,3 +98,32 @@ func lessValidateSummary(a *result.ValidateResultSummary, b *result.ValidateResu
 	}
 	return a.Id < b.Id
 }
+
+func lessAuditEvent(a *result.AuditEvent, b *result.AuditEvent) bool {
+	if a.Time != b.Time {
+		return a.Time.After(b.Time.Time)
+	}
+	return a.Id < b.Id
+}
+
+// SortAuditEvents sorts the given audit events from newest to oldest.
+func SortAuditEvents(events []result.AuditEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		return lessAuditEvent(&events[i], &events[j])
+	})
+}
+
+func filterAuditEvent(e *result.AuditEvent, options ListAuditEventsOptions) bool {
+	if f := options.KluctlDeployment; f != nil {
+		if f.Name != "" && e.KluctlDeployment.Name != f.Name {
+			return false
+		}
+		if f.Namespace != "" && e.KluctlDeployment.Namespace != f.Namespace {
+			return false
+		}
+		if f.ClusterId != "" && e.KluctlDeployment.ClusterId != f.ClusterId {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func TestSortAndFilterAuditEvents(t *testing.T) {
+	now := time.Now()
+	buildEvent := func(id string, name string, clusterId string, age time.Duration) result.AuditEvent {
+		return result.AuditEvent{
+			Id:   id,
+			Time: metav1.NewTime(now.Add(-age)),
+			KluctlDeployment: result.KluctlDeploymentInfo{
+				Name:      name,
+				Namespace: "ns",
+				ClusterId: clusterId,
+			},
+		}
+	}
+
+	events := []result.AuditEvent{
+		buildEvent("a", "kd1", "c1", time.Minute*2),
+		buildEvent("c", "kd2", "c1", 0),
+		buildEvent("b", "kd1", "c2", time.Minute),
+		buildEvent("d", "kd1", "c1", 0),
+	}
+	SortAuditEvents(events)
+
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.Id)
+	}
+	assert.Equal(t, []string{"c", "d", "b", "a"}, ids)
+
+	filter := func(options ListAuditEventsOptions) []string {
+		var ret []string
+		for _, e := range events {
+			if filterAuditEvent(&e, options) {
+				ret = append(ret, e.Id)
+			}
+		}
+		return ret
+	}
+
+	assert.Equal(t, []string{"c", "d", "b", "a"}, filter(ListAuditEventsOptions{}))
+	assert.Equal(t, []string{"d", "b", "a"}, filter(ListAuditEventsOptions{
+		KluctlDeployment: &result.KluctlDeploymentInfo{Name: "kd1"},
+	}))
+	assert.Equal(t, []string{"d", "a"}, filter(ListAuditEventsOptions{
+		KluctlDeployment: &result.KluctlDeploymentInfo{Name: "kd1", Namespace: "ns", ClusterId: "c1"},
+	}))
+	assert.Empty(t, filter(ListAuditEventsOptions{
+		KluctlDeployment: &result.KluctlDeploymentInfo{Namespace: "other"},
+	}))
+}
@@ -251,6 +251,14 @@ func (rc *ResultsCollector) DeleteCommandResult(rsId string) error {
 	return fmt.Errorf("DeleteCommandResult is not supported in ResultsCollector")
 }
 
+func (rc *ResultsCollector) DeleteValidateResult(vrId string) error {
+	return fmt.Errorf("DeleteValidateResult is not supported in ResultsCollector")
+}
+
+func (rc *ResultsCollector) SetCommandResultPinned(rsId string, pinned bool) error {
+	return fmt.Errorf("SetCommandResultPinned is not supported in ResultsCollector")
+}
+
 func (rc *ResultsCollector) ListCommandResultSummaries(options ListResultSummariesOptions) ([]result.CommandResultSummary, error) {
 	rc.mutex.Lock()
 	defer rc.mutex.Unlock()
@@ -457,3 +465,20 @@ func (rc *ResultsCollector) GetKluctlDeployment(clusterId string, name string, n
 	}
 	return nil, nil
 }
+
+func (rc *ResultsCollector) WriteAuditEvent(e *result.AuditEvent) error {
+	return fmt.Errorf("WriteAuditEvent is not supported in ResultsCollector")
+}
+
+func (rc *ResultsCollector) ListAuditEvents(options ListAuditEventsOptions) ([]result.AuditEvent, error) {
+	var ret []result.AuditEvent
+	for _, store := range rc.stores {
+		events, err := store.ListAuditEvents(options)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, events...)
+	}
+	SortAuditEvents(ret)
+	return ret, nil
+}
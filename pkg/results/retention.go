@@ -0,0 +1,94 @@
+package results
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"sort"
+	"time"
+)
+
+// RetentionOptions configures which results are kept by ResultStoreSecrets when new results are written.
+type RetentionOptions struct {
+	KeepCommandResultsCount  int
+	KeepValidateResultsCount int
+	KeepAuditEventsCount     int
+
+	// KeepCommandResultsAge and KeepValidateResultsAge cause results that are older than the given age to be deleted,
+	// even if the count limit is not reached yet. The newest result of each target is always kept. A value of 0
+	// disables age based retention.
+	KeepCommandResultsAge  time.Duration
+	KeepValidateResultsAge time.Duration
+
+	// MaxResultsSize limits the total size (in bytes) of all command and validate results inside the write namespace.
+	// When exceeded, the oldest results are deleted until the total size fits again. A value of 0 disables the limit.
+	MaxResultsSize int64
+
+	// KeepLastSuccessfulDeploy prevents the newest successful deployment of each target from being deleted, no matter
+	// how old it is.
+	KeepLastSuccessfulDeploy bool
+}
+
+// retentionEntry is the retention relevant information about a single command or validate result.
+type retentionEntry struct {
+	id        string
+	startTime time.Time
+	size      int64
+
+	// protected entries are never deleted, e.g. because they are pinned or the last successful deployment
+	protected bool
+}
+
+// IsSuccessfulDeploy returns true if the command result summary belongs to a deployment that was not a dry-run and
+// did not result in errors.
+func IsSuccessfulDeploy(s *result.CommandResultSummary) bool {
+	return s.Command.Command == "deploy" && !s.Command.DryRun && len(s.Errors) == 0
+}
+
+// selectExpiredResults returns the entries that exceed keepCount or keepAge. The entries must belong to a single target
+// and must be sorted from newest to oldest. The newest entry is never selected.
+func selectExpiredResults(entries []retentionEntry, keepCount int, keepAge time.Duration, now time.Time) []retentionEntry {
+	var ret []retentionEntry
+	for i, e := range entries {
+		if e.protected {
+			continue
+		}
+		expired := i >= keepCount
+		if i != 0 && keepAge != 0 && e.startTime.Before(now.Add(-keepAge)) {
+			expired = true
+		}
+		if expired {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// selectOversizedResults returns the oldest entries that need to be deleted to get the total size of all entries below
+// maxSize. Protected entries are counted but never selected.
+func selectOversizedResults(entries []retentionEntry, maxSize int64) []retentionEntry {
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+	if total <= maxSize {
+		return nil
+	}
+
+	sorted := make([]retentionEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].startTime.Before(sorted[j].startTime)
+	})
+
+	var ret []retentionEntry
+	for _, e := range sorted {
+		if total <= maxSize {
+			break
+		}
+		if e.protected {
+			continue
+		}
+		ret = append(ret, e)
+		total -= e.size
+	}
+	return ret
+}
@@ -0,0 +1,49 @@
+package results
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func retentionIds(entries []retentionEntry) []string {
+	var ret []string
+	for _, e := range entries {
+		ret = append(ret, e.id)
+	}
+	return ret
+}
+
+func TestSelectExpiredResults(t *testing.T) {
+	now := time.Now()
+	entries := []retentionEntry{
+		{id: "r1", startTime: now.Add(-10 * time.Hour)},
+		{id: "r2", startTime: now.Add(-20 * time.Hour)},
+		{id: "r3", startTime: now.Add(-30 * time.Hour), protected: true},
+		{id: "r4", startTime: now.Add(-40 * time.Hour)},
+		{id: "r5", startTime: now.Add(-50 * time.Hour)},
+	}
+
+	assert.Equal(t, []string{"r4", "r5"}, retentionIds(selectExpiredResults(entries, 3, 0, now)))
+	assert.Equal(t, []string{"r4", "r5"}, retentionIds(selectExpiredResults(entries, 10, 35*time.Hour, now)))
+	assert.Equal(t, []string{"r2", "r4", "r5"}, retentionIds(selectExpiredResults(entries, 10, 15*time.Hour, now)))
+
+	// the newest result is always kept
+	assert.Equal(t, []string{"r2", "r4", "r5"}, retentionIds(selectExpiredResults(entries, 10, time.Hour, now)))
+	assert.Equal(t, []string{"r2", "r4", "r5"}, retentionIds(selectExpiredResults(entries, 1, time.Hour, now)))
+}
+
+func TestSelectOversizedResults(t *testing.T) {
+	now := time.Now()
+	entries := []retentionEntry{
+		{id: "r1", startTime: now.Add(-10 * time.Hour), size: 100},
+		{id: "r3", startTime: now.Add(-30 * time.Hour), size: 100, protected: true},
+		{id: "r2", startTime: now.Add(-20 * time.Hour), size: 100},
+		{id: "r4", startTime: now.Add(-40 * time.Hour), size: 100},
+	}
+
+	assert.Nil(t, selectOversizedResults(entries, 400))
+	assert.Equal(t, []string{"r4"}, retentionIds(selectOversizedResults(entries, 300)))
+	assert.Equal(t, []string{"r4", "r2"}, retentionIds(selectOversizedResults(entries, 250)))
+	assert.Equal(t, []string{"r4", "r2", "r1"}, retentionIds(selectOversizedResults(entries, 0)))
+}
@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 )
 
@@ -53,6 +54,31 @@ func NewSealer(ctx context.Context, cert *x509.Certificate, forceReseal bool) (*
 	return s, nil
 }
 
+func (s *Sealer) CertHash() string {
+	return s.certHash
+}
+
+// GetOutdatedCertHashes returns the cert hashes of all SealedSecrets found in the given sealed file that do not match
+// the cert of this Sealer. Secrets that have no cert hash annotation are reported with an empty hash.
+func (s *Sealer) GetOutdatedCertHashes(p string) ([]string, error) {
+	existing, err := s.loadExistingSealedSecrets(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []string
+	found := map[string]bool{}
+	for _, ss := range existing {
+		if ss.certHash == s.certHash || found[ss.certHash] {
+			continue
+		}
+		found[ss.certHash] = true
+		ret = append(ret, ss.certHash)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
 func HashSecret(key string, secret []byte, secretName string, secretNamespace string, scope string) string {
 	if secretNamespace == "" {
 		secretNamespace = "*"
@@ -115,6 +115,11 @@ func (d *Decryptor) AddKeyServiceClient(s keyservice.KeyServiceClient) {
 	d.keyServices = append(d.keyServices, s)
 }
 
+// KeyServices returns the key services added to the Decryptor, e.g. to use them for encryption.
+func (d *Decryptor) KeyServices() []keyservice.KeyServiceClient {
+	return d.keyServices
+}
+
 // IsEncryptedSecret checks if the given object is a Kubernetes Secret encrypted
 // with Mozilla SOPS.
 func IsEncryptedSecret(object *unstructured.Unstructured) bool {
@@ -0,0 +1,208 @@
+package sops
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/getsops/sops/v3"
+	"github.com/getsops/sops/v3/aes"
+	"github.com/getsops/sops/v3/cmd/sops/common"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/getsops/sops/v3/config"
+	"github.com/getsops/sops/v3/keyservice"
+	"github.com/getsops/sops/v3/version"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"os"
+	"path/filepath"
+)
+
+// LoadCreationRule loads the creation rule matching the given file. If configPath is empty, the nearest .sops.yaml is
+// searched, starting at the directory of the given file and walking up the directory tree.
+func LoadCreationRule(path string, configPath string) (*config.Config, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if configPath == "" {
+		configPath, err = findSopsConfig(filepath.Dir(absPath))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rule, err := config.LoadCreationRuleForFile(configPath, absPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load creation rules from %s: %w", configPath, err)
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("no creation rule in %s matches %s", configPath, path)
+	}
+	return rule, nil
+}
+
+func findSopsConfig(dir string) (string, error) {
+	for {
+		p := filepath.Join(dir, ".sops.yaml")
+		if utils.IsFile(p) {
+			return p, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no .sops.yaml found")
+		}
+		dir = parent
+	}
+}
+
+// IsEncrypted returns true if the given data contains SOPS metadata
+func IsEncrypted(data []byte, format formats.Format) bool {
+	if !IsMaybeSopsFile(data) {
+		return false
+	}
+	_, err := common.StoreForFormat(format).LoadEncryptedFile(data)
+	return err == nil
+}
+
+// Encrypt encrypts plain data in the given format with the keys from the given creation rule. The data key is
+// encrypted by the first key service that supports the configured master keys.
+func Encrypt(keyServices []keyservice.KeyServiceClient, rule *config.Config, plain []byte, format formats.Format) ([]byte, error) {
+	store := common.StoreForFormat(format)
+
+	if IsEncrypted(plain, format) {
+		return nil, fmt.Errorf("data is already encrypted")
+	}
+
+	branches, err := store.LoadPlainFile(plain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load plain data: %w", err)
+	}
+	if len(branches) < 1 {
+		return nil, fmt.Errorf("data must contain at least one document")
+	}
+
+	tree := sops.Tree{
+		Branches: branches,
+		Metadata: sops.Metadata{
+			KeyGroups:         rule.KeyGroups,
+			UnencryptedSuffix: rule.UnencryptedSuffix,
+			EncryptedSuffix:   rule.EncryptedSuffix,
+			UnencryptedRegex:  rule.UnencryptedRegex,
+			EncryptedRegex:    rule.EncryptedRegex,
+			Version:           version.Version,
+			ShamirThreshold:   rule.ShamirThreshold,
+		},
+	}
+	dataKey, errs := tree.GenerateDataKeyWithKeyServices(keyServices)
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("failed to generate data key: %w", errors.Join(errs...))
+	}
+
+	return encryptTree(store, &tree, dataKey)
+}
+
+// EditFile decrypts the given file into a temporary file, calls editFn with the path of the temporary file and then
+// re-encrypts the edited content with the same data key and master keys. If the file does not exist yet, editing starts
+// with an empty file, which is then encrypted with the matching creation rule from configPath (see LoadCreationRule).
+// Returns false if the content was not changed by editFn.
+func EditFile(ctx context.Context, keyServices []keyservice.KeyServiceClient, path string, configPath string, editFn func(tmpPath string) error) (bool, error) {
+	format := formats.FormatForPath(path)
+	store := common.StoreForFormat(format)
+
+	var tree *sops.Tree
+	var dataKey []byte
+	var plain []byte
+	if utils.Exists(path) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		t, err := store.LoadEncryptedFile(data)
+		if err != nil {
+			return false, fmt.Errorf("failed to load encrypted file %s: %w", path, err)
+		}
+		tree = &t
+		dataKey, err = common.DecryptTree(common.DecryptTreeOpts{
+			Tree:        tree,
+			KeyServices: keyServices,
+			Cipher:      aes.NewCipher(),
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to decrypt %s: %w", path, err)
+		}
+		plain, err = store.EmitPlainFile(tree.Branches)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// keep the extension so that editors can detect the format
+	tmpFile, err := os.CreateTemp(utils.GetTmpBaseDir(ctx), "sops-edit-*"+filepath.Ext(path))
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmpFile.Name())
+	_, err = tmpFile.Write(plain)
+	_ = tmpFile.Close()
+	if err != nil {
+		return false, err
+	}
+
+	err = editFn(tmpFile.Name())
+	if err != nil {
+		return false, err
+	}
+
+	newPlain, err := os.ReadFile(tmpFile.Name())
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(plain, newPlain) {
+		return false, nil
+	}
+
+	var encrypted []byte
+	if tree == nil {
+		rule, err := LoadCreationRule(path, configPath)
+		if err != nil {
+			return false, err
+		}
+		encrypted, err = Encrypt(keyServices, rule, newPlain, format)
+		if err != nil {
+			return false, err
+		}
+	} else {
+		tree.Branches, err = store.LoadPlainFile(newPlain)
+		if err != nil {
+			return false, fmt.Errorf("failed to load edited file: %w", err)
+		}
+		encrypted, err = encryptTree(store, tree, dataKey)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = os.WriteFile(path, encrypted, 0o600)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func encryptTree(store common.Store, tree *sops.Tree, dataKey []byte) ([]byte, error) {
+	err := common.EncryptTree(common.EncryptTreeOpts{
+		Tree:    tree,
+		Cipher:  aes.NewCipher(),
+		DataKey: dataKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt: %w", err)
+	}
+
+	encrypted, err := store.EmitEncryptedFile(*tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to emit encrypted data: %w", err)
+	}
+	return encrypted, nil
+}
@@ -0,0 +1,106 @@
+package sops
+
+import (
+	"context"
+	extage "filippo.io/age"
+	"fmt"
+	sopsage "github.com/getsops/sops/v3/age"
+	"github.com/getsops/sops/v3/cmd/sops/formats"
+	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setupSopsConfig(t *testing.T) (string, *decryptor.Decryptor) {
+	ageID, err := extage.GenerateX25519Identity()
+	assert.NoError(t, err)
+	t.Setenv(sopsage.SopsAgeKeyEnv, ageID.String())
+
+	dir := t.TempDir()
+	err = os.WriteFile(filepath.Join(dir, ".sops.yaml"), []byte(fmt.Sprintf(`creation_rules:
+  - path_regex: .*\.yaml
+    encrypted_regex: ^secret$
+    age: %s
+`, ageID.Recipient().String())), 0o600)
+	assert.NoError(t, err)
+
+	d := decryptor.NewDecryptor(dir, decryptor.MaxEncryptedFileSize)
+	d.AddLocalKeyService()
+	return dir, d
+}
+
+func decryptForTest(t *testing.T, d *decryptor.Decryptor, path string) string {
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	plain, encrypted, err := MaybeDecrypt(d, data, formats.Yaml, formats.Yaml)
+	assert.NoError(t, err)
+	assert.True(t, encrypted)
+	return string(plain)
+}
+
+func TestEncrypt(t *testing.T) {
+	dir, d := setupSopsConfig(t)
+	path := filepath.Join(dir, "sub", "test.yaml")
+
+	rule, err := LoadCreationRule(path, "")
+	assert.NoError(t, err)
+
+	plain := []byte("a: b\nsecret: s3cr3t\n")
+	encrypted, err := Encrypt(d.KeyServices(), rule, plain, formats.Yaml)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted, formats.Yaml))
+	assert.Contains(t, string(encrypted), "a: b\n")
+	assert.NotContains(t, string(encrypted), "s3cr3t")
+
+	_, err = Encrypt(d.KeyServices(), rule, encrypted, formats.Yaml)
+	assert.ErrorContains(t, err, "already encrypted")
+
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	assert.NoError(t, os.WriteFile(path, encrypted, 0o600))
+	assert.Equal(t, string(plain), decryptForTest(t, d, path))
+
+	_, err = LoadCreationRule(filepath.Join(dir, "test.json"), "")
+	assert.ErrorContains(t, err, "no matching creation rules found")
+}
+
+func TestEditFile(t *testing.T) {
+	dir, d := setupSopsConfig(t)
+	path := filepath.Join(dir, "test.yaml")
+
+	// new file
+	changed, err := EditFile(context.Background(), d.KeyServices(), path, "", func(tmpPath string) error {
+		assert.Equal(t, ".yaml", filepath.Ext(tmpPath))
+		return os.WriteFile(tmpPath, []byte("secret: s3cr3t\n"), 0o600)
+	})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "secret: s3cr3t\n", decryptForTest(t, d, path))
+
+	before, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	// unchanged
+	changed, err = EditFile(context.Background(), d.KeyServices(), path, "", func(tmpPath string) error {
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	after, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	// changed
+	changed, err = EditFile(context.Background(), d.KeyServices(), path, "", func(tmpPath string) error {
+		b, err := os.ReadFile(tmpPath)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(tmpPath, []byte(strings.ReplaceAll(string(b), "s3cr3t", "changed")), 0o600)
+	})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "secret: changed\n", decryptForTest(t, d, path))
+}
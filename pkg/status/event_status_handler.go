@@ -0,0 +1,225 @@
+package status
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventStart       EventType = "start"
+	EventUpdate      EventType = "update"
+	EventProgress    EventType = "progress"
+	EventSuccess     EventType = "success"
+	EventWarning     EventType = "warning"
+	EventFailure     EventType = "failure"
+	EventMessage     EventType = "message"
+	EventDeprecation EventType = "deprecation"
+	EventObject      EventType = "object"
+	EventPrompt      EventType = "prompt"
+)
+
+// Event is a single structured status event, as emitted by the handler returned from NewEventStatusHandler.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+
+	// Id identifies the status line for start, update, progress, success, warning and failure events.
+	Id int64 `json:"id,omitempty"`
+
+	Level   string `json:"level,omitempty"`
+	Message string `json:"message,omitempty"`
+
+	Current int `json:"current,omitempty"`
+	Total   int `json:"total,omitempty"`
+
+	// DurationMs is set for events that end a status line and for object events.
+	DurationMs int64 `json:"durationMs,omitempty"`
+
+	// Key is the deprecation key for deprecation events.
+	Key string `json:"key,omitempty"`
+
+	Object *ObjectEvent `json:"object,omitempty"`
+
+	// Password is set for prompt events that ask for secret input.
+	Password bool `json:"password,omitempty"`
+}
+
+type eventStatusHandler struct {
+	cb     func(e *Event)
+	trace  bool
+	nextId atomic.Int64
+}
+
+type eventStatusLine struct {
+	sh    *eventStatusHandler
+	id    int64
+	level Level
+	start time.Time
+
+	mutex   sync.Mutex
+	current int
+	total   int
+	ended   bool
+}
+
+// NewEventStatusHandler creates a StatusHandler that reports all status lines, messages, deprecations and object
+// events as structured events to cb. cb might be called concurrently.
+func NewEventStatusHandler(cb func(e *Event), trace bool) StatusHandler {
+	return &eventStatusHandler{
+		cb:    cb,
+		trace: trace,
+	}
+}
+
+// NewJsonLinesStatusHandler creates a StatusHandler that writes one JSON encoded Event per line to out.
+func NewJsonLinesStatusHandler(out io.Writer, trace bool) StatusHandler {
+	var mutex sync.Mutex
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+	return NewEventStatusHandler(func(e *Event) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		_ = enc.Encode(e)
+	}, trace)
+}
+
+func (s *eventStatusHandler) emit(e Event) {
+	e.Time = time.Now()
+	s.cb(&e)
+}
+
+func (s *eventStatusHandler) IsTraceEnabled() bool {
+	return s.trace
+}
+
+func (s *eventStatusHandler) SetTrace(trace bool) {
+	s.trace = trace
+}
+
+func (s *eventStatusHandler) Stop() {
+}
+
+func (s *eventStatusHandler) Flush() {
+}
+
+func (s *eventStatusHandler) StartStatus(level Level, total int, message string) StatusLine {
+	sl := &eventStatusLine{
+		sh:    s,
+		id:    s.nextId.Add(1),
+		level: level,
+		start: time.Now(),
+		total: total,
+	}
+	s.emit(Event{
+		Type:    EventStart,
+		Id:      sl.id,
+		Level:   level.String(),
+		Message: message,
+		Total:   total,
+	})
+	return sl
+}
+
+func (s *eventStatusHandler) Message(level Level, message string) {
+	if level == LevelTrace && !s.trace {
+		return
+	}
+	s.emit(Event{
+		Type:    EventMessage,
+		Level:   level.String(),
+		Message: message,
+	})
+}
+
+func (s *eventStatusHandler) MessageFallback(level Level, message string) {
+	// all status line updates are already reported as events
+}
+
+func (s *eventStatusHandler) Deprecation(key string, message string) {
+	s.emit(Event{
+		Type:    EventDeprecation,
+		Level:   LevelWarning.String(),
+		Message: message,
+		Key:     key,
+	})
+}
+
+func (s *eventStatusHandler) ObjectEvent(e ObjectEvent) {
+	s.emit(Event{
+		Type:       EventObject,
+		DurationMs: e.Duration.Milliseconds(),
+		Object:     &e,
+	})
+}
+
+func (s *eventStatusHandler) Prompt(message string, password bool) {
+	s.emit(Event{
+		Type:     EventPrompt,
+		Level:    LevelPrompt.String(),
+		Message:  message,
+		Password: password,
+	})
+}
+
+func (sl *eventStatusLine) SetTotal(total int) {
+	sl.mutex.Lock()
+	defer sl.mutex.Unlock()
+	sl.total = total
+}
+
+func (sl *eventStatusLine) Increment() {
+	sl.mutex.Lock()
+	if sl.current < sl.total {
+		sl.current++
+	}
+	e := Event{
+		Type:    EventProgress,
+		Id:      sl.id,
+		Current: sl.current,
+		Total:   sl.total,
+	}
+	sl.mutex.Unlock()
+
+	sl.sh.emit(e)
+}
+
+func (sl *eventStatusLine) Update(message string) {
+	sl.sh.emit(Event{
+		Type:    EventUpdate,
+		Id:      sl.id,
+		Level:   sl.level.String(),
+		Message: message,
+	})
+}
+
+func (sl *eventStatusLine) End(result EndResult) {
+	sl.mutex.Lock()
+	if sl.ended {
+		sl.mutex.Unlock()
+		return
+	}
+	sl.ended = true
+	sl.mutex.Unlock()
+
+	e := Event{
+		Id:         sl.id,
+		DurationMs: time.Since(sl.start).Milliseconds(),
+	}
+	switch result {
+	case EndSuccess:
+		e.Type = EventSuccess
+	case EndWarning:
+		e.Type = EventWarning
+	default:
+		e.Type = EventFailure
+	}
+	sl.sh.emit(e)
+}
+
+var _ StatusHandler = &eventStatusHandler{}
+var _ EventHandler = &eventStatusHandler{}
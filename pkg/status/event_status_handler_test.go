@@ -0,0 +1,82 @@
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func readJsonLines(t *testing.T, buf *bytes.Buffer) []Event {
+	var ret []Event
+	for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e Event
+		err := json.Unmarshal([]byte(l), &e)
+		assert.NoError(t, err)
+		ret = append(ret, e)
+	}
+	return ret
+}
+
+func TestJsonLinesStatusHandler(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	ctx := NewContext(context.Background(), NewJsonLinesStatusHandler(buf, false))
+
+	s := Start(ctx, "test")
+	s.Update("update")
+	s.Increment()
+	s.Success()
+	s.Failed()
+
+	Trace(ctx, "trace")
+	Warning(ctx, "warning")
+	Deprecation(ctx, "key", "deprecated")
+	Deprecation(ctx, "key", "deprecated")
+
+	ref := k8s.ObjectRef{Kind: "ConfigMap", Name: "cm", Namespace: "default"}
+	ReportObjectEvent(ctx, ObjectEvent{Type: ObjectEventApplied, Ref: ref})
+
+	events := readJsonLines(t, buf)
+	assert.Len(t, events, 7)
+
+	assert.Equal(t, EventStart, events[0].Type)
+	assert.Equal(t, "test", events[0].Message)
+	assert.Equal(t, 1, events[0].Total)
+	assert.NotZero(t, events[0].Id)
+
+	assert.Equal(t, EventUpdate, events[1].Type)
+	assert.Equal(t, "update", events[1].Message)
+	assert.Equal(t, EventProgress, events[2].Type)
+	assert.Equal(t, 1, events[2].Current)
+	assert.Equal(t, EventSuccess, events[3].Type)
+	for i := 1; i < 4; i++ {
+		assert.Equal(t, events[0].Id, events[i].Id)
+	}
+
+	assert.Equal(t, EventMessage, events[4].Type)
+	assert.Equal(t, "warning", events[4].Level)
+
+	assert.Equal(t, EventDeprecation, events[5].Type)
+	assert.Equal(t, "key", events[5].Key)
+
+	assert.Equal(t, EventObject, events[6].Type)
+	assert.Equal(t, ObjectEventApplied, events[6].Object.Type)
+	assert.Equal(t, ref, events[6].Object.Ref)
+}
+
+func TestJsonLinesStatusHandlerPrompt(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	ctx := NewContext(context.Background(), NewJsonLinesStatusHandler(buf, false))
+
+	assert.True(t, ReportPrompt(ctx, "Password: ", true))
+	assert.False(t, ReportPrompt(context.Background(), "Password: ", true))
+
+	events := readJsonLines(t, buf)
+	assert.Len(t, events, 1)
+	assert.Equal(t, EventPrompt, events[0].Type)
+	assert.Equal(t, "Password: ", events[0].Message)
+	assert.True(t, events[0].Password)
+}
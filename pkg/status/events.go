@@ -0,0 +1,50 @@
+package status
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"time"
+)
+
+type ObjectEventType string
+
+const (
+	ObjectEventApplied     ObjectEventType = "applied"
+	ObjectEventApplyFailed ObjectEventType = "apply-failed"
+	ObjectEventReady       ObjectEventType = "ready"
+	ObjectEventWaitFailed  ObjectEventType = "wait-failed"
+)
+
+// ObjectEvent describes the outcome of a single object operation, e.g. applying an object or waiting for its readiness.
+type ObjectEvent struct {
+	Type     ObjectEventType `json:"type"`
+	Ref      k8s.ObjectRef   `json:"ref"`
+	Hook     bool            `json:"hook,omitempty"`
+	Duration time.Duration   `json:"-"`
+}
+
+// EventHandler can optionally be implemented by a StatusHandler to receive structured events. Handlers that don't
+// implement it only receive deprecations as warning messages and don't get any object events at all.
+type EventHandler interface {
+	Deprecation(key string, message string)
+	ObjectEvent(e ObjectEvent)
+	Prompt(message string, password bool)
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelTrace:
+		return "trace"
+	case LevelInfo:
+		return "info"
+	case LevelWarning:
+		return "warning"
+	case LevelError:
+		return "error"
+	case LevelProgress:
+		return "progress"
+	case LevelPrompt:
+		return "prompt"
+	default:
+		return "unknown"
+	}
+}
@@ -30,7 +30,7 @@ const (
 )
 
 const (
-	LevelTrace = iota
+	LevelTrace Level = iota
 	LevelInfo
 	LevelWarning
 	LevelError
@@ -313,10 +313,31 @@ func Errorf(ctx context.Context, status string, args ...any) {
 func Deprecation(ctx context.Context, key string, message string) {
 	cv := getContextValue(ctx)
 	cv.deprecationOnce.Do(key, func() {
-		cv.slh.Message(LevelWarning, message)
+		if eh, ok := cv.slh.(EventHandler); ok {
+			eh.Deprecation(key, message)
+		} else {
+			cv.slh.Message(LevelWarning, message)
+		}
 	})
 }
 
+// ReportPrompt forwards the prompt to the status handler if it implements EventHandler. It returns false if the
+// status handler can't report prompts.
+func ReportPrompt(ctx context.Context, message string, password bool) bool {
+	if eh, ok := FromContext(ctx).(EventHandler); ok {
+		eh.Prompt(message, password)
+		return true
+	}
+	return false
+}
+
+// ReportObjectEvent forwards the object event to the status handler if it implements EventHandler.
+func ReportObjectEvent(ctx context.Context, e ObjectEvent) {
+	if eh, ok := FromContext(ctx).(EventHandler); ok {
+		eh.ObjectEvent(e)
+	}
+}
+
 func Flush(ctx context.Context) {
 	slh := FromContext(ctx)
 	slh.Flush()
@@ -0,0 +1,127 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/kluctl/kluctl/v2/pkg/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"io"
+	"os"
+)
+
+const (
+	ExporterOtlpGrpc = "otlp-grpc"
+	ExporterOtlpHttp = "otlp-http"
+	ExporterStderr   = "stderr"
+	ExporterFile     = "file"
+)
+
+type Options struct {
+	// Exporter is one of the Exporter* constants. If empty, tracing stays disabled.
+	Exporter string
+
+	// Endpoint overrides the endpoint of the OTLP exporters. If empty, the standard OTEL_EXPORTER_OTLP_* environment
+	// variables are used.
+	Endpoint string
+	// Insecure disables TLS for the OTLP exporters.
+	Insecure bool
+
+	// File is the path spans are written to when the file exporter is used.
+	File string
+	// Stderr is the writer used by the stderr exporter. It defaults to os.Stderr.
+	Stderr io.Writer
+
+	ServiceName string
+}
+
+// Setup configures the global tracer provider according to the given options. The returned function must be called
+// before the process exits to flush all pending spans.
+func Setup(ctx context.Context, opts Options) (func(ctx context.Context) error, error) {
+	if opts.Exporter == "" {
+		return func(ctx context.Context) error {
+			return nil
+		}, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var closeFile *os.File
+	var err error
+	switch opts.Exporter {
+	case ExporterOtlpGrpc:
+		var o []otlptracegrpc.Option
+		if opts.Endpoint != "" {
+			o = append(o, otlptracegrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			o = append(o, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, o...)
+	case ExporterOtlpHttp:
+		var o []otlptracehttp.Option
+		if opts.Endpoint != "" {
+			o = append(o, otlptracehttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			o = append(o, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, o...)
+	case ExporterStderr:
+		// never write to stdout, as spans would then get mixed with the output of commands
+		out := opts.Stderr
+		if out == nil {
+			out = os.Stderr
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(out), stdouttrace.WithPrettyPrint())
+	case ExporterFile:
+		if opts.File == "" {
+			return nil, fmt.Errorf("a file must be specified when using the %s trace exporter", ExporterFile)
+		}
+		closeFile, err = os.Create(opts.File)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(closeFile))
+	default:
+		return nil, fmt.Errorf("invalid trace exporter %s", opts.Exporter)
+	}
+	if err != nil {
+		if closeFile != nil {
+			_ = closeFile.Close()
+		}
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", opts.Exporter, err)
+	}
+
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = "kluctl"
+	}
+	resource, err := sdkresource.Merge(sdkresource.Default(), sdkresource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", version.GetVersion()),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		err := tp.Shutdown(ctx)
+		if closeFile != nil {
+			_ = closeFile.Close()
+		}
+		return err
+	}, nil
+}
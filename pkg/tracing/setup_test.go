@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetupNoExporter(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{})
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestSetupInvalidExporter(t *testing.T) {
+	_, err := Setup(context.Background(), Options{Exporter: "invalid"})
+	assert.ErrorContains(t, err, "invalid trace exporter")
+
+	_, err = Setup(context.Background(), Options{Exporter: ExporterFile})
+	assert.Error(t, err)
+}
+
+func TestSetupFileExporter(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "trace.json")
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterFile, File: p})
+	assert.NoError(t, err)
+
+	ctx, span := Start(context.Background(), "test-span")
+	traceId := TraceId(ctx)
+	assert.NotEmpty(t, traceId)
+	End(span, fmt.Errorf("test error"))
+
+	assert.NoError(t, shutdown(context.Background()))
+
+	b, err := os.ReadFile(p)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"Name":"test-span"`)
+	assert.Contains(t, string(b), traceId)
+	assert.Contains(t, string(b), "test error")
+}
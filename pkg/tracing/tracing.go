@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/kluctl/kluctl/v2"
+
+// Start starts a new span as child of the span found in ctx (if any). If tracing was not set up via Setup, this will
+// return a no-op span.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends the span and marks it as failed if err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ObjectRefAttributes returns the span attributes used to identify a Kubernetes object.
+func ObjectRefAttributes(ref k8s.ObjectRef) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("k8s.object.group", ref.Group),
+		attribute.String("k8s.object.kind", ref.Kind),
+		attribute.String("k8s.object.namespace", ref.Namespace),
+		attribute.String("k8s.object.name", ref.Name),
+	}
+}
+
+// TraceId returns the id of the trace found in ctx or an empty string if there is no recording trace.
+func TraceId(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
@@ -19,6 +19,41 @@ type HelmChartConfig2 struct {
 	SkipCRDs          bool    `json:"skipCRDs,omitempty"`
 	SkipUpdate        bool    `json:"skipUpdate,omitempty"`
 	SkipPrePull       bool    `json:"skipPrePull,omitempty"`
+	EnableLookup      *bool   `json:"enableLookup,omitempty"`
+	LookupFixture     string  `json:"lookupFixture,omitempty"`
+
+	PostRenderer *HelmPostRendererConfig `json:"postRenderer,omitempty"`
+	Verify       *HelmVerifyConfig       `json:"verify,omitempty"`
+}
+
+type HelmVerifyConfig struct {
+	Keyring   string `json:"keyring,omitempty"`
+	CosignKey string `json:"cosignKey,omitempty"`
+}
+
+type HelmPostRendererConfig struct {
+	Exec    *HelmExecPostRendererConfig `json:"exec,omitempty"`
+	Patches []HelmPatchConfig           `json:"patches,omitempty"`
+}
+
+type HelmExecPostRendererConfig struct {
+	Command string   `json:"command" validate:"required"`
+	Args    []string `json:"args,omitempty"`
+}
+
+type HelmPatchConfig struct {
+	Patch  string                 `json:"patch" validate:"required"`
+	Target *HelmPatchTargetConfig `json:"target,omitempty"`
+}
+
+type HelmPatchTargetConfig struct {
+	Group              string `json:"group,omitempty"`
+	Version            string `json:"version,omitempty"`
+	Kind               string `json:"kind,omitempty"`
+	Name               string `json:"name,omitempty"`
+	Namespace          string `json:"namespace,omitempty"`
+	LabelSelector      string `json:"labelSelector,omitempty"`
+	AnnotationSelector string `json:"annotationSelector,omitempty"`
 }
 
 func ValidateHelmChartConfig2(sl validator.StructLevel) {
@@ -50,6 +85,18 @@ func ValidateHelmChartConfig2(sl validator.StructLevel) {
 		if c.UpdateConstraints != nil {
 			sl.ReportError("self", "updateConstraints", "updateConstraints", "updateConstraints can not be specified for local Helm charts", "")
 		}
+		if c.Verify != nil {
+			sl.ReportError("self", "verify", "verify", "verify can not be specified for local Helm charts", "")
+		}
+	}
+	if c.Verify != nil {
+		if c.Verify.Keyring == "" && c.Verify.CosignKey == "" {
+			sl.ReportError("self", "verify", "verify", "one of keyring or cosignKey must be specified", "")
+		} else if c.Verify.Keyring != "" && c.Verify.CosignKey != "" {
+			sl.ReportError("self", "verify", "verify", "only one of keyring or cosignKey can be specified", "")
+		} else if c.Verify.CosignKey != "" && c.Repo != "" && !registry.IsOCI(c.Repo) {
+			sl.ReportError("self", "verify", "verify", "cosignKey can only be specified when repo is a OCI url", "")
+		}
 	}
 }
 
@@ -27,7 +27,7 @@ type Target struct {
 	Args          *uo.UnstructuredObject `json:"args,omitempty"`
 	SealingConfig *SealingConfig         `json:"sealingConfig,omitempty"`
 	Aws           *AwsConfig             `json:"aws,omitempty"`
-	Images        []FixedImage           `json:"images,omitempty"`
+	Images        []UpdatableFixedImage  `json:"images,omitempty"`
 	Discriminator string                 `json:"discriminator,omitempty"`
 }
 
@@ -52,12 +52,17 @@ type SecretsConfig struct {
 	SecretSets    []SecretSet                `json:"secretSets,omitempty"`
 }
 
+type HelmProjectConfig struct {
+	RequireVerification bool `json:"requireVerification,omitempty"`
+}
+
 type KluctlProject struct {
-	Targets       []*Target        `json:"targets,omitempty"`
-	Args          []*DeploymentArg `json:"args,omitempty"`
-	SecretsConfig *SecretsConfig   `json:"secretsConfig,omitempty"`
-	Discriminator string           `json:"discriminator,omitempty"`
-	Aws           *AwsConfig       `json:"aws,omitempty"`
+	Targets       []*Target          `json:"targets,omitempty"`
+	Args          []*DeploymentArg   `json:"args,omitempty"`
+	SecretsConfig *SecretsConfig     `json:"secretsConfig,omitempty"`
+	Discriminator string             `json:"discriminator,omitempty"`
+	Aws           *AwsConfig         `json:"aws,omitempty"`
+	Helm          *HelmProjectConfig `json:"helm,omitempty"`
 }
 
 type KluctlLibraryProject struct {
@@ -0,0 +1,54 @@
+package result
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	AuditSourceWebui      = "webui"
+	AuditSourceApi        = "api"
+	AuditSourceCli        = "cli"
+	AuditSourceAnnotation = "annotation"
+)
+
+const (
+	AuditActionReconcile = "reconcile"
+	AuditActionDeploy    = "deploy"
+	AuditActionPrune     = "prune"
+	AuditActionValidate  = "validate"
+	AuditActionDiff      = "diff"
+	AuditActionSuspend   = "suspend"
+	AuditActionResume    = "resume"
+	AuditActionApprove   = "approve"
+)
+
+// AuditEvent records an action that was performed on a KluctlDeployment, e.g. a manual deployment request or the
+// suspension of a deployment.
+type AuditEvent struct {
+	Id   string      `json:"id"`
+	Time metav1.Time `json:"time"`
+
+	// User is the user that performed the action, as authenticated by the component that recorded the event, e.g. the
+	// webui or the kluctl CLI. It is empty for manual requests, which are recorded by the controller.
+	User   string `json:"user,omitempty"`
+	Source string `json:"source"`
+	Action string `json:"action"`
+
+	// ClaimedUser and ClaimedSource are taken from the manual request annotation. Everyone who is allowed to modify the
+	// KluctlDeployment can set arbitrary values there, so these are not verified in any way.
+	ClaimedUser   string `json:"claimedUser,omitempty"`
+	ClaimedSource string `json:"claimedSource,omitempty"`
+	// FieldManager is the field manager that wrote the manual request annotation, as recorded by the API server in the
+	// managedFields of the KluctlDeployment.
+	FieldManager string `json:"fieldManager,omitempty"`
+
+	KluctlDeployment KluctlDeploymentInfo `json:"kluctlDeployment"`
+	ProjectKey       *ProjectKey          `json:"projectKey,omitempty"`
+	TargetKey        *TargetKey           `json:"targetKey,omitempty"`
+
+	RequestValue    string `json:"requestValue,omitempty"`
+	ObjectsHash     string `json:"objectsHash,omitempty"`
+	ReconcileId     string `json:"reconcileId,omitempty"`
+	CommandResultId string `json:"commandResultId,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
@@ -26,6 +26,22 @@ type DeploymentError struct {
 	Message string        `json:"message"`
 }
 
+// HelmLookup records an object that was looked up via the Helm "lookup" template function while rendering a Helm Chart
+type HelmLookup struct {
+	ReleaseName string        `json:"releaseName"`
+	Ref         k8s.ObjectRef `json:"ref"`
+	Found       bool          `json:"found,omitempty"`
+}
+
+// SensitiveField records a field of a rendered object that contains a value originating from a sensitive vars source.
+// The value itself is never recorded.
+type SensitiveField struct {
+	Ref        k8s.ObjectRef `json:"ref"`
+	JsonPath   string        `json:"jsonPath"`
+	VarPath    string        `json:"varPath"`
+	VarsSource string        `json:"varsSource,omitempty"`
+}
+
 type KluctlDeploymentInfo struct {
 	Name      string `json:"name"`
 	Namespace string `json:"namespace"`
@@ -93,6 +109,9 @@ type CommandInfo struct {
 	ExcludeTags           []string               `json:"excludeTags,omitempty"`
 	IncludeDeploymentDirs []string               `json:"includeDeploymentDirs,omitempty"`
 	ExcludeDeploymentDirs []string               `json:"excludeDeploymentDirs,omitempty"`
+
+	// TraceId is the OpenTelemetry trace id of the command, if tracing was enabled
+	TraceId string `json:"traceId,omitempty"`
 }
 
 type GitInfo struct {
@@ -115,6 +134,7 @@ type BaseObject struct {
 	Orphan  bool `json:"orphan,omitempty"`
 	Deleted bool `json:"deleted,omitempty"`
 	Hook    bool `json:"hook,omitempty"`
+	Skipped bool `json:"skipped,omitempty"`
 }
 
 type ResultObject struct {
@@ -144,6 +164,9 @@ type CommandResult struct {
 	Errors     []DeploymentError  `json:"errors,omitempty"`
 	Warnings   []DeploymentError  `json:"warnings,omitempty"`
 	SeenImages []types.FixedImage `json:"seenImages,omitempty"`
+
+	HelmLookups     []HelmLookup     `json:"helmLookups,omitempty"`
+	SensitiveFields []SensitiveField `json:"sensitiveFields,omitempty"`
 }
 
 func (cr *CommandResult) ToCompacted() *CompactedCommandResult {
@@ -0,0 +1,34 @@
+package result
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+)
+
+// ImageChange describes an image that was resolved differently in two command results. OldResultImage is empty if the
+// image was not seen in the old command result and NewResultImage is empty if it was not seen in the new one.
+type ImageChange struct {
+	Image          string         `json:"image"`
+	Object         *k8s.ObjectRef `json:"object,omitempty"`
+	Container      string         `json:"container,omitempty"`
+	OldResultImage string         `json:"oldResultImage,omitempty"`
+	NewResultImage string         `json:"newResultImage,omitempty"`
+}
+
+// CommandResultComparison describes the differences between two command results, e.g. two deployments of the same
+// target. Only rendered objects are compared, meaning that changes done on the cluster side are not included.
+type CommandResultComparison struct {
+	OldResultId string `json:"oldResultId"`
+	NewResultId string `json:"newResultId"`
+
+	OldGitInfo GitInfo `json:"oldGitInfo"`
+	NewGitInfo GitInfo `json:"newGitInfo"`
+
+	// VarsChanges contains the changes of the args and of the rendered vars. Vars loaded from sensitive sources are
+	// not compared.
+	VarsChanges  []Change      `json:"varsChanges,omitempty"`
+	ImageChanges []ImageChange `json:"imageChanges,omitempty"`
+
+	AddedObjects   []k8s.ObjectRef `json:"addedObjects,omitempty"`
+	RemovedObjects []k8s.ObjectRef `json:"removedObjects,omitempty"`
+	ChangedObjects []ChangedObject `json:"changedObjects,omitempty"`
+}
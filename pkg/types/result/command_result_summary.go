@@ -31,6 +31,10 @@ type CommandResultSummary struct {
 	Warnings []DeploymentError `json:"warnings"`
 
 	TotalChanges int `json:"totalChanges"`
+
+	// Pinned is true if the command result was pinned, meaning that it is never deleted by retention. It is not part of
+	// the stored summary, but set by the result store when listing results.
+	Pinned bool `json:"pinned,omitempty"`
 }
 
 func (cr *CommandResult) BuildSummary() *CommandResultSummary {
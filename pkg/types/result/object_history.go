@@ -0,0 +1,34 @@
+package result
+
+import (
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ObjectHistoryEventCreated  = "created"
+	ObjectHistoryEventChanged  = "changed"
+	ObjectHistoryEventDrifted  = "drifted"
+	ObjectHistoryEventOrphaned = "orphaned"
+	ObjectHistoryEventDeleted  = "deleted"
+)
+
+// ObjectHistoryEntry describes what happened to a single object in a single command result.
+type ObjectHistoryEntry struct {
+	ResultId         string                `json:"resultId"`
+	ProjectKey       ProjectKey            `json:"projectKey"`
+	TargetKey        TargetKey             `json:"targetKey"`
+	KluctlDeployment *KluctlDeploymentInfo `json:"kluctlDeployment,omitempty"`
+	Command          string                `json:"command,omitempty"`
+	Initiator        CommandInitiator      `json:"initiator"`
+	StartTime        metav1.Time           `json:"startTime"`
+	GitInfo          GitInfo               `json:"gitInfo"`
+
+	Ref k8s.ObjectRef `json:"ref"`
+
+	// Event is one of created, changed, drifted, orphaned or deleted. An object is considered drifted if it was changed
+	// while its rendered version stayed the same as in the previous command result, meaning that the change only
+	// reverted modifications done on the cluster side.
+	Event   string   `json:"event"`
+	Changes []Change `json:"changes,omitempty"`
+}
@@ -12,6 +12,14 @@ type ValidateResultEntry struct {
 	Message    string        `json:"message"`
 }
 
+// HelmTestResult is the result of a single Helm Chart test hook (helm.sh/hook: test)
+type HelmTestResult struct {
+	ReleaseName string        `json:"releaseName"`
+	Ref         k8s.ObjectRef `json:"ref"`
+	Succeeded   bool          `json:"succeeded"`
+	Logs        string        `json:"logs,omitempty"`
+}
+
 type ValidateResult struct {
 	Id                  string                 `json:"id"`
 	ReconcileId         string                 `json:"reconcileId"`
@@ -26,6 +34,7 @@ type ValidateResult struct {
 	Warnings            []DeploymentError      `json:"warnings,omitempty"`
 	Errors              []DeploymentError      `json:"errors,omitempty"`
 	Results             []ValidateResultEntry  `json:"results,omitempty"`
+	HelmTests           []HelmTestResult       `json:"helmTests,omitempty"`
 }
 
 type ValidateResultSummary struct {
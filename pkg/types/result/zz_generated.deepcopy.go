@@ -22,9 +22,37 @@ package result
 
 import (
 	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditEvent) DeepCopyInto(out *AuditEvent) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	out.KluctlDeployment = in.KluctlDeployment
+	if in.ProjectKey != nil {
+		in, out := &in.ProjectKey, &out.ProjectKey
+		*out = new(ProjectKey)
+		**out = **in
+	}
+	if in.TargetKey != nil {
+		in, out := &in.TargetKey, &out.TargetKey
+		*out = new(TargetKey)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditEvent.
+func (in *AuditEvent) DeepCopy() *AuditEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BaseObject) DeepCopyInto(out *BaseObject) {
 	*out = *in
@@ -206,6 +234,16 @@ func (in *CommandResult) DeepCopyInto(out *CommandResult) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.HelmLookups != nil {
+		in, out := &in.HelmLookups, &out.HelmLookups
+		*out = make([]HelmLookup, len(*in))
+		copy(*out, *in)
+	}
+	if in.SensitiveFields != nil {
+		in, out := &in.SensitiveFields, &out.SensitiveFields
+		*out = make([]SensitiveField, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandResult.
@@ -218,6 +256,54 @@ func (in *CommandResult) DeepCopy() *CommandResult {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CommandResultComparison) DeepCopyInto(out *CommandResultComparison) {
+	*out = *in
+	in.OldGitInfo.DeepCopyInto(&out.OldGitInfo)
+	in.NewGitInfo.DeepCopyInto(&out.NewGitInfo)
+	if in.VarsChanges != nil {
+		in, out := &in.VarsChanges, &out.VarsChanges
+		*out = make([]Change, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImageChanges != nil {
+		in, out := &in.ImageChanges, &out.ImageChanges
+		*out = make([]ImageChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AddedObjects != nil {
+		in, out := &in.AddedObjects, &out.AddedObjects
+		*out = make([]k8s.ObjectRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemovedObjects != nil {
+		in, out := &in.RemovedObjects, &out.RemovedObjects
+		*out = make([]k8s.ObjectRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChangedObjects != nil {
+		in, out := &in.ChangedObjects, &out.ChangedObjects
+		*out = make([]ChangedObject, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CommandResultComparison.
+func (in *CommandResultComparison) DeepCopy() *CommandResultComparison {
+	if in == nil {
+		return nil
+	}
+	out := new(CommandResultComparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommandResultSummary) DeepCopyInto(out *CommandResultSummary) {
 	*out = *in
@@ -411,6 +497,58 @@ func (in *GitInfo) DeepCopy() *GitInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmLookup) DeepCopyInto(out *HelmLookup) {
+	*out = *in
+	out.Ref = in.Ref
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmLookup.
+func (in *HelmLookup) DeepCopy() *HelmLookup {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmLookup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmTestResult) DeepCopyInto(out *HelmTestResult) {
+	*out = *in
+	out.Ref = in.Ref
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmTestResult.
+func (in *HelmTestResult) DeepCopy() *HelmTestResult {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmTestResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageChange) DeepCopyInto(out *ImageChange) {
+	*out = *in
+	if in.Object != nil {
+		in, out := &in.Object, &out.Object
+		*out = new(k8s.ObjectRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageChange.
+func (in *ImageChange) DeepCopy() *ImageChange {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KluctlDeploymentInfo) DeepCopyInto(out *KluctlDeploymentInfo) {
 	*out = *in
@@ -426,6 +564,38 @@ func (in *KluctlDeploymentInfo) DeepCopy() *KluctlDeploymentInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectHistoryEntry) DeepCopyInto(out *ObjectHistoryEntry) {
+	*out = *in
+	out.ProjectKey = in.ProjectKey
+	out.TargetKey = in.TargetKey
+	if in.KluctlDeployment != nil {
+		in, out := &in.KluctlDeployment, &out.KluctlDeployment
+		*out = new(KluctlDeploymentInfo)
+		**out = **in
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.GitInfo.DeepCopyInto(&out.GitInfo)
+	out.Ref = in.Ref
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]Change, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectHistoryEntry.
+func (in *ObjectHistoryEntry) DeepCopy() *ObjectHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectKey) DeepCopyInto(out *ProjectKey) {
 	*out = *in
@@ -470,6 +640,22 @@ func (in *ResultObject) DeepCopy() *ResultObject {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SensitiveField) DeepCopyInto(out *SensitiveField) {
+	*out = *in
+	out.Ref = in.Ref
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SensitiveField.
+func (in *SensitiveField) DeepCopy() *SensitiveField {
+	if in == nil {
+		return nil
+	}
+	out := new(SensitiveField)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetKey) DeepCopyInto(out *TargetKey) {
 	*out = *in
@@ -516,6 +702,11 @@ func (in *ValidateResult) DeepCopyInto(out *ValidateResult) {
 		*out = make([]ValidateResultEntry, len(*in))
 		copy(*out, *in)
 	}
+	if in.HelmTests != nil {
+		in, out := &in.HelmTests, &out.HelmTests
+		*out = make([]HelmTestResult, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidateResult.
@@ -19,8 +19,32 @@ type FixedImage struct {
 	DeploymentDir *string        `json:"deploymentDir,omitempty"`
 }
 
+// UpdatableFixedImage is a FixedImage with an optional update policy. It is used for fixed images specified in
+// .kluctl.yaml targets and fixed images files, which are the places where 'kluctl images update' looks for update
+// policies. The update policy is ignored everywhere else, so FixedImage must be used when no update is possible.
+type UpdatableFixedImage struct {
+	FixedImage `json:",inline"`
+
+	UpdatePolicy *ImageUpdatePolicy `json:"updatePolicy,omitempty"`
+}
+
+type ImageUpdatePolicy struct {
+	Semver  *string `json:"semver,omitempty"`
+	Regex   *string `json:"regex,omitempty"`
+	Numeric *string `json:"numeric,omitempty"`
+}
+
 type FixedImagesConfig struct {
-	Images []FixedImage `json:"images,omitempty"`
+	Images []UpdatableFixedImage `json:"images,omitempty"`
+}
+
+// ToFixedImages converts the given updatable fixed images into plain fixed images, dropping the update policies.
+func ToFixedImages(l []UpdatableFixedImage) []FixedImage {
+	ret := make([]FixedImage, 0, len(l))
+	for _, fi := range l {
+		ret = append(ret, fi.FixedImage)
+	}
+	return ret
 }
 
 func ValidateFixedImage(sl validator.StructLevel) {
@@ -32,6 +56,19 @@ func ValidateFixedImage(sl validator.StructLevel) {
 	}
 }
 
+func ValidateImageUpdatePolicy(sl validator.StructLevel) {
+	s := sl.Current().Interface().(ImageUpdatePolicy)
+	if s.Semver == nil && s.Regex == nil && s.Numeric == nil {
+		sl.ReportError(s, "self", "self", "one of semver, regex or numeric must be set", "")
+	} else if s.Semver != nil && s.Numeric != nil {
+		sl.ReportError(s, "self", "self", "only one of semver or numeric can be set", "")
+	}
+	if s.Numeric != nil && *s.Numeric != "asc" && *s.Numeric != "desc" {
+		sl.ReportError(s.Numeric, "numeric", "numeric", "numeric must be 'asc' or 'desc'", "")
+	}
+}
+
 func init() {
 	yaml.Validator.RegisterStructValidation(ValidateFixedImage, FixedImage{})
+	yaml.Validator.RegisterStructValidation(ValidateImageUpdatePolicy, ImageUpdatePolicy{})
 }
@@ -319,7 +319,7 @@ func (in *FixedImagesConfig) DeepCopyInto(out *FixedImagesConfig) {
 	*out = *in
 	if in.Images != nil {
 		in, out := &in.Images, &out.Images
-		*out = make([]FixedImage, len(*in))
+		*out = make([]UpdatableFixedImage, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -451,6 +451,21 @@ func (in *HelmChartConfig2) DeepCopyInto(out *HelmChartConfig2) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.EnableLookup != nil {
+		in, out := &in.EnableLookup, &out.EnableLookup
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PostRenderer != nil {
+		in, out := &in.PostRenderer, &out.PostRenderer
+		*out = new(HelmPostRendererConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Verify != nil {
+		in, out := &in.Verify, &out.Verify
+		*out = new(HelmVerifyConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmChartConfig2.
@@ -463,6 +478,118 @@ func (in *HelmChartConfig2) DeepCopy() *HelmChartConfig2 {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmExecPostRendererConfig) DeepCopyInto(out *HelmExecPostRendererConfig) {
+	*out = *in
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmExecPostRendererConfig.
+func (in *HelmExecPostRendererConfig) DeepCopy() *HelmExecPostRendererConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmExecPostRendererConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmPatchConfig) DeepCopyInto(out *HelmPatchConfig) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(HelmPatchTargetConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmPatchConfig.
+func (in *HelmPatchConfig) DeepCopy() *HelmPatchConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmPatchConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmPatchTargetConfig) DeepCopyInto(out *HelmPatchTargetConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmPatchTargetConfig.
+func (in *HelmPatchTargetConfig) DeepCopy() *HelmPatchTargetConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmPatchTargetConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmPostRendererConfig) DeepCopyInto(out *HelmPostRendererConfig) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(HelmExecPostRendererConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Patches != nil {
+		in, out := &in.Patches, &out.Patches
+		*out = make([]HelmPatchConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmPostRendererConfig.
+func (in *HelmPostRendererConfig) DeepCopy() *HelmPostRendererConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmPostRendererConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmProjectConfig) DeepCopyInto(out *HelmProjectConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmProjectConfig.
+func (in *HelmProjectConfig) DeepCopy() *HelmProjectConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmProjectConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmVerifyConfig) DeepCopyInto(out *HelmVerifyConfig) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmVerifyConfig.
+func (in *HelmVerifyConfig) DeepCopy() *HelmVerifyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmVerifyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IgnoreForDiffItemConfig) DeepCopyInto(out *IgnoreForDiffItemConfig) {
 	*out = *in
@@ -508,6 +635,36 @@ func (in *IgnoreForDiffItemConfig) DeepCopy() *IgnoreForDiffItemConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageUpdatePolicy) DeepCopyInto(out *ImageUpdatePolicy) {
+	*out = *in
+	if in.Semver != nil {
+		in, out := &in.Semver, &out.Semver
+		*out = new(string)
+		**out = **in
+	}
+	if in.Regex != nil {
+		in, out := &in.Regex, &out.Regex
+		*out = new(string)
+		**out = **in
+	}
+	if in.Numeric != nil {
+		in, out := &in.Numeric, &out.Numeric
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageUpdatePolicy.
+func (in *ImageUpdatePolicy) DeepCopy() *ImageUpdatePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageUpdatePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KluctlLibraryProject) DeepCopyInto(out *KluctlLibraryProject) {
 	*out = *in
@@ -569,6 +726,11 @@ func (in *KluctlProject) DeepCopyInto(out *KluctlProject) {
 		*out = new(AwsConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Helm != nil {
+		in, out := &in.Helm, &out.Helm
+		*out = new(HelmProjectConfig)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KluctlProject.
@@ -791,7 +953,7 @@ func (in *Target) DeepCopyInto(out *Target) {
 	}
 	if in.Images != nil {
 		in, out := &in.Images, &out.Images
-		*out = make([]FixedImage, len(*in))
+		*out = make([]UpdatableFixedImage, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -808,6 +970,27 @@ func (in *Target) DeepCopy() *Target {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpdatableFixedImage) DeepCopyInto(out *UpdatableFixedImage) {
+	*out = *in
+	in.FixedImage.DeepCopyInto(&out.FixedImage)
+	if in.UpdatePolicy != nil {
+		in, out := &in.UpdatePolicy, &out.UpdatePolicy
+		*out = new(ImageUpdatePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpdatableFixedImage.
+func (in *UpdatableFixedImage) DeepCopy() *UpdatableFixedImage {
+	if in == nil {
+		return nil
+	}
+	out := new(UpdatableFixedImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VarSourceAzureKeyVault) DeepCopyInto(out *VarSourceAzureKeyVault) {
 	*out = *in
@@ -137,6 +137,13 @@ func (uo *UnstructuredObject) SetK8sLabel(name string, value string) {
 	}
 }
 
+func (uo *UnstructuredObject) RemoveK8sLabel(name string) {
+	err := uo.RemoveNestedField("metadata", "labels", name)
+	if err != nil {
+		panic(err)
+	}
+}
+
 func (uo *UnstructuredObject) GetK8sLabelsWithRegex(r interface{}) map[string]string {
 	p := uo.getRegexp(r)
 
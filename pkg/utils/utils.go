@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"github.com/jinzhu/copier"
 	"strconv"
+	"unicode/utf8"
 )
 
 func Sha256String(data string) string {
@@ -63,3 +64,14 @@ func StrPtrEquals(s1 *string, s2 *string) bool {
 	}
 	return *s1 == *s2
 }
+
+// TruncateUtf8 truncates s to at most maxLen bytes without splitting multi-byte UTF-8 characters
+func TruncateUtf8(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	for maxLen > 0 && !utf8.RuneStart(s[maxLen]) {
+		maxLen--
+	}
+	return s[:maxLen]
+}
@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTruncateUtf8(t *testing.T) {
+	assert.Equal(t, "abc", TruncateUtf8("abc", 5))
+	assert.Equal(t, "ab", TruncateUtf8("abc", 2))
+	// "ä" is encoded with 2 bytes
+	assert.Equal(t, "a", TruncateUtf8("aäb", 2))
+	assert.Equal(t, "aä", TruncateUtf8("aäb", 3))
+	assert.Equal(t, "", TruncateUtf8("äb", 1))
+}
@@ -16,12 +16,14 @@ import (
 	"github.com/kluctl/kluctl/v2/pkg/sops"
 	"github.com/kluctl/kluctl/v2/pkg/sops/decryptor"
 	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/tracing"
 	"github.com/kluctl/kluctl/v2/pkg/types"
 	k8s2 "github.com/kluctl/kluctl/v2/pkg/types/k8s"
 	"github.com/kluctl/kluctl/v2/pkg/utils"
 	"github.com/kluctl/kluctl/v2/pkg/utils/uo"
 	"github.com/kluctl/kluctl/v2/pkg/vars/vault"
 	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"go.opentelemetry.io/otel/attribute"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"os"
@@ -100,8 +102,35 @@ func (v *VarsLoader) LoadVars(ctx context.Context, varsCtx *VarsCtx, sourceIn *t
 		ignoreMissing = *source.IgnoreMissing
 	}
 
+	ctx, span := tracing.Start(ctx, "load-vars", attribute.String("kluctl.vars_source.type", varsSourceType(&source)))
+	newVars, sensitive, err := v.loadVarsSource(ctx, varsCtx, &source, ignoreMissing, searchDirs, rootKey)
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+
+	if sourceIn.Sensitive != nil {
+		// override the default
+		sensitive = *sourceIn.Sensitive
+	}
+
+	sourceIn.RenderedSensitive = sensitive
+	sourceIn.RenderedVars = newVars.Clone()
+
+	if source.NoOverride == nil || !*source.NoOverride {
+		varsCtx.Vars.Merge(newVars)
+	} else {
+		newVars.Merge(varsCtx.Vars)
+		varsCtx.Vars = newVars
+	}
+
+	return nil
+}
+
+func (v *VarsLoader) loadVarsSource(ctx context.Context, varsCtx *VarsCtx, source *types.VarsSource, ignoreMissing bool, searchDirs []string, rootKey string) (*uo.UnstructuredObject, bool, error) {
 	var newVars *uo.UnstructuredObject
 	var sensitive bool
+	var err error
 	if source.Values != nil {
 		newVars = source.Values
 		if rootKey != "" {
@@ -119,45 +148,55 @@ func (v *VarsLoader) LoadVars(ctx context.Context, varsCtx *VarsCtx, sourceIn *t
 		newVars, err = v.loadFromK8sObject(varsCtx, *source.ClusterSecret, "Secret", ignoreMissing, true)
 		sensitive = true
 	} else if source.SystemEnvVars != nil {
-		newVars, err = v.loadSystemEnvs(varsCtx, &source, ignoreMissing, rootKey)
+		newVars, err = v.loadSystemEnvs(varsCtx, source, ignoreMissing, rootKey)
 		sensitive = true
 	} else if source.Http != nil {
-		newVars, sensitive, err = v.loadHttp(varsCtx, &source, ignoreMissing)
+		newVars, sensitive, err = v.loadHttp(varsCtx, source, ignoreMissing)
 	} else if source.AwsSecretsManager != nil {
-		newVars, err = v.loadAwsSecretsManager(varsCtx, &source, ignoreMissing)
+		newVars, err = v.loadAwsSecretsManager(varsCtx, source, ignoreMissing)
 		sensitive = true
 	} else if source.GcpSecretManager != nil {
-		newVars, err = v.loadGcpSecretManager(varsCtx, &source, ignoreMissing)
+		newVars, err = v.loadGcpSecretManager(varsCtx, source, ignoreMissing)
 		sensitive = true
 	} else if source.Vault != nil {
-		newVars, err = v.loadVault(varsCtx, &source, ignoreMissing)
+		newVars, err = v.loadVault(varsCtx, source, ignoreMissing)
 		sensitive = true
 	} else if source.AzureKeyVault != nil {
-		newVars, err = v.loadAzureKeyVault(varsCtx, &source, ignoreMissing)
+		newVars, err = v.loadAzureKeyVault(varsCtx, source, ignoreMissing)
 		sensitive = true
 	} else {
-		return fmt.Errorf("invalid vars source")
-	}
-	if err != nil {
-		return err
+		return nil, false, fmt.Errorf("invalid vars source")
 	}
+	return newVars, sensitive, err
+}
 
-	if sourceIn.Sensitive != nil {
-		// override the default
-		sensitive = *sourceIn.Sensitive
-	}
-
-	sourceIn.RenderedSensitive = sensitive
-	sourceIn.RenderedVars = newVars.Clone()
-
-	if source.NoOverride == nil || !*source.NoOverride {
-		varsCtx.Vars.Merge(newVars)
-	} else {
-		newVars.Merge(varsCtx.Vars)
-		varsCtx.Vars = newVars
+func varsSourceType(source *types.VarsSource) string {
+	switch {
+	case source.Values != nil:
+		return "values"
+	case source.File != nil:
+		return "file"
+	case source.Git != nil:
+		return "git"
+	case source.ClusterConfigMap != nil:
+		return "clusterConfigMap"
+	case source.ClusterSecret != nil:
+		return "clusterSecret"
+	case source.SystemEnvVars != nil:
+		return "systemEnvVars"
+	case source.Http != nil:
+		return "http"
+	case source.AwsSecretsManager != nil:
+		return "awsSecretsManager"
+	case source.GcpSecretManager != nil:
+		return "gcpSecretManager"
+	case source.Vault != nil:
+		return "vault"
+	case source.AzureKeyVault != nil:
+		return "azureKeyVault"
+	default:
+		return "unknown"
 	}
-
-	return nil
 }
 
 func (v *VarsLoader) mergeVars(varsCtx *VarsCtx, newVars *uo.UnstructuredObject, rootKey string) {
@@ -0,0 +1,166 @@
+package webui
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strings"
+	"time"
+)
+
+const apiTokenPrefix = "kluctl_"
+
+// ApiToken describes a long-lived API token. Only the hash of the token is stored.
+type ApiToken struct {
+	Name      string       `json:"-"`
+	Hash      string       `json:"hash"`
+	Username  string       `json:"username"`
+	IsAdmin   bool         `json:"isAdmin,omitempty"`
+	IsViewer  bool         `json:"isViewer,omitempty"`
+	Groups    []string     `json:"groups,omitempty"`
+	CreatedAt metav1.Time  `json:"createdAt"`
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// NewApiToken generates a new random token and returns it together with the ApiToken that contains its hash.
+func NewApiToken(name string, username string, expiresIn *time.Duration) (string, *ApiToken, error) {
+	if errs := validation.IsConfigMapKey(name); len(errs) != 0 {
+		return "", nil, fmt.Errorf("invalid token name '%s': %s", name, strings.Join(errs, ", "))
+	}
+
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", nil, err
+	}
+	token := apiTokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	if username == "" {
+		username = name
+	}
+
+	t := &ApiToken{
+		Name:      name,
+		Hash:      hashApiToken(token),
+		Username:  username,
+		CreatedAt: metav1.Now(),
+	}
+	if expiresIn != nil {
+		e := metav1.NewTime(t.CreatedAt.Add(*expiresIn))
+		t.ExpiresAt = &e
+	}
+	return token, t, nil
+}
+
+func hashApiToken(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(h[:])
+}
+
+func (t *ApiToken) isExpired() bool {
+	return t.ExpiresAt != nil && time.Now().After(t.ExpiresAt.Time)
+}
+
+func (t *ApiToken) matches(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(t.Hash), []byte(hashApiToken(token))) == 1
+}
+
+func (t *ApiToken) toUser() *User {
+	return &User{
+		Username: t.Username,
+		IsAdmin:  t.IsAdmin,
+		IsViewer: t.IsViewer,
+		Groups:   t.Groups,
+	}
+}
+
+// ListApiTokens reads all API tokens from the given Secret. A missing Secret is treated as if no tokens exist.
+func ListApiTokens(ctx context.Context, c client.Client, namespace string, secretName string) ([]*ApiToken, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, client.ObjectKey{Name: secretName, Namespace: namespace}, &secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ret []*ApiToken
+	for name, b := range secret.Data {
+		var t ApiToken
+		err = json.Unmarshal(b, &t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse API token %s: %w", name, err)
+		}
+		t.Name = name
+		ret = append(ret, &t)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret, nil
+}
+
+// StoreApiToken stores the given API token in the given Secret, creating the Secret if needed. Existing tokens with the
+// same name are not overwritten.
+func StoreApiToken(ctx context.Context, c client.Client, namespace string, secretName string, t *ApiToken, manager string) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	var secret corev1.Secret
+	err = c.Get(ctx, client.ObjectKey{Name: secretName, Namespace: namespace}, &secret)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		secret.Name = secretName
+		secret.Namespace = namespace
+		secret.Data = map[string][]byte{
+			t.Name: b,
+		}
+		return c.Create(ctx, &secret, client.FieldOwner(manager))
+	}
+
+	if _, ok := secret.Data[t.Name]; ok {
+		return fmt.Errorf("API token %s already exists", t.Name)
+	}
+
+	patch := client.MergeFromWithOptions(secret.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[t.Name] = b
+	return c.Patch(ctx, &secret, patch, client.FieldOwner(manager))
+}
+
+// RevokeApiToken removes the API token with the given name from the given Secret.
+func RevokeApiToken(ctx context.Context, c client.Client, namespace string, secretName string, name string, manager string) error {
+	var secret corev1.Secret
+	err := c.Get(ctx, client.ObjectKey{Name: secretName, Namespace: namespace}, &secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return fmt.Errorf("API token %s not found", name)
+		}
+		return err
+	}
+	if _, ok := secret.Data[name]; !ok {
+		return fmt.Errorf("API token %s not found", name)
+	}
+
+	patch := client.MergeFromWithOptions(secret.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	delete(secret.Data, name)
+	return c.Patch(ctx, &secret, patch, client.FieldOwner(manager))
+}
@@ -0,0 +1,109 @@
+package webui
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+	"time"
+)
+
+func TestApiTokensStoreListRevoke(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+
+	token, at, err := NewApiToken("release-bot", "", nil)
+	assert.NoError(t, err)
+	assert.Regexp(t, "^kluctl_", token)
+	assert.Equal(t, "release-bot", at.Username)
+	assert.NotContains(t, at.Hash, token)
+	assert.True(t, at.matches(token))
+	assert.False(t, at.matches(token+"x"))
+
+	err = StoreApiToken(ctx, c, "kluctl-system", "tokens", at, "test")
+	assert.NoError(t, err)
+	err = StoreApiToken(ctx, c, "kluctl-system", "tokens", at, "test")
+	assert.ErrorContains(t, err, "already exists")
+
+	_, at2, err := NewApiToken("other", "other-user", nil)
+	assert.NoError(t, err)
+	at2.Groups = []string{"g1"}
+	err = StoreApiToken(ctx, c, "kluctl-system", "tokens", at2, "test")
+	assert.NoError(t, err)
+
+	tokens, err := ListApiTokens(ctx, c, "kluctl-system", "tokens")
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 2)
+	assert.Equal(t, "other", tokens[0].Name)
+	assert.Equal(t, []string{"g1"}, tokens[0].Groups)
+	assert.Equal(t, "release-bot", tokens[1].Name)
+
+	err = RevokeApiToken(ctx, c, "kluctl-system", "tokens", "release-bot", "test")
+	assert.NoError(t, err)
+	err = RevokeApiToken(ctx, c, "kluctl-system", "tokens", "release-bot", "test")
+	assert.ErrorContains(t, err, "not found")
+
+	tokens, err = ListApiTokens(ctx, c, "kluctl-system", "tokens")
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	tokens, err = ListApiTokens(ctx, c, "kluctl-system", "missing")
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 0)
+}
+
+func TestApiTokenInvalidName(t *testing.T) {
+	_, _, err := NewApiToken("invalid/name", "", nil)
+	assert.ErrorContains(t, err, "invalid token name")
+}
+
+func TestApiTokenAuth(t *testing.T) {
+	ctx := context.Background()
+	c := fake.NewClientBuilder().Build()
+
+	token, at, err := NewApiToken("bot", "", nil)
+	assert.NoError(t, err)
+	at.IsViewer = true
+	assert.NoError(t, StoreApiToken(ctx, c, "kluctl-system", "tokens", at, "test"))
+
+	expiresIn := -time.Minute
+	expiredToken, at2, err := NewApiToken("expired", "", &expiresIn)
+	assert.NoError(t, err)
+	assert.NoError(t, StoreApiToken(ctx, c, "kluctl-system", "tokens", at2, "test"))
+
+	s := &authHandler{
+		ctx:                 ctx,
+		serverClient:        c,
+		controllerNamespace: "kluctl-system",
+		authConfig: AuthConfig{
+			AuthEnabled:         true,
+			ApiTokensSecretName: "tokens",
+		},
+	}
+
+	getUser := func(header string) *User {
+		gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+		gc.Request, _ = http.NewRequest(http.MethodGet, "/api/v1/results", nil)
+		gc.Request.Header.Set("Authorization", header)
+		return s.getUser(gc)
+	}
+
+	u := getUser("Bearer " + token)
+	if assert.NotNil(t, u) {
+		assert.Equal(t, "bot", u.Username)
+		assert.True(t, u.IsViewer)
+		assert.False(t, u.IsAdmin)
+	}
+
+	assert.Nil(t, getUser("Bearer "+expiredToken))
+	assert.Nil(t, getUser("Bearer kluctl_invalid"))
+	assert.Nil(t, getUser("Bearer invalid"))
+
+	// revoked tokens are only detected after the cache expired
+	assert.NoError(t, RevokeApiToken(ctx, c, "kluctl-system", "tokens", "bot", "test"))
+	s.apiTokensLoadTime = time.Time{}
+	assert.Nil(t, getUser("Bearer "+token))
+}
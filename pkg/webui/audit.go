@@ -0,0 +1,126 @@
+package webui
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"time"
+)
+
+type auditEventsParams struct {
+	ClusterId string `form:"clusterId"`
+	Name      string `form:"name"`
+	Namespace string `form:"namespace"`
+	Limit     int    `form:"limit"`
+}
+
+// getAuditSource returns the source to record in audit events. Requests authenticated via API tokens are recorded as
+// API requests.
+func getAuditSource(c *gin.Context) string {
+	if _, ok := getBearerToken(c); ok {
+		return result.AuditSourceApi
+	}
+	return result.AuditSourceWebui
+}
+
+// doModifyKluctlDeploymentAudited works like doModifyKluctlDeployment and records an audit event after the
+// KluctlDeployment has been patched successfully. Manual requests are not recorded here, as the controller records them
+// after they have been processed.
+func (s *CommandResultsServer) doModifyKluctlDeploymentAudited(c *gin.Context, perm Permission, params KluctlDeploymentParam, action string, objectsHash string, update func(obj *kluctlv1.KluctlDeployment) error) bool {
+	kd := s.doModifyKluctlDeployment(c, perm, params.Cluster, params.Name, params.Namespace, update)
+	if kd == nil {
+		return false
+	}
+
+	e := &result.AuditEvent{
+		Id:     uuid.NewString(),
+		Time:   metav1.Now(),
+		Source: getAuditSource(c),
+		Action: action,
+		KluctlDeployment: result.KluctlDeploymentInfo{
+			Name:      kd.Name,
+			Namespace: kd.Namespace,
+			ClusterId: params.Cluster,
+		},
+		ProjectKey:  kd.Status.ProjectKey,
+		TargetKey:   kd.Status.TargetKey,
+		ObjectsHash: objectsHash,
+	}
+	if user := s.auth.getUser(c); user != nil {
+		e.User = user.Username
+	}
+	s.writeAuditEvent(e)
+	return true
+}
+
+func (s *CommandResultsServer) writeAuditEvent(e *result.AuditEvent) {
+	ca := s.cam.getForClusterId(e.KluctlDeployment.ClusterId)
+	if ca == nil {
+		return
+	}
+
+	// audit events are written with the webui's own service account, as users are usually not allowed to write results
+	kc, err := ca.getClient("", nil)
+	if err != nil {
+		status.Warningf(s.ctx, "Failed to write audit event %s: %s", e.Id, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, time.Second*10)
+	defer cancel()
+
+	err = results.WriteAuditEventSecret(ctx, kc, s.auditEventsNamespace, e)
+	if err != nil {
+		status.Warningf(s.ctx, "Failed to write audit event %s: %s", e.Id, err)
+	}
+}
+
+// listAuditEvents returns all audit events matching the given params that the user is allowed to view.
+func (s *CommandResultsServer) listAuditEvents(c *gin.Context, params auditEventsParams) ([]result.AuditEvent, error) {
+	var options results.ListAuditEventsOptions
+	if params.Name != "" || params.Namespace != "" || params.ClusterId != "" {
+		options.KluctlDeployment = &result.KluctlDeploymentInfo{
+			Name:      params.Name,
+			Namespace: params.Namespace,
+			ClusterId: params.ClusterId,
+		}
+	}
+
+	events, err := s.store.ListAuditEvents(options)
+	if err != nil {
+		return nil, err
+	}
+
+	user := s.auth.getUser(c)
+	ret := []result.AuditEvent{}
+	for _, e := range events {
+		if params.Limit > 0 && len(ret) >= params.Limit {
+			break
+		}
+		if ok, _ := s.auth.checkPermission(user, PermissionView, newAuditEventAccessTarget(&e)); !ok {
+			continue
+		}
+		ret = append(ret, e)
+	}
+	return ret, nil
+}
+
+func (s *CommandResultsServer) getAuditEvents(c *gin.Context) {
+	var params auditEventsParams
+	params.ClusterId = c.Query("cluster")
+	params.Name = c.Query("name")
+	params.Namespace = c.Query("namespace")
+
+	events, err := s.listAuditEvents(c, params)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
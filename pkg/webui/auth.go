@@ -10,9 +10,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/kluctl/kluctl/v2/pkg/k8s"
 	"golang.org/x/oauth2"
+	corev1 "k8s.io/api/core/v1"
 	"net/http"
 	"net/url"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sync"
 	"time"
 )
 
@@ -29,6 +31,11 @@ type AuthConfig struct {
 	AdminRbacUser  string
 	ViewerRbacUser string
 
+	RoleBindingsConfigMapName string
+	RoleBindingsConfigMapKey  string
+
+	ApiTokensSecretName string
+
 	OidcIssuerUrl        string
 	OidcDisplayName      string
 	OidcClientId         string
@@ -66,11 +73,19 @@ type authHandler struct {
 	oidcProvider       *oidc.Provider
 	oidcProviderClaims map[string]any
 	oauth2Config       *oauth2.Config
+
+	roleBindings []*RoleBinding
+
+	apiTokensMutex    sync.Mutex
+	apiTokens         []*ApiToken
+	apiTokensLoadTime time.Time
 }
 
 type User struct {
-	Username string `json:"username"`
-	IsAdmin  bool   `json:"isAdmin"`
+	Username string   `json:"username"`
+	IsAdmin  bool     `json:"isAdmin"`
+	IsViewer bool     `json:"isViewer"`
+	Groups   []string `json:"groups,omitempty"`
 }
 
 func newAuthHandler(ctx context.Context, serverClient client.Client, controllerNamespace string, authConfig AuthConfig) (*authHandler, error) {
@@ -108,6 +123,13 @@ func newAuthHandler(ctx context.Context, serverClient client.Client, controllerN
 		ret.viewerPassword = x
 	}
 
+	if authConfig.RoleBindingsConfigMapName != "" {
+		ret.roleBindings, err = ret.loadRoleBindings()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = ret.setupOidcProvider(ctx, authConfig)
 	if err != nil {
 		return nil, err
@@ -116,6 +138,23 @@ func newAuthHandler(ctx context.Context, serverClient client.Client, controllerN
 	return ret, nil
 }
 
+func (s *authHandler) loadRoleBindings() ([]*RoleBinding, error) {
+	var cm corev1.ConfigMap
+	err := s.serverClient.Get(s.ctx, client.ObjectKey{Name: s.authConfig.RoleBindingsConfigMapName, Namespace: s.controllerNamespace}, &cm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get role bindings ConfigMap: %w", err)
+	}
+	data, ok := cm.Data[s.authConfig.RoleBindingsConfigMapKey]
+	if !ok {
+		return nil, fmt.Errorf("ConfigMap %s has no key %s", cm.Name, s.authConfig.RoleBindingsConfigMapKey)
+	}
+	rbs, err := ParseRoleBindings([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse role bindings: %w", err)
+	}
+	return rbs, nil
+}
+
 func (s *authHandler) setupRoutes(router gin.IRouter) error {
 	gob.Register(map[string]interface{}{})
 	gob.Register(oidcTokenInfo{})
@@ -215,6 +254,11 @@ func (s *authHandler) getUser(c *gin.Context) *User {
 		return s.getAdminUser("admin")
 	}
 
+	if token, ok := getBearerToken(c); ok {
+		// API tokens never fall back to session based logins
+		return s.getUserFromApiToken(token)
+	}
+
 	user := s.getStaticUserFromSession(c)
 	if user != nil {
 		return user
@@ -231,14 +275,6 @@ func (s *authHandler) getUser(c *gin.Context) *User {
 	return nil
 }
 
-func (s *authHandler) getRbacUser(user *User) string {
-	if user.IsAdmin {
-		return s.authConfig.AdminRbacUser
-	} else {
-		return s.authConfig.ViewerRbacUser
-	}
-}
-
 func (s *authHandler) authHandler(c *gin.Context) {
 	if !s.authConfig.AuthEnabled {
 		return
@@ -0,0 +1,58 @@
+package webui
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kluctl/kluctl/v2/pkg/status"
+	"strings"
+	"time"
+)
+
+// apiTokensRefreshTime specifies how long API tokens are cached before the Secret is read again. This is also the
+// maximum time it takes for revoked tokens to become invalid.
+const apiTokensRefreshTime = 10 * time.Second
+
+func getBearerToken(c *gin.Context) (string, bool) {
+	h := c.GetHeader("Authorization")
+	if h == "" {
+		return "", false
+	}
+	token, ok := strings.CutPrefix(h, "Bearer ")
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+func (s *authHandler) getUserFromApiToken(token string) *User {
+	if s.authConfig.ApiTokensSecretName == "" || !strings.HasPrefix(token, apiTokenPrefix) {
+		return nil
+	}
+
+	for _, t := range s.getApiTokens() {
+		if t.matches(token) {
+			if t.isExpired() {
+				return nil
+			}
+			return t.toUser()
+		}
+	}
+	return nil
+}
+
+func (s *authHandler) getApiTokens() []*ApiToken {
+	s.apiTokensMutex.Lock()
+	defer s.apiTokensMutex.Unlock()
+
+	if time.Now().Before(s.apiTokensLoadTime.Add(apiTokensRefreshTime)) {
+		return s.apiTokens
+	}
+
+	tokens, err := ListApiTokens(s.ctx, s.serverClient, s.controllerNamespace, s.authConfig.ApiTokensSecretName)
+	if err != nil {
+		status.Warningf(s.ctx, "Failed to load API tokens: %s", err)
+		return s.apiTokens
+	}
+	s.apiTokens = tokens
+	s.apiTokensLoadTime = time.Now()
+	return s.apiTokens
+}
@@ -238,6 +238,7 @@ func (s *authHandler) getUserFromIDToken(idToken *oidc.IDToken) (*User, error) {
 
 	isAdmin := false
 	isViewer := false
+	var groupNames []string
 
 	for _, group := range groups {
 		g, ok := group.(string)
@@ -250,13 +251,24 @@ func (s *authHandler) getUserFromIDToken(idToken *oidc.IDToken) (*User, error) {
 		if utils.FindStrInSlice(s.authConfig.OidcViewersGroups, g) != -1 {
 			isViewer = true
 		}
+		// only remember groups that are relevant for role bindings, as the user ends up in the session cookie
+		if s.isRoleBindingGroup(g) {
+			groupNames = append(groupNames, g)
+		}
 	}
 
+	var user *User
 	if isAdmin {
-		return s.getAdminUser(username), nil
+		user = s.getAdminUser(username)
 	} else if isViewer {
-		return s.getViewerUser(username), nil
+		user = s.getViewerUser(username)
 	} else {
+		user = &User{Username: username}
+	}
+	user.Groups = groupNames
+
+	if !user.IsAdmin && !user.IsViewer && !s.hasAnyRoleBinding(user) {
 		return nil, fmt.Errorf("permission denied")
 	}
+	return user, nil
 }
@@ -0,0 +1,317 @@
+package webui
+
+import (
+	"fmt"
+	"github.com/gobwas/glob"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/kluctl/kluctl/v2/pkg/utils"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"strings"
+)
+
+type Permission string
+
+const (
+	// PermissionView allows to see KluctlDeployments, command results, validation results and controller logs
+	PermissionView Permission = "view"
+	// PermissionDiff allows to see remote and applied objects and to request validations and diffs
+	PermissionDiff Permission = "diff"
+	// PermissionDeploy allows to request reconciliations and deployments
+	PermissionDeploy Permission = "deploy"
+	// PermissionPrune allows to request prunes
+	PermissionPrune Permission = "prune"
+	// PermissionSuspend allows to suspend and resume KluctlDeployments
+	PermissionSuspend Permission = "suspend"
+	// PermissionApprove allows to approve manual deployments
+	PermissionApprove Permission = "approve"
+	// PermissionPin allows to pin and unpin command results
+	PermissionPin Permission = "pin"
+)
+
+var allPermissions = []Permission{
+	PermissionView,
+	PermissionDiff,
+	PermissionDeploy,
+	PermissionPrune,
+	PermissionSuspend,
+	PermissionApprove,
+	PermissionPin,
+}
+
+type RoleBindingsConfig struct {
+	RoleBindings []*RoleBinding `json:"roleBindings"`
+}
+
+// RoleBinding grants permissions to users and OIDC groups. Permissions are only granted for KluctlDeployments and
+// results that match at least one of the scopes. If no scopes are specified, the permissions are granted for everything.
+// Every permission implicitly includes PermissionView.
+type RoleBinding struct {
+	Name        string             `json:"name"`
+	Users       []string           `json:"users,omitempty"`
+	Groups      []string           `json:"groups,omitempty"`
+	Permissions []Permission       `json:"permissions"`
+	Scopes      []RoleBindingScope `json:"scopes,omitempty"`
+
+	// RbacUser specifies the RBAC user to impersonate when modifying KluctlDeployments. Defaults to the admin RBAC user.
+	RbacUser string `json:"rbacUser,omitempty"`
+}
+
+// RoleBindingScope matches KluctlDeployments and results. All fields are glob patterns, while empty fields match
+// everything. All specified fields must match for the scope to match.
+type RoleBindingScope struct {
+	KluctlDeployment *KluctlDeploymentScope `json:"kluctlDeployment,omitempty"`
+	Project          *ProjectScope          `json:"project,omitempty"`
+	Target           *TargetScope           `json:"target,omitempty"`
+
+	globs map[string]glob.Glob
+}
+
+type KluctlDeploymentScope struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+type ProjectScope struct {
+	RepoKey string `json:"repoKey,omitempty"`
+	SubDir  string `json:"subDir,omitempty"`
+}
+
+type TargetScope struct {
+	TargetName string `json:"targetName,omitempty"`
+	ClusterId  string `json:"clusterId,omitempty"`
+}
+
+// accessTarget describes what is being accessed. Nil fields are unknown and never match scopes that require them.
+type accessTarget struct {
+	kd         *result.KluctlDeploymentInfo
+	projectKey *result.ProjectKey
+	targetKey  *result.TargetKey
+}
+
+func newResultAccessTarget(projectKey result.ProjectKey, targetKey result.TargetKey, kd *result.KluctlDeploymentInfo) accessTarget {
+	return accessTarget{
+		kd:         kd,
+		projectKey: &projectKey,
+		targetKey:  &targetKey,
+	}
+}
+
+func newKluctlDeploymentAccessTarget(clusterId string, kd *kluctlv1.KluctlDeployment) accessTarget {
+	return accessTarget{
+		kd: &result.KluctlDeploymentInfo{
+			Name:      kd.Name,
+			Namespace: kd.Namespace,
+			ClusterId: clusterId,
+		},
+		projectKey: kd.Status.ProjectKey,
+		targetKey:  kd.Status.TargetKey,
+	}
+}
+
+func newAuditEventAccessTarget(e *result.AuditEvent) accessTarget {
+	return accessTarget{
+		kd:         &e.KluctlDeployment,
+		projectKey: e.ProjectKey,
+		targetKey:  e.TargetKey,
+	}
+}
+
+func ParseRoleBindings(data []byte) ([]*RoleBinding, error) {
+	var config RoleBindingsConfig
+	err := yaml.ReadYamlBytes(data, &config)
+	if err != nil {
+		return nil, err
+	}
+	for i, rb := range config.RoleBindings {
+		err = rb.validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid role binding %d (%s): %w", i, rb.Name, err)
+		}
+	}
+	return config.RoleBindings, nil
+}
+
+func (rb *RoleBinding) validate() error {
+	if len(rb.Users) == 0 && len(rb.Groups) == 0 {
+		return fmt.Errorf("at least one user or group must be specified")
+	}
+	for _, p := range rb.Permissions {
+		found := false
+		for _, p2 := range allPermissions {
+			if p == p2 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown permission '%s'", p)
+		}
+	}
+	for i := range rb.Scopes {
+		err := rb.Scopes[i].compile()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rb *RoleBinding) appliesToUser(user *User) bool {
+	if utils.FindStrInSlice(rb.Users, user.Username) != -1 {
+		return true
+	}
+	for _, g := range user.Groups {
+		if utils.FindStrInSlice(rb.Groups, g) != -1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (rb *RoleBinding) hasPermission(perm Permission) bool {
+	for _, p := range rb.Permissions {
+		if p == perm {
+			return true
+		}
+	}
+	// every permission implies view access
+	return perm == PermissionView && len(rb.Permissions) != 0
+}
+
+func (rb *RoleBinding) matches(at accessTarget) bool {
+	if len(rb.Scopes) == 0 {
+		return true
+	}
+	for i := range rb.Scopes {
+		if rb.Scopes[i].matches(at) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *RoleBindingScope) compile() error {
+	s.globs = map[string]glob.Glob{}
+	for _, p := range s.patterns() {
+		if p == "" {
+			continue
+		}
+		g, err := glob.Compile(p, '/')
+		if err != nil {
+			return fmt.Errorf("invalid pattern '%s': %w", p, err)
+		}
+		s.globs[p] = g
+	}
+	return nil
+}
+
+func (s *RoleBindingScope) patterns() []string {
+	var ret []string
+	if s.KluctlDeployment != nil {
+		ret = append(ret, s.KluctlDeployment.Namespace, s.KluctlDeployment.Name)
+	}
+	if s.Project != nil {
+		ret = append(ret, s.Project.RepoKey, s.Project.SubDir)
+	}
+	if s.Target != nil {
+		ret = append(ret, s.Target.TargetName, s.Target.ClusterId)
+	}
+	return ret
+}
+
+func (s *RoleBindingScope) matches(at accessTarget) bool {
+	if s.KluctlDeployment != nil {
+		if at.kd == nil {
+			return false
+		}
+		if !s.matchPattern(s.KluctlDeployment.Namespace, at.kd.Namespace) || !s.matchPattern(s.KluctlDeployment.Name, at.kd.Name) {
+			return false
+		}
+	}
+	if s.Project != nil {
+		if at.projectKey == nil {
+			return false
+		}
+		if !s.matchRepoKey(s.Project.RepoKey, at.projectKey.RepoKey) || !s.matchPattern(s.Project.SubDir, at.projectKey.SubDir) {
+			return false
+		}
+	}
+	if s.Target != nil {
+		if at.targetKey == nil {
+			return false
+		}
+		if !s.matchPattern(s.Target.TargetName, at.targetKey.TargetName) || !s.matchPattern(s.Target.ClusterId, at.targetKey.ClusterId) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchRepoKey matches the repo key with and without the type prefix (e.g. git://), so that patterns like
+// github.com/my-org/** work as expected
+func (s *RoleBindingScope) matchRepoKey(pattern string, repoKey types.RepoKey) bool {
+	str := repoKey.String()
+	if s.matchPattern(pattern, str) {
+		return true
+	}
+	x := strings.SplitN(str, "://", 2)
+	return len(x) == 2 && s.matchPattern(pattern, x[1])
+}
+
+func (s *RoleBindingScope) matchPattern(pattern string, v string) bool {
+	if pattern == "" {
+		return true
+	}
+	g, ok := s.globs[pattern]
+	if !ok {
+		// scope was not compiled, which means it was not loaded via ParseRoleBindings
+		return false
+	}
+	return g.Match(v)
+}
+
+// checkPermission checks if the given user has the given permission for the given access target. If so, it also
+// returns the RBAC user to be used when modifying the accessed object.
+func (s *authHandler) checkPermission(user *User, perm Permission, at accessTarget) (bool, string) {
+	if user == nil {
+		return false, ""
+	}
+	if user.IsAdmin {
+		return true, s.authConfig.AdminRbacUser
+	}
+	if perm == PermissionView && user.IsViewer {
+		return true, s.authConfig.ViewerRbacUser
+	}
+	for _, rb := range s.roleBindings {
+		if !rb.appliesToUser(user) || !rb.hasPermission(perm) || !rb.matches(at) {
+			continue
+		}
+		rbacUser := rb.RbacUser
+		if rbacUser == "" {
+			rbacUser = s.authConfig.AdminRbacUser
+		}
+		return true, rbacUser
+	}
+	return false, ""
+}
+
+// hasAnyRoleBinding returns true if at least one role binding applies to the given user
+func (s *authHandler) hasAnyRoleBinding(user *User) bool {
+	for _, rb := range s.roleBindings {
+		if rb.appliesToUser(user) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *authHandler) isRoleBindingGroup(group string) bool {
+	for _, rb := range s.roleBindings {
+		if utils.FindStrInSlice(rb.Groups, group) != -1 {
+			return true
+		}
+	}
+	return false
+}
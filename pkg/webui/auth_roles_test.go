@@ -0,0 +1,146 @@
+package webui
+
+import (
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+const testRoleBindings = `
+roleBindings:
+  - name: team-a
+    groups: ["team-a"]
+    permissions: ["deploy", "approve"]
+    scopes:
+      - kluctlDeployment:
+          namespace: team-a-*
+  - name: team-b
+    users: ["bob@example.com"]
+    permissions: ["diff"]
+    rbacUser: team-b-rbac
+    scopes:
+      - project:
+          repoKey: github.com/team-b/**
+        target:
+          targetName: prod
+`
+
+func buildTestAuthHandler(t *testing.T) *authHandler {
+	rbs, err := ParseRoleBindings([]byte(testRoleBindings))
+	assert.NoError(t, err)
+	return &authHandler{
+		authConfig: AuthConfig{
+			AdminRbacUser:  "admin-rbac",
+			ViewerRbacUser: "viewer-rbac",
+		},
+		roleBindings: rbs,
+	}
+}
+
+func TestParseRoleBindingsInvalid(t *testing.T) {
+	_, err := ParseRoleBindings([]byte(`
+roleBindings:
+  - name: x
+    users: ["a"]
+    permissions: ["destroy"]
+`))
+	assert.ErrorContains(t, err, "unknown permission 'destroy'")
+
+	_, err = ParseRoleBindings([]byte(`
+roleBindings:
+  - name: x
+    permissions: ["view"]
+`))
+	assert.ErrorContains(t, err, "at least one user or group must be specified")
+
+	_, err = ParseRoleBindings([]byte(`
+roleBindings:
+  - name: x
+    users: ["a"]
+    permissions: ["view"]
+    scopes:
+      - kluctlDeployment:
+          name: "[a"
+`))
+	assert.ErrorContains(t, err, "invalid pattern")
+}
+
+func TestCheckPermissionAdminAndViewer(t *testing.T) {
+	s := buildTestAuthHandler(t)
+
+	ok, rbacUser := s.checkPermission(&User{Username: "admin", IsAdmin: true}, PermissionPrune, accessTarget{})
+	assert.True(t, ok)
+	assert.Equal(t, "admin-rbac", rbacUser)
+
+	ok, rbacUser = s.checkPermission(&User{Username: "viewer", IsViewer: true}, PermissionView, accessTarget{})
+	assert.True(t, ok)
+	assert.Equal(t, "viewer-rbac", rbacUser)
+
+	ok, _ = s.checkPermission(&User{Username: "viewer", IsViewer: true}, PermissionDeploy, accessTarget{})
+	assert.False(t, ok)
+
+	ok, _ = s.checkPermission(nil, PermissionView, accessTarget{})
+	assert.False(t, ok)
+}
+
+func TestCheckPermissionKluctlDeploymentScope(t *testing.T) {
+	s := buildTestAuthHandler(t)
+	user := &User{Username: "alice@example.com", Groups: []string{"team-a"}}
+
+	kd := &kluctlv1.KluctlDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-a-apps"},
+	}
+	at := newKluctlDeploymentAccessTarget("cluster", kd)
+
+	ok, rbacUser := s.checkPermission(user, PermissionDeploy, at)
+	assert.True(t, ok)
+	assert.Equal(t, "admin-rbac", rbacUser)
+
+	ok, _ = s.checkPermission(user, PermissionApprove, at)
+	assert.True(t, ok)
+	ok, _ = s.checkPermission(user, PermissionView, at)
+	assert.True(t, ok)
+	ok, _ = s.checkPermission(user, PermissionPrune, at)
+	assert.False(t, ok)
+
+	kd.Namespace = "team-b-apps"
+	ok, _ = s.checkPermission(user, PermissionView, newKluctlDeploymentAccessTarget("cluster", kd))
+	assert.False(t, ok)
+
+	// results from CLI invocations are not related to a KluctlDeployment
+	ok, _ = s.checkPermission(user, PermissionView, newResultAccessTarget(result.ProjectKey{}, result.TargetKey{}, nil))
+	assert.False(t, ok)
+}
+
+func TestCheckPermissionProjectTargetScope(t *testing.T) {
+	s := buildTestAuthHandler(t)
+	user := &User{Username: "bob@example.com"}
+
+	pk := result.ProjectKey{RepoKey: types.NewRepoKey("git", "github.com", "team-b/sub/repo")}
+	tk := result.TargetKey{TargetName: "prod"}
+
+	ok, rbacUser := s.checkPermission(user, PermissionDiff, newResultAccessTarget(pk, tk, nil))
+	assert.True(t, ok)
+	assert.Equal(t, "team-b-rbac", rbacUser)
+
+	ok, _ = s.checkPermission(user, PermissionDiff, newResultAccessTarget(pk, result.TargetKey{TargetName: "test"}, nil))
+	assert.False(t, ok)
+
+	pk.RepoKey = types.NewRepoKey("git", "github.com", "team-a/repo")
+	ok, _ = s.checkPermission(user, PermissionView, newResultAccessTarget(pk, tk, nil))
+	assert.False(t, ok)
+
+	// KluctlDeployments are matched via the project and target keys found in the status
+	kd := &kluctlv1.KluctlDeployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "team-b"},
+	}
+	ok, _ = s.checkPermission(user, PermissionView, newKluctlDeploymentAccessTarget("cluster", kd))
+	assert.False(t, ok)
+	kd.Status.ProjectKey = &result.ProjectKey{RepoKey: types.NewRepoKey("git", "github.com", "team-b/repo")}
+	kd.Status.TargetKey = &tk
+	ok, _ = s.checkPermission(user, PermissionView, newKluctlDeploymentAccessTarget("cluster", kd))
+	assert.True(t, ok)
+}
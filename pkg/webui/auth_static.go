@@ -124,7 +124,7 @@ func (s *authHandler) getAdminUser(id string) *User {
 func (s *authHandler) getViewerUser(id string) *User {
 	u := &User{
 		Username: id,
-		IsAdmin:  false,
+		IsViewer: true,
 	}
 	return u
 }
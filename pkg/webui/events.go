@@ -33,6 +33,7 @@ type eventEntry struct {
 	expire        *time.Time
 	seq           int64
 	projectTarget *ProjectTargetKey
+	accessTarget  accessTarget
 	payload       string
 }
 
@@ -46,7 +47,7 @@ func newEventsHandler(server *CommandResultsServer) *eventsHandler {
 	return h
 }
 
-func (h *eventsHandler) updateEvent(id string, ptKey *ProjectTargetKey, payload string, expireIn *time.Duration) {
+func (h *eventsHandler) updateEvent(id string, ptKey *ProjectTargetKey, at accessTarget, payload string, expireIn *time.Duration) {
 	h.mutex.Lock()
 	defer h.mutex.Unlock()
 
@@ -66,6 +67,7 @@ func (h *eventsHandler) updateEvent(id string, ptKey *ProjectTargetKey, payload
 			seq:           seq,
 			id:            id,
 			projectTarget: ptKey,
+			accessTarget:  at,
 			payload:       payload,
 		})
 		h.eventsMap[id] = e
@@ -73,6 +75,7 @@ func (h *eventsHandler) updateEvent(id string, ptKey *ProjectTargetKey, payload
 		e2 := e.Value.(*eventEntry)
 		e2.expire = expire
 		e2.seq = seq
+		e2.accessTarget = at
 		e2.payload = payload
 
 		h.events.MoveToBack(e)
@@ -193,7 +196,8 @@ func (h *eventsHandler) startEventsWatcher() error {
 				if event.Delete {
 					expireIn = &expireDeletions
 				}
-				h.updateEvent("cr-"+event.Summary.Id, &ProjectTargetKey{Project: event.Summary.ProjectKey, Target: event.Summary.TargetKey}, buildCommandResultMsg(event), expireIn)
+				at := newResultAccessTarget(event.Summary.ProjectKey, event.Summary.TargetKey, event.Summary.KluctlDeployment)
+				h.updateEvent("cr-"+event.Summary.Id, &ProjectTargetKey{Project: event.Summary.ProjectKey, Target: event.Summary.TargetKey}, at, buildCommandResultMsg(event), expireIn)
 			case event, ok := <-validateResultsCh:
 				if !ok {
 					status.Error(h.server.ctx, "results channel closed unexpectedly")
@@ -203,7 +207,8 @@ func (h *eventsHandler) startEventsWatcher() error {
 				if event.Delete {
 					expireIn = &expireDeletions
 				}
-				h.updateEvent("vr-"+event.Summary.Id, &ProjectTargetKey{Project: event.Summary.ProjectKey, Target: event.Summary.TargetKey}, buildValidateResultMsg(event), expireIn)
+				at := newResultAccessTarget(event.Summary.ProjectKey, event.Summary.TargetKey, event.Summary.KluctlDeployment)
+				h.updateEvent("vr-"+event.Summary.Id, &ProjectTargetKey{Project: event.Summary.ProjectKey, Target: event.Summary.TargetKey}, at, buildValidateResultMsg(event), expireIn)
 			case event, ok := <-kluctlDeploymentsCh:
 				if !ok {
 					status.Error(h.server.ctx, "results channel closed unexpectedly")
@@ -213,7 +218,8 @@ func (h *eventsHandler) startEventsWatcher() error {
 				if event.Delete {
 					expireIn = &expireDeletions
 				}
-				h.updateEvent("kd-"+string(event.Deployment.UID), nil, buildKluctlDeploymentMsg(event), expireIn)
+				at := newKluctlDeploymentAccessTarget(event.ClusterId, event.Deployment)
+				h.updateEvent("kd-"+string(event.Deployment.UID), nil, at, buildKluctlDeploymentMsg(event), expireIn)
 			case <-cleanupTimer:
 				h.cleanupEvents()
 				cleanupTimer = time.After(5 * time.Second)
@@ -251,13 +257,15 @@ func (h *eventsHandler) handler(c *gin.Context) {
 		}
 	}
 
+	user := h.server.auth.getUser(c)
+
 	conn, err := acceptWebsocket(c)
 	if err != nil {
 		return
 	}
 	defer conn.Close(websocket.StatusInternalError, "the sky is falling")
 
-	err = h.wsHandle(conn, filter)
+	err = h.wsHandle(conn, user, filter)
 	if err != nil {
 		cs := websocket.CloseStatus(err)
 		if cs == websocket.StatusNormalClosure || cs == websocket.StatusGoingAway {
@@ -267,7 +275,7 @@ func (h *eventsHandler) handler(c *gin.Context) {
 	}
 }
 
-func (h *eventsHandler) wsHandle(c *websocket.Conn, filter *result.ProjectKey) error {
+func (h *eventsHandler) wsHandle(c *websocket.Conn, user *User, filter *result.ProjectKey) error {
 	ctx := c.CloseRead(h.server.ctx)
 
 	getNewEvents := func(seq int64) ([]string, int64) {
@@ -284,6 +292,9 @@ func (h *eventsHandler) wsHandle(c *websocket.Conn, filter *result.ProjectKey) e
 			if e2.projectTarget != nil && !results.FilterProject(e2.projectTarget.Project, filter) {
 				continue
 			}
+			if ok, _ := h.server.auth.checkPermission(user, PermissionView, e2.accessTarget); !ok {
+				continue
+			}
 			events = append(events, e2.payload)
 		}
 
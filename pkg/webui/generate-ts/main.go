@@ -15,10 +15,13 @@ func main() {
 		WithBackupDir("").
 		Add(result.CommandResult{}).
 		Add(result.CommandResultSummary{}).
+		Add(result.CommandResultComparison{}).
+		Add(result.ObjectHistoryEntry{}).
 		Add(result.ValidateResult{}).
 		Add(result.ValidateResultSummary{}).
 		Add(result.DriftDetectionResult{}).
 		Add(result.ChangedObject{}).
+		Add(result.AuditEvent{}).
 		Add(webui.ShortName{}).
 		Add(uo.UnstructuredObject{}).
 		Add(webui.ProjectTargetKey{}).
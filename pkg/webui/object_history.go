@@ -0,0 +1,107 @@
+package webui
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/types/k8s"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"net/http"
+)
+
+// loadObjectHistory builds the history of the object referenced by ref from all command results matching the given
+// params that the user is allowed to view. Changes are only included if the user is allowed to see diffs, and never
+// for Secrets when the user is not an admin.
+func (s *CommandResultsServer) loadObjectHistory(c *gin.Context, params *apiV1ListParams, ref k8s.ObjectRef) ([]result.ObjectHistoryEntry, error) {
+	if ref.Kind == "" || ref.Name == "" {
+		return nil, fmt.Errorf("kind and name are required")
+	}
+
+	options, err := params.buildOptions()
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.store.ListCommandResultSummaries(options)
+	if err != nil {
+		return nil, err
+	}
+
+	user := s.auth.getUser(c)
+	var filtered []result.CommandResultSummary
+	for _, x := range summaries {
+		if !params.filterTarget(x.TargetKey) {
+			continue
+		}
+		if ok, _ := s.auth.checkPermission(user, PermissionView, newResultAccessTarget(x.ProjectKey, x.TargetKey, x.KluctlDeployment)); !ok {
+			continue
+		}
+		filtered = append(filtered, x)
+	}
+
+	entries, err := results.LoadObjectHistory(s.store, filtered, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Limit > 0 && len(entries) > params.Limit {
+		entries = entries[:params.Limit]
+	}
+	for i := range entries {
+		e := &entries[i]
+		if user.IsAdmin {
+			continue
+		}
+		if e.Ref.GroupKind() == (schema.GroupKind{Kind: "Secret"}) {
+			e.Changes = nil
+		} else if ok, _ := s.auth.checkPermission(user, PermissionDiff, newResultAccessTarget(e.ProjectKey, e.TargetKey, e.KluctlDeployment)); !ok {
+			e.Changes = nil
+		}
+	}
+	return entries, nil
+}
+
+func (s *CommandResultsServer) getObjectHistory(c *gin.Context) {
+	var params apiV1ListParams
+	var ref refParam
+
+	err := c.Bind(&params)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	err = c.Bind(&ref)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	entries, err := s.loadObjectHistory(c, &params, ref.toK8sRef())
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+func (s *CommandResultsServer) apiV1GetObjectHistory(c *gin.Context) {
+	params, _ := s.bindApiV1ListParams(c)
+	if params == nil {
+		return
+	}
+	var ref refParam
+	err := c.BindQuery(&ref)
+	if err != nil {
+		apiV1Error(c, http.StatusBadRequest, err)
+		return
+	}
+
+	entries, err := s.loadObjectHistory(c, params, ref.toK8sRef())
+	if err != nil {
+		apiV1Error(c, http.StatusBadRequest, err)
+		return
+	}
+	c.JSON(http.StatusOK, apiV1ListResponse[result.ObjectHistoryEntry]{
+		Items: entries,
+	})
+}
@@ -0,0 +1,92 @@
+package webui
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"net/http"
+	"time"
+)
+
+// doSetCommandResultPinned checks the pin permission and pins or unpins the command result. On failure, the status code
+// and the error to report are returned.
+func (s *CommandResultsServer) doSetCommandResultPinned(c *gin.Context, resultId string, pinned bool) (int, error) {
+	cr, err := s.store.GetCommandResult(results.GetCommandResultOptions{
+		Id:      resultId,
+		Reduced: true,
+	})
+	if err != nil {
+		return http.StatusBadRequest, err
+	}
+	if cr == nil {
+		return http.StatusNotFound, fmt.Errorf("command result %s not found", resultId)
+	}
+
+	user := s.auth.getUser(c)
+	if ok, _ := s.auth.checkPermission(user, PermissionPin, newResultAccessTarget(cr.ProjectKey, cr.TargetKey, cr.KluctlDeployment)); !ok {
+		return http.StatusForbidden, fmt.Errorf("not allowed to pin command result %s", resultId)
+	}
+
+	// results written by the controller are stored in the controller's cluster, while results written by the CLI are
+	// stored in the target cluster
+	clusterId := cr.ClusterInfo.ClusterId
+	if cr.KluctlDeployment != nil {
+		clusterId = cr.KluctlDeployment.ClusterId
+	}
+	ca := s.cam.getForClusterId(clusterId)
+	if ca == nil {
+		return http.StatusNotFound, fmt.Errorf("cluster %s not found", clusterId)
+	}
+
+	// results are pinned with the webui's own service account, as users are usually not allowed to write results
+	kc, err := ca.getClient("", nil)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	ctx, cancel := context.WithTimeout(s.ctx, time.Second*10)
+	defer cancel()
+
+	err = results.SetCommandResultPinnedSecret(ctx, kc, resultId, pinned)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
+func (s *CommandResultsServer) setCommandResultPinned(c *gin.Context) {
+	var params struct {
+		ResultId string `json:"resultId"`
+		Pinned   bool   `json:"pinned"`
+	}
+	err := c.Bind(&params)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	code, err := s.doSetCommandResultPinned(c, params.ResultId, params.Pinned)
+	if err != nil {
+		_ = c.AbortWithError(code, err)
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (s *CommandResultsServer) apiV1PinResult(c *gin.Context) {
+	s.apiV1SetResultPinned(c, true)
+}
+
+func (s *CommandResultsServer) apiV1UnpinResult(c *gin.Context) {
+	s.apiV1SetResultPinned(c, false)
+}
+
+func (s *CommandResultsServer) apiV1SetResultPinned(c *gin.Context, pinned bool) {
+	code, err := s.doSetCommandResultPinned(c, c.Param("id"), pinned)
+	if err != nil {
+		apiV1Error(c, code, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"pinned": pinned})
+}
@@ -38,7 +38,8 @@ type CommandResultsServer struct {
 	store results.ResultStore
 	cam   *clusterAccessorManager
 
-	controllerNamespace string
+	controllerNamespace  string
+	auditEventsNamespace string
 
 	// this is the client for the k8s cluster where the server runs on
 	serverClient       client.Client
@@ -55,6 +56,7 @@ func NewCommandResultsServer(
 	store *results.ResultsCollector,
 	configs []*rest.Config,
 	controllerNamespace string,
+	auditEventsNamespace string,
 	serverConfig *rest.Config,
 	serverClient client.Client,
 	authConfig AuthConfig,
@@ -66,8 +68,9 @@ func NewCommandResultsServer(
 		cam: &clusterAccessorManager{
 			ctx: ctx,
 		},
-		serverClient: serverClient,
-		onlyApi:      onlyApi,
+		auditEventsNamespace: auditEventsNamespace,
+		serverClient:         serverClient,
+		onlyApi:              onlyApi,
 	}
 
 	var err error
@@ -133,13 +136,20 @@ func (s *CommandResultsServer) Run(host string, port int, openBrowser bool) erro
 	api.GET("/getShortNames", s.getShortNames)
 	api.GET("/getCommandResult", s.getCommandResult)
 	api.GET("/getCommandResultObject", s.getCommandResultObject)
+	api.GET("/compareCommandResults", s.compareCommandResults)
+	api.GET("/getObjectHistory", s.getObjectHistory)
 	api.GET("/getValidateResult", s.getValidateResult)
 	api.POST("/validateNow", s.validateNow)
+	api.POST("/diffNow", s.diffNow)
 	api.POST("/reconcileNow", s.reconcileNow)
 	api.POST("/deployNow", s.deployNow)
 	api.POST("/pruneNow", s.pruneNow)
 	api.POST("/setSuspended", s.setSuspended)
 	api.POST("/setManualObjectsHash", s.setManualObjectsHash)
+	api.POST("/setCommandResultPinned", s.setCommandResultPinned)
+	api.GET("/getAuditEvents", s.getAuditEvents)
+
+	s.setupApiV1Routes(api)
 
 	err = s.events.startEventsWatcher()
 	if err != nil {
@@ -308,7 +318,7 @@ func (s *CommandResultsServer) redactSensitiveVars(user *User, d *types.Deployme
 	}
 }
 
-func (s *CommandResultsServer) checkObjectAccess(c *gin.Context, user *User, o *uo.UnstructuredObject, objectType string) (bool, *uo.UnstructuredObject) {
+func (s *CommandResultsServer) checkObjectAccess(c *gin.Context, user *User, at accessTarget, o *uo.UnstructuredObject, objectType string) (bool, *uo.UnstructuredObject) {
 	if user.IsAdmin {
 		// everything allowed
 		return true, o
@@ -317,9 +327,11 @@ func (s *CommandResultsServer) checkObjectAccess(c *gin.Context, user *User, o *
 		return true, o
 	}
 
-	// non-admins can only see the rendered version
+	// non-admins can only see the rendered version, unless they are allowed to see diffs
 	if objectType != "rendered" {
-		return false, o
+		if ok, _ := s.auth.checkPermission(user, PermissionDiff, at); !ok {
+			return false, o
+		}
 	}
 
 	// but no secrets
@@ -339,31 +351,47 @@ func (s *CommandResultsServer) getCommandResult(c *gin.Context) {
 		return
 	}
 
+	sr := s.loadCommandResult(c, params.ResultId, true)
+	if sr == nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, sr)
+}
+
+// loadCommandResult loads the command result, checks permissions and removes everything the current user is
+// not allowed to see. On failure, the request is aborted and nil is returned.
+func (s *CommandResultsServer) loadCommandResult(c *gin.Context, id string, reduced bool) *result.CommandResult {
 	sr, err := s.store.GetCommandResult(results.GetCommandResultOptions{
-		Id:      params.ResultId,
-		Reduced: true,
+		Id:      id,
+		Reduced: reduced,
 	})
 	if err != nil {
 		_ = c.AbortWithError(http.StatusBadRequest, err)
-		return
+		return nil
 	}
 	if sr == nil {
 		c.AbortWithStatus(http.StatusNotFound)
-		return
+		return nil
 	}
 
 	user := s.auth.getUser(c)
+	at := newResultAccessTarget(sr.ProjectKey, sr.TargetKey, sr.KluctlDeployment)
+	if ok, _ := s.auth.checkPermission(user, PermissionView, at); !ok {
+		c.AbortWithStatus(http.StatusForbidden)
+		return nil
+	}
 
 	s.redactSensitiveVars(user, sr.Deployment)
 
 	for i, _ := range sr.Objects {
 		o := &sr.Objects[i]
-		_, o.Rendered = s.checkObjectAccess(c, user, o.Rendered, "rendered")
-		_, o.Remote = s.checkObjectAccess(c, user, o.Remote, "remote")
-		_, o.Applied = s.checkObjectAccess(c, user, o.Applied, "applied")
+		_, o.Rendered = s.checkObjectAccess(c, user, at, o.Rendered, "rendered")
+		_, o.Remote = s.checkObjectAccess(c, user, at, o.Remote, "remote")
+		_, o.Applied = s.checkObjectAccess(c, user, at, o.Applied, "applied")
 	}
 
-	c.JSON(http.StatusOK, sr)
+	return sr
 }
 
 func (s *CommandResultsServer) getCommandResultObject(c *gin.Context) {
@@ -400,6 +428,13 @@ func (s *CommandResultsServer) getCommandResultObject(c *gin.Context) {
 		return
 	}
 
+	user := s.auth.getUser(c)
+	at := newResultAccessTarget(sr.ProjectKey, sr.TargetKey, sr.KluctlDeployment)
+	if ok, _ := s.auth.checkPermission(user, PermissionView, at); !ok {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
 	ref2 := ref.toK8sRef()
 
 	var found *result.ResultObject
@@ -414,17 +449,15 @@ func (s *CommandResultsServer) getCommandResultObject(c *gin.Context) {
 		return
 	}
 
-	user := s.auth.getUser(c)
-
 	var ok bool
 	var o2 *uo.UnstructuredObject
 	switch objectType.ObjectType {
 	case "rendered":
-		ok, o2 = s.checkObjectAccess(c, user, found.Rendered, objectType.ObjectType)
+		ok, o2 = s.checkObjectAccess(c, user, at, found.Rendered, objectType.ObjectType)
 	case "remote":
-		ok, o2 = s.checkObjectAccess(c, user, found.Remote, objectType.ObjectType)
+		ok, o2 = s.checkObjectAccess(c, user, at, found.Remote, objectType.ObjectType)
 	case "applied":
-		ok, o2 = s.checkObjectAccess(c, user, found.Applied, objectType.ObjectType)
+		ok, o2 = s.checkObjectAccess(c, user, at, found.Applied, objectType.ObjectType)
 	default:
 		c.AbortWithStatus(http.StatusNotFound)
 		return
@@ -441,6 +474,39 @@ func (s *CommandResultsServer) getCommandResultObject(c *gin.Context) {
 	c.JSON(http.StatusOK, o2)
 }
 
+type compareCommandResultsParams struct {
+	OldResultId string `form:"oldResultId" binding:"required"`
+	NewResultId string `form:"newResultId" binding:"required"`
+}
+
+func (s *CommandResultsServer) compareCommandResults(c *gin.Context) {
+	var params compareCommandResultsParams
+
+	err := c.Bind(&params)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	// rendered objects are only available in non-reduced results
+	oldCr := s.loadCommandResult(c, params.OldResultId, false)
+	if oldCr == nil {
+		return
+	}
+	newCr := s.loadCommandResult(c, params.NewResultId, false)
+	if newCr == nil {
+		return
+	}
+
+	cmp, err := results.CompareCommandResults(oldCr, newCr)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cmp)
+}
+
 func (s *CommandResultsServer) getValidateResult(c *gin.Context) {
 	var params resultIdParam
 
@@ -450,35 +516,54 @@ func (s *CommandResultsServer) getValidateResult(c *gin.Context) {
 		return
 	}
 
+	vr := s.loadValidateResult(c, params.ResultId)
+	if vr == nil {
+		return
+	}
+
+	c.JSON(http.StatusOK, vr)
+}
+
+func (s *CommandResultsServer) loadValidateResult(c *gin.Context, id string) *result.ValidateResult {
 	vr, err := s.store.GetValidateResult(results.GetValidateResultOptions{
-		Id: params.ResultId,
+		Id: id,
 	})
 	if err != nil {
 		_ = c.AbortWithError(http.StatusBadRequest, err)
-		return
+		return nil
 	}
 	if vr == nil {
 		c.AbortWithStatus(http.StatusNotFound)
-		return
+		return nil
 	}
 
-	c.JSON(http.StatusOK, vr)
+	user := s.auth.getUser(c)
+	at := newResultAccessTarget(vr.ProjectKey, vr.TargetKey, vr.KluctlDeployment)
+	if ok, _ := s.auth.checkPermission(user, PermissionView, at); !ok {
+		c.AbortWithStatus(http.StatusForbidden)
+		return nil
+	}
+
+	return vr
 }
 
-func (s *CommandResultsServer) doModifyKluctlDeployment(c *gin.Context, clusterId string, name string, namespace string, update func(obj *kluctlv1.KluctlDeployment) error) {
+// checkKluctlDeploymentPermission retrieves the given KluctlDeployment and checks if the current user has the given
+// permission for it. On success, the deployment, the cluster accessor and the RBAC user to use for modifications are
+// returned. On failure, the request is aborted and nil is returned.
+func (s *CommandResultsServer) checkKluctlDeploymentPermission(c *gin.Context, perm Permission, clusterId string, name string, namespace string) (*kluctlv1.KluctlDeployment, *clusterAccessor, string) {
 	user := s.auth.getUser(c)
 
 	ca := s.cam.getForClusterId(clusterId)
 	if ca == nil {
 		_ = c.AbortWithError(http.StatusNotFound, fmt.Errorf("cluster %s not found", clusterId))
-		return
+		return nil, nil, ""
 	}
 
-	rbacUser := s.auth.getRbacUser(user)
-	kc, err := ca.getClient(rbacUser, nil)
+	// we retrieve the deployment without impersonation, as we need it to check permissions
+	kc, err := ca.getClient("", nil)
 	if err != nil {
 		_ = c.AbortWithError(http.StatusInternalServerError, err)
-		return
+		return nil, nil, ""
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
@@ -489,27 +574,53 @@ func (s *CommandResultsServer) doModifyKluctlDeployment(c *gin.Context, clusterI
 	if err != nil {
 		if errors.IsNotFound(err) {
 			_ = c.AbortWithError(http.StatusNotFound, err)
-			return
+			return nil, nil, ""
 		}
 		_ = c.AbortWithError(http.StatusInternalServerError, err)
-		return
+		return nil, nil, ""
+	}
+
+	ok, rbacUser := s.auth.checkPermission(user, perm, newKluctlDeploymentAccessTarget(clusterId, &kd))
+	if !ok {
+		c.AbortWithStatus(http.StatusForbidden)
+		return nil, nil, ""
 	}
 
+	return &kd, ca, rbacUser
+}
+
+// doModifyKluctlDeployment checks permissions and then patches the given KluctlDeployment. On failure, the request is
+// aborted and nil is returned. Otherwise, the patched KluctlDeployment is returned.
+func (s *CommandResultsServer) doModifyKluctlDeployment(c *gin.Context, perm Permission, clusterId string, name string, namespace string, update func(obj *kluctlv1.KluctlDeployment) error) *kluctlv1.KluctlDeployment {
+	kd, ca, rbacUser := s.checkKluctlDeploymentPermission(c, perm, clusterId, name, namespace)
+	if kd == nil {
+		return nil
+	}
+
+	kc, err := ca.getClient(rbacUser, nil)
+	if err != nil {
+		_ = c.AbortWithError(http.StatusInternalServerError, err)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
 	patch := client.MergeFrom(kd.DeepCopy())
 
-	err = update(&kd)
+	err = update(kd)
 	if err != nil {
 		_ = c.AbortWithError(http.StatusInternalServerError, err)
-		return
+		return nil
 	}
 
-	err = kc.Patch(ctx, &kd, patch, client.FieldOwner(webuiManager))
+	err = kc.Patch(ctx, kd, patch, client.FieldOwner(webuiManager))
 	if err != nil {
 		_ = c.AbortWithError(http.StatusInternalServerError, err)
-		return
+		return nil
 	}
 
-	c.Status(http.StatusOK)
+	return kd
 }
 
 type KluctlDeploymentParam struct {
@@ -518,41 +629,58 @@ type KluctlDeploymentParam struct {
 	Namespace string `json:"namespace" form:"namespace"`
 }
 
-func (s *CommandResultsServer) doSetAnnotation(c *gin.Context, aname string, avalue string) {
+func (s *CommandResultsServer) manualRequest(c *gin.Context, perm Permission, annotationName string) {
 	var params KluctlDeploymentParam
 	err := c.Bind(&params)
 	if err != nil {
 		_ = c.AbortWithError(http.StatusBadRequest, err)
 		return
 	}
-	s.doModifyKluctlDeployment(c, params.Cluster, params.Name, params.Namespace, func(obj *kluctlv1.KluctlDeployment) error {
-		metav1.SetMetaDataAnnotation(&obj.ObjectMeta, aname, avalue)
-		return nil
-	})
+	if s.doManualRequest(c, perm, annotationName, params) == nil {
+		return
+	}
+	c.Status(http.StatusOK)
 }
 
-func (s *CommandResultsServer) manualRequest(c *gin.Context, annotationName string) {
+// doManualRequest sets the given manual request annotation. It returns the ManualRequest on success and nil if the
+// request got aborted.
+func (s *CommandResultsServer) doManualRequest(c *gin.Context, perm Permission, annotationName string, params KluctlDeploymentParam) *kluctlv1.ManualRequest {
 	mr := &kluctlv1.ManualRequest{
 		RequestValue: time.Now().Format(time.RFC3339Nano),
+		Source:       getAuditSource(c),
+	}
+	if user := s.auth.getUser(c); user != nil {
+		mr.RequestedBy = user.Username
 	}
 
-	s.doSetAnnotation(c, annotationName, yaml.WriteJsonStringMust(mr))
+	kd := s.doModifyKluctlDeployment(c, perm, params.Cluster, params.Name, params.Namespace, func(obj *kluctlv1.KluctlDeployment) error {
+		metav1.SetMetaDataAnnotation(&obj.ObjectMeta, annotationName, yaml.WriteJsonStringMust(mr))
+		return nil
+	})
+	if kd == nil {
+		return nil
+	}
+	return mr
 }
 
 func (s *CommandResultsServer) validateNow(c *gin.Context) {
-	s.manualRequest(c, kluctlv1.KluctlRequestValidateAnnotation)
+	s.manualRequest(c, PermissionDiff, kluctlv1.KluctlRequestValidateAnnotation)
+}
+
+func (s *CommandResultsServer) diffNow(c *gin.Context) {
+	s.manualRequest(c, PermissionDiff, kluctlv1.KluctlRequestDiffAnnotation)
 }
 
 func (s *CommandResultsServer) reconcileNow(c *gin.Context) {
-	s.manualRequest(c, kluctlv1.KluctlRequestReconcileAnnotation)
+	s.manualRequest(c, PermissionDeploy, kluctlv1.KluctlRequestReconcileAnnotation)
 }
 
 func (s *CommandResultsServer) deployNow(c *gin.Context) {
-	s.manualRequest(c, kluctlv1.KluctlRequestDeployAnnotation)
+	s.manualRequest(c, PermissionDeploy, kluctlv1.KluctlRequestDeployAnnotation)
 }
 
 func (s *CommandResultsServer) pruneNow(c *gin.Context) {
-	s.manualRequest(c, kluctlv1.KluctlRequestPruneAnnotation)
+	s.manualRequest(c, PermissionPrune, kluctlv1.KluctlRequestPruneAnnotation)
 }
 
 func (s *CommandResultsServer) setSuspended(c *gin.Context) {
@@ -566,8 +694,19 @@ func (s *CommandResultsServer) setSuspended(c *gin.Context) {
 		return
 	}
 
-	s.doModifyKluctlDeployment(c, params.Cluster, params.Name, params.Namespace, func(obj *kluctlv1.KluctlDeployment) error {
-		obj.Spec.Suspend = params.Suspend
+	if !s.doSetSuspended(c, params.KluctlDeploymentParam, params.Suspend) {
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (s *CommandResultsServer) doSetSuspended(c *gin.Context, params KluctlDeploymentParam, suspend bool) bool {
+	action := result.AuditActionSuspend
+	if !suspend {
+		action = result.AuditActionResume
+	}
+	return s.doModifyKluctlDeploymentAudited(c, PermissionSuspend, params, action, "", func(obj *kluctlv1.KluctlDeployment) error {
+		obj.Spec.Suspend = suspend
 		return nil
 	})
 }
@@ -583,11 +722,18 @@ func (s *CommandResultsServer) setManualObjectsHash(c *gin.Context) {
 		return
 	}
 
-	s.doModifyKluctlDeployment(c, params.Cluster, params.Name, params.Namespace, func(obj *kluctlv1.KluctlDeployment) error {
-		if params.ObjectsHash == "" {
+	if !s.doSetManualObjectsHash(c, params.KluctlDeploymentParam, params.ObjectsHash) {
+		return
+	}
+	c.Status(http.StatusOK)
+}
+
+func (s *CommandResultsServer) doSetManualObjectsHash(c *gin.Context, params KluctlDeploymentParam, objectsHash string) bool {
+	return s.doModifyKluctlDeploymentAudited(c, PermissionApprove, params, result.AuditActionApprove, objectsHash, func(obj *kluctlv1.KluctlDeployment) error {
+		if objectsHash == "" {
 			obj.Spec.ManualObjectsHash = nil
 		} else {
-			obj.Spec.ManualObjectsHash = &params.ObjectsHash
+			obj.Spec.ManualObjectsHash = &objectsHash
 		}
 		return nil
 	})
@@ -0,0 +1,291 @@
+package webui
+
+import (
+	_ "embed"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	kluctlv1 "github.com/kluctl/kluctl/v2/api/v1beta1"
+	"github.com/kluctl/kluctl/v2/pkg/results"
+	"github.com/kluctl/kluctl/v2/pkg/types"
+	"github.com/kluctl/kluctl/v2/pkg/types/result"
+	"net/http"
+)
+
+//go:embed openapi-v1.yaml
+var openapiV1 []byte
+
+type apiV1ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+type apiV1ListParams struct {
+	Project    string `form:"project"`
+	SubDir     string `form:"subDir"`
+	TargetName string `form:"targetName"`
+	ClusterId  string `form:"clusterId"`
+	Limit      int    `form:"limit"`
+}
+
+type apiV1ListResponse[T any] struct {
+	Items []T `json:"items"`
+}
+
+type apiV1KluctlDeployment struct {
+	ClusterId  string                     `json:"clusterId"`
+	Deployment *kluctlv1.KluctlDeployment `json:"deployment"`
+}
+
+type apiV1ActionResponse struct {
+	Action       string `json:"action"`
+	RequestValue string `json:"requestValue,omitempty"`
+}
+
+func (s *CommandResultsServer) setupApiV1Routes(api gin.IRouter) {
+	v1 := api.Group("/v1")
+	v1.GET("/openapi.yaml", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/yaml", openapiV1)
+	})
+	v1.GET("/results", s.apiV1ListResults)
+	v1.GET("/results/:id", s.apiV1GetResult)
+	v1.POST("/results/:id/pin", s.apiV1PinResult)
+	v1.POST("/results/:id/unpin", s.apiV1UnpinResult)
+	v1.GET("/object-history", s.apiV1GetObjectHistory)
+	v1.GET("/validate-results", s.apiV1ListValidateResults)
+	v1.GET("/validate-results/:id", s.apiV1GetValidateResult)
+	v1.GET("/kluctl-deployments", s.apiV1ListKluctlDeployments)
+	v1.POST("/kluctl-deployments/:clusterId/:namespace/:name/:action", s.apiV1KluctlDeploymentAction)
+	v1.GET("/audit-events", s.apiV1ListAuditEvents)
+}
+
+func apiV1Error(c *gin.Context, code int, err error) {
+	c.AbortWithStatusJSON(code, apiV1ErrorResponse{Error: err.Error()})
+}
+
+func (p *apiV1ListParams) buildOptions() (results.ListResultSummariesOptions, error) {
+	var ret results.ListResultSummariesOptions
+	if p.Project == "" {
+		return ret, nil
+	}
+	repoKey, err := types.ParseRepoKey(p.Project, "git")
+	if err != nil {
+		return ret, err
+	}
+	ret.ProjectFilter = &result.ProjectKey{
+		RepoKey: repoKey,
+		SubDir:  p.SubDir,
+	}
+	return ret, nil
+}
+
+func (p *apiV1ListParams) filterTarget(tk result.TargetKey) bool {
+	if p.TargetName != "" && tk.TargetName != p.TargetName {
+		return false
+	}
+	if p.ClusterId != "" && tk.ClusterId != p.ClusterId {
+		return false
+	}
+	return true
+}
+
+func (s *CommandResultsServer) bindApiV1ListParams(c *gin.Context) (*apiV1ListParams, *results.ListResultSummariesOptions) {
+	var params apiV1ListParams
+	err := c.BindQuery(&params)
+	if err != nil {
+		apiV1Error(c, http.StatusBadRequest, err)
+		return nil, nil
+	}
+	if params.Limit < 0 {
+		apiV1Error(c, http.StatusBadRequest, fmt.Errorf("limit must not be negative"))
+		return nil, nil
+	}
+	options, err := params.buildOptions()
+	if err != nil {
+		apiV1Error(c, http.StatusBadRequest, err)
+		return nil, nil
+	}
+	return &params, &options
+}
+
+func (s *CommandResultsServer) apiV1ListResults(c *gin.Context) {
+	params, options := s.bindApiV1ListParams(c)
+	if params == nil {
+		return
+	}
+
+	summaries, err := s.store.ListCommandResultSummaries(*options)
+	if err != nil {
+		apiV1Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	user := s.auth.getUser(c)
+	ret := apiV1ListResponse[result.CommandResultSummary]{
+		Items: []result.CommandResultSummary{},
+	}
+	for _, x := range summaries {
+		if params.Limit != 0 && len(ret.Items) >= params.Limit {
+			break
+		}
+		if !params.filterTarget(x.TargetKey) {
+			continue
+		}
+		if ok, _ := s.auth.checkPermission(user, PermissionView, newResultAccessTarget(x.ProjectKey, x.TargetKey, x.KluctlDeployment)); !ok {
+			continue
+		}
+		ret.Items = append(ret.Items, x)
+	}
+	c.JSON(http.StatusOK, ret)
+}
+
+func (s *CommandResultsServer) apiV1GetResult(c *gin.Context) {
+	sr := s.loadCommandResult(c, c.Param("id"), true)
+	if sr == nil {
+		return
+	}
+	c.JSON(http.StatusOK, sr)
+}
+
+func (s *CommandResultsServer) apiV1ListValidateResults(c *gin.Context) {
+	params, options := s.bindApiV1ListParams(c)
+	if params == nil {
+		return
+	}
+
+	summaries, err := s.store.ListValidateResultSummaries(*options)
+	if err != nil {
+		apiV1Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	user := s.auth.getUser(c)
+	ret := apiV1ListResponse[result.ValidateResultSummary]{
+		Items: []result.ValidateResultSummary{},
+	}
+	for _, x := range summaries {
+		if params.Limit != 0 && len(ret.Items) >= params.Limit {
+			break
+		}
+		if !params.filterTarget(x.TargetKey) {
+			continue
+		}
+		if ok, _ := s.auth.checkPermission(user, PermissionView, newResultAccessTarget(x.ProjectKey, x.TargetKey, x.KluctlDeployment)); !ok {
+			continue
+		}
+		ret.Items = append(ret.Items, x)
+	}
+	c.JSON(http.StatusOK, ret)
+}
+
+func (s *CommandResultsServer) apiV1GetValidateResult(c *gin.Context) {
+	vr := s.loadValidateResult(c, c.Param("id"))
+	if vr == nil {
+		return
+	}
+	c.JSON(http.StatusOK, vr)
+}
+
+func (s *CommandResultsServer) apiV1ListKluctlDeployments(c *gin.Context) {
+	kds, err := s.store.ListKluctlDeployments()
+	if err != nil {
+		apiV1Error(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	user := s.auth.getUser(c)
+	ret := apiV1ListResponse[apiV1KluctlDeployment]{
+		Items: []apiV1KluctlDeployment{},
+	}
+	for _, x := range kds {
+		if ok, _ := s.auth.checkPermission(user, PermissionView, newKluctlDeploymentAccessTarget(x.ClusterId, x.Deployment)); !ok {
+			continue
+		}
+		ret.Items = append(ret.Items, apiV1KluctlDeployment{
+			ClusterId:  x.ClusterId,
+			Deployment: x.Deployment,
+		})
+	}
+	c.JSON(http.StatusOK, ret)
+}
+
+func (s *CommandResultsServer) apiV1ListAuditEvents(c *gin.Context) {
+	var params auditEventsParams
+	err := c.BindQuery(&params)
+	if err != nil {
+		apiV1Error(c, http.StatusBadRequest, err)
+		return
+	}
+	if params.Limit < 0 {
+		apiV1Error(c, http.StatusBadRequest, fmt.Errorf("limit must not be negative"))
+		return
+	}
+
+	events, err := s.listAuditEvents(c, params)
+	if err != nil {
+		apiV1Error(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, apiV1ListResponse[result.AuditEvent]{
+		Items: events,
+	})
+}
+
+var apiV1ManualRequestActions = map[string]struct {
+	perm       Permission
+	annotation string
+}{
+	"reconcile": {PermissionDeploy, kluctlv1.KluctlRequestReconcileAnnotation},
+	"deploy":    {PermissionDeploy, kluctlv1.KluctlRequestDeployAnnotation},
+	"prune":     {PermissionPrune, kluctlv1.KluctlRequestPruneAnnotation},
+	"validate":  {PermissionDiff, kluctlv1.KluctlRequestValidateAnnotation},
+	"diff":      {PermissionDiff, kluctlv1.KluctlRequestDiffAnnotation},
+}
+
+func (s *CommandResultsServer) apiV1KluctlDeploymentAction(c *gin.Context) {
+	params := KluctlDeploymentParam{
+		Cluster:   c.Param("clusterId"),
+		Name:      c.Param("name"),
+		Namespace: c.Param("namespace"),
+	}
+	action := c.Param("action")
+
+	resp := apiV1ActionResponse{
+		Action: action,
+	}
+
+	if x, ok := apiV1ManualRequestActions[action]; ok {
+		mr := s.doManualRequest(c, x.perm, x.annotation, params)
+		if mr == nil {
+			return
+		}
+		resp.RequestValue = mr.RequestValue
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	switch action {
+	case "suspend", "resume":
+		if !s.doSetSuspended(c, params, action == "suspend") {
+			return
+		}
+	case "approve":
+		var body struct {
+			ObjectsHash string `json:"objectsHash"`
+		}
+		err := c.BindJSON(&body)
+		if err != nil {
+			return
+		}
+		if body.ObjectsHash == "" {
+			apiV1Error(c, http.StatusBadRequest, fmt.Errorf("objectsHash is required"))
+			return
+		}
+		if !s.doSetManualObjectsHash(c, params, body.ObjectsHash) {
+			return
+		}
+	default:
+		apiV1Error(c, http.StatusNotFound, fmt.Errorf("unknown action %s", action))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,40 @@
+package webui
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/kluctl/kluctl/v2/pkg/yaml"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+func TestApiV1OpenApi(t *testing.T) {
+	s := &CommandResultsServer{}
+
+	router := gin.New()
+	s.setupApiV1Routes(router.Group("/api"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/openapi.yaml", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var spec map[string]any
+	err := yaml.ReadYamlString(w.Body.String(), &spec)
+	assert.NoError(t, err)
+
+	// ensure that all routes are documented
+	paths := spec["paths"].(map[string]any)
+	for _, r := range router.Routes() {
+		if r.Path == "/api/v1/openapi.yaml" {
+			continue
+		}
+		p := r.Path[len("/api/v1"):]
+		p = openApiPathRegex.ReplaceAllString(p, "{$1}")
+		assert.Contains(t, paths, p)
+	}
+}
+
+var openApiPathRegex = regexp.MustCompile(`:([a-zA-Z]+)`)
@@ -27,6 +27,14 @@ func (s *CommandResultsServer) logsHandler(gctx *gin.Context) {
 		return
 	}
 
+	// admins, viewers and unscoped role bindings don't require a lookup of the deployment
+	if ok, _ := s.auth.checkPermission(s.auth.getUser(gctx), PermissionView, accessTarget{}); !ok {
+		kd, _, _ := s.checkKluctlDeploymentPermission(gctx, PermissionView, args.Cluster, args.Name, args.Namespace)
+		if kd == nil {
+			return
+		}
+	}
+
 	conn, err := acceptWebsocket(gctx)
 	if err != nil {
 		return
@@ -1,7 +1,10 @@
 import {
+    AuditEvent,
     AuthInfo,
     CommandResult,
+    CommandResultComparison,
     CommandResultSummary,
+    ObjectHistoryEntry,
     ObjectRef, OciRef,
     ResultObject,
     ShortName,
@@ -40,6 +43,8 @@ export enum ObjectType {
 export interface User {
     username: string
     isAdmin: boolean
+    isViewer: boolean
+    groups?: string[]
 }
 
 export interface Api {
@@ -49,6 +54,8 @@ export interface Api {
     listenEvents(filterProject: string | undefined, filterSubDir: string | undefined, handle: (msg: any) => void): Promise<() => void>
     getCommandResult(resultId: string): Promise<CommandResult>
     getCommandResultObject(resultId: string, ref: ObjectRef, objectType: string): Promise<any>
+    compareCommandResults(oldResultId: string, newResultId: string): Promise<CommandResultComparison>
+    getObjectHistory(project: string | undefined, subDir: string | undefined, targetName: string | undefined, ref: ObjectRef): Promise<ObjectHistoryEntry[]>
     getValidateResult(resultId: string): Promise<ValidateResult>
     validateNow(cluster: string, name: string, namespace: string): Promise<Response>
     reconcileNow(cluster: string, name: string, namespace: string): Promise<Response>
@@ -56,7 +63,9 @@ export interface Api {
     pruneNow(cluster: string, name: string, namespace: string): Promise<Response>
     setSuspended(cluster: string, name: string, namespace: string, suspend: boolean): Promise<Response>
     setManualObjectsHash(cluster: string, name: string, namespace: string, objectsHash: string): Promise<Response>
+    setCommandResultPinned(resultId: string, pinned: boolean): Promise<Response>
     watchLogs(cluster: string | undefined, name: string | undefined, namespace: string | undefined, reconcileId: string | undefined, handle: (lines: any[]) => void): () => void
+    getAuditEvents(cluster: string, name: string, namespace: string): Promise<AuditEvent[]>
 }
 
 export async function checkStaticBuild() {
@@ -201,6 +210,30 @@ export class RealApi implements Api {
         return new CommandResult(json)
     }
 
+    async compareCommandResults(oldResultId: string, newResultId: string) {
+        const params = new URLSearchParams()
+        params.set("oldResultId", oldResultId)
+        params.set("newResultId", newResultId)
+        const json = await this.doGet("/api/compareCommandResults", params)
+        return new CommandResultComparison(json)
+    }
+
+    async getObjectHistory(project: string | undefined, subDir: string | undefined, targetName: string | undefined, ref: ObjectRef) {
+        const params = new URLSearchParams()
+        if (project) {
+            params.set("project", project)
+            if (subDir) {
+                params.set("subDir", subDir)
+            }
+        }
+        if (targetName) {
+            params.set("targetName", targetName)
+        }
+        buildRefParams(ref, params)
+        const json: any[] = await this.doGet("/api/getObjectHistory", params)
+        return json.map(x => new ObjectHistoryEntry(x))
+    }
+
     async getCommandResultObject(resultId: string, ref: ObjectRef, objectType: string) {
         const params = new URLSearchParams()
         params.set("resultId", resultId)
@@ -266,6 +299,13 @@ export class RealApi implements Api {
         })
     }
 
+    async setCommandResultPinned(resultId: string, pinned: boolean): Promise<Response> {
+        return this.doPost("/api/setCommandResultPinned", {
+            "resultId": resultId,
+            "pinned": pinned,
+        })
+    }
+
     watchLogs(cluster: string | undefined, name: string | undefined, namespace: string | undefined, reconcileId: string | undefined, handle: (lines: any[]) => void): () => void {
         const params = new URLSearchParams()
         if (cluster) params.set("cluster", cluster)
@@ -293,6 +333,15 @@ export class RealApi implements Api {
             ws.close()
         }
     }
+
+    async getAuditEvents(cluster: string, name: string, namespace: string): Promise<AuditEvent[]> {
+        const params = new URLSearchParams()
+        params.set("cluster", cluster)
+        params.set("name", name)
+        params.set("namespace", namespace)
+        const json: any[] = await this.doGet("/api/getAuditEvents", params)
+        return json.map(x => new AuditEvent(x))
+    }
 }
 
 export class StaticApi implements Api {
@@ -307,6 +356,7 @@ export class StaticApi implements Api {
         return {
             "username": "no-user",
             "isAdmin": true,
+            "isViewer": false,
         }
     }
 
@@ -374,6 +424,14 @@ export class StaticApi implements Api {
         }
     }
 
+    async compareCommandResults(oldResultId: string, newResultId: string): Promise<CommandResultComparison> {
+        throw new Error("not implemented")
+    }
+
+    async getObjectHistory(project: string | undefined, subDir: string | undefined, targetName: string | undefined, ref: ObjectRef): Promise<ObjectHistoryEntry[]> {
+        throw new Error("not implemented")
+    }
+
     async getValidateResult(resultId: string): Promise<ValidateResult> {
         throw new Error("not implemented")
     }
@@ -402,9 +460,17 @@ export class StaticApi implements Api {
         throw new Error("not implemented")
     }
 
+    setCommandResultPinned(resultId: string, pinned: boolean): Promise<Response> {
+        throw new Error("not implemented")
+    }
+
     watchLogs(cluster: string, name: string, namespace: string, reconcileId: string, handle: (lines: any[]) => void): () => void {
         return () => {}
     }
+
+    async getAuditEvents(cluster: string, name: string, namespace: string): Promise<AuditEvent[]> {
+        throw new Error("not implemented")
+    }
 }
 
 function buildRefParams(ref: ObjectRef, params: URLSearchParams) {
@@ -0,0 +1,73 @@
+import React from 'react';
+import Table from '@mui/material/Table';
+import TableBody from '@mui/material/TableBody';
+import TableCell from '@mui/material/TableCell';
+import TableContainer from '@mui/material/TableContainer';
+import TableHead from '@mui/material/TableHead';
+import TableRow from '@mui/material/TableRow';
+import { Box, Typography } from "@mui/material";
+import { AuditEvent } from "../models";
+import { useAppContext } from "./App";
+import { Loading, useLoadingHelper } from "./Loading";
+import { ErrorMessage } from "./ErrorMessage";
+import { Since } from "./Since";
+
+export function AuditEventsTable(props: { cluster: string, name: string, namespace: string }) {
+    const appCtx = useAppContext()
+
+    const [loading, error, events] = useLoadingHelper<AuditEvent[]>(true, async () => {
+        return await appCtx.api.getAuditEvents(props.cluster, props.name, props.namespace)
+    }, [props.cluster, props.name, props.namespace, appCtx.api])
+
+    if (loading) {
+        return <Loading/>
+    }
+    if (error) {
+        return <ErrorMessage>
+            {error.message}
+        </ErrorMessage>
+    }
+
+    return <>
+        <Box height={"100%"}>
+            <TableContainer>
+                <Table>
+                    <TableHead>
+                        <TableRow>
+                            <TableCell>Time</TableCell>
+                            <TableCell>User</TableCell>
+                            <TableCell>Source</TableCell>
+                            <TableCell>Action</TableCell>
+                            <TableCell>Result</TableCell>
+                        </TableRow>
+                    </TableHead>
+                    <TableBody>
+                        {events?.map(e => (
+                            <TableRow key={e.id}>
+                                <TableCell sx={{ minWidth: "100px" }}>
+                                    <Since startTime={new Date(e.time)}/>
+                                </TableCell>
+                                <TableCell>
+                                    <Typography>{e.user || "<unknown>"}</Typography>
+                                    {e.claimedUser && <Typography variant={"caption"} display={"block"}>claimed: {e.claimedUser}</Typography>}
+                                </TableCell>
+                                <TableCell>
+                                    <Typography>{e.source}</Typography>
+                                    {e.claimedSource && <Typography variant={"caption"} display={"block"}>claimed: {e.claimedSource}</Typography>}
+                                    {e.fieldManager && <Typography variant={"caption"} display={"block"}>field manager: {e.fieldManager}</Typography>}
+                                </TableCell>
+                                <TableCell>
+                                    <Typography>{e.action}</Typography>
+                                </TableCell>
+                                <TableCell>
+                                    {e.error && <Typography color={"error"}>{e.error}</Typography>}
+                                    {e.commandResultId && <Typography>{e.commandResultId}</Typography>}
+                                </TableCell>
+                            </TableRow>
+                        ))}
+                    </TableBody>
+                </Table>
+            </TableContainer>
+        </Box>
+    </>
+}
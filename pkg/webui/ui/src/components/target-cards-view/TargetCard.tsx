@@ -11,6 +11,7 @@ import { Loading, useLoadingHelper } from "../Loading";
 import { ErrorMessage } from "../ErrorMessage";
 import { ValidateResultsTable } from "../ValidateResultsTable";
 import { LogsViewer } from "../LogsViewer";
+import { AuditEventsTable } from "../AuditEventsTable";
 import { K8sManifestViewer } from "../K8sManifestViewer";
 import { YamlViewer } from "../YamlViewer";
 import { gitRefToString } from "../../utils/git";
@@ -236,6 +237,15 @@ class TargetItemCardProvider implements CardTabsProvider {
                 />
             })
         }
+        if (!appCtx.isStatic && this.ts.kdInfo) {
+            tabs.push({
+                label: "Audit", content: <AuditEventsTable
+                    cluster={this.ts.kdInfo.clusterId}
+                    name={this.ts.kdInfo.name}
+                    namespace={this.ts.kdInfo.namespace}
+                />
+            })
+        }
 
         return tabs
     }
@@ -2,6 +2,68 @@
 
 import { GitRef } from './models-static'
 
+export class SensitiveField {
+    ref: ObjectRef;
+    jsonPath: string;
+    varPath: string;
+    varsSource?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.ref = this.convertValues(source["ref"], ObjectRef);
+        this.jsonPath = source["jsonPath"];
+        this.varPath = source["varPath"];
+        this.varsSource = source["varsSource"];
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
+export class HelmLookup {
+    releaseName: string;
+    ref: ObjectRef;
+    found?: boolean;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.releaseName = source["releaseName"];
+        this.ref = this.convertValues(source["ref"], ObjectRef);
+        this.found = source["found"];
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
 export class DeploymentError {
     ref: ObjectRef;
     message: string;
@@ -53,6 +115,7 @@ export class ResultObject {
     orphan?: boolean;
     deleted?: boolean;
     hook?: boolean;
+    skipped?: boolean;
     rendered?: any;
     remote?: any;
     applied?: any;
@@ -65,6 +128,7 @@ export class ResultObject {
         this.orphan = source["orphan"];
         this.deleted = source["deleted"];
         this.hook = source["hook"];
+        this.skipped = source["skipped"];
         this.rendered = source["rendered"];
         this.remote = source["remote"];
         this.applied = source["applied"];
@@ -106,6 +170,102 @@ export class IgnoreForDiffItemConfig {
         this.namespace = source["namespace"];
     }
 }
+export class HelmVerifyConfig {
+    keyring?: string;
+    cosignKey?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.keyring = source["keyring"];
+        this.cosignKey = source["cosignKey"];
+    }
+}
+export class HelmPatchTargetConfig {
+    group?: string;
+    version?: string;
+    kind?: string;
+    name?: string;
+    namespace?: string;
+    labelSelector?: string;
+    annotationSelector?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.group = source["group"];
+        this.version = source["version"];
+        this.kind = source["kind"];
+        this.name = source["name"];
+        this.namespace = source["namespace"];
+        this.labelSelector = source["labelSelector"];
+        this.annotationSelector = source["annotationSelector"];
+    }
+}
+export class HelmPatchConfig {
+    patch: string;
+    target?: HelmPatchTargetConfig;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.patch = source["patch"];
+        this.target = this.convertValues(source["target"], HelmPatchTargetConfig);
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
+export class HelmExecPostRendererConfig {
+    command: string;
+    args?: string[];
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.command = source["command"];
+        this.args = source["args"];
+    }
+}
+export class HelmPostRendererConfig {
+    exec?: HelmExecPostRendererConfig;
+    patches?: HelmPatchConfig[];
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.exec = this.convertValues(source["exec"], HelmExecPostRendererConfig);
+        this.patches = this.convertValues(source["patches"], HelmPatchConfig);
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
 export class HelmChartConfig {
     repo?: string;
     path?: string;
@@ -119,6 +279,10 @@ export class HelmChartConfig {
     skipCRDs?: boolean;
     skipUpdate?: boolean;
     skipPrePull?: boolean;
+    enableLookup?: boolean;
+    lookupFixture?: string;
+    postRenderer?: HelmPostRendererConfig;
+    verify?: HelmVerifyConfig;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
@@ -134,7 +298,29 @@ export class HelmChartConfig {
         this.skipCRDs = source["skipCRDs"];
         this.skipUpdate = source["skipUpdate"];
         this.skipPrePull = source["skipPrePull"];
+        this.enableLookup = source["enableLookup"];
+        this.lookupFixture = source["lookupFixture"];
+        this.postRenderer = this.convertValues(source["postRenderer"], HelmPostRendererConfig);
+        this.verify = this.convertValues(source["verify"], HelmVerifyConfig);
     }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
 }
 export class DeleteObjectItemConfig {
     group?: string;
@@ -492,6 +678,50 @@ export class KluctlDeploymentInfo {
         this.clusterId = source["clusterId"];
     }
 }
+export class FixedImage {
+    image?: string;
+    imageRegex?: string;
+    resultImage: string;
+    deployedImage?: string;
+    namespace?: string;
+    object?: ObjectRef;
+    deployment?: string;
+    container?: string;
+    deployTags?: string[];
+    deploymentDir?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.image = source["image"];
+        this.imageRegex = source["imageRegex"];
+        this.resultImage = source["resultImage"];
+        this.deployedImage = source["deployedImage"];
+        this.namespace = source["namespace"];
+        this.object = this.convertValues(source["object"], ObjectRef);
+        this.deployment = source["deployment"];
+        this.container = source["container"];
+        this.deployTags = source["deployTags"];
+        this.deploymentDir = source["deploymentDir"];
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
 export class CommandInfo {
     initiator: string;
     startTime: string;
@@ -512,6 +742,7 @@ export class CommandInfo {
     excludeTags?: string[];
     includeDeploymentDirs?: string[];
     excludeDeploymentDirs?: string[];
+    traceId?: string;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
@@ -534,6 +765,7 @@ export class CommandInfo {
         this.excludeTags = source["excludeTags"];
         this.includeDeploymentDirs = source["includeDeploymentDirs"];
         this.excludeDeploymentDirs = source["excludeDeploymentDirs"];
+        this.traceId = source["traceId"];
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -554,6 +786,18 @@ export class CommandInfo {
 	    return a;
 	}
 }
+export class ImageUpdatePolicy {
+    semver?: string;
+    regex?: string;
+    numeric?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.semver = source["semver"];
+        this.regex = source["regex"];
+        this.numeric = source["numeric"];
+    }
+}
 export class ObjectRef {
     group?: string;
     version?: string;
@@ -570,7 +814,7 @@ export class ObjectRef {
         this.namespace = source["namespace"];
     }
 }
-export class FixedImage {
+export class UpdatableFixedImage {
     image?: string;
     imageRegex?: string;
     resultImage: string;
@@ -581,6 +825,7 @@ export class FixedImage {
     container?: string;
     deployTags?: string[];
     deploymentDir?: string;
+    updatePolicy?: ImageUpdatePolicy;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
@@ -594,6 +839,7 @@ export class FixedImage {
         this.container = source["container"];
         this.deployTags = source["deployTags"];
         this.deploymentDir = source["deploymentDir"];
+        this.updatePolicy = this.convertValues(source["updatePolicy"], ImageUpdatePolicy);
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -670,7 +916,7 @@ export class Target {
     args?: any;
     sealingConfig?: SealingConfig;
     aws?: AwsConfig;
-    images?: FixedImage[];
+    images?: UpdatableFixedImage[];
     discriminator?: string;
 
     constructor(source: any = {}) {
@@ -680,7 +926,7 @@ export class Target {
         this.args = source["args"];
         this.sealingConfig = this.convertValues(source["sealingConfig"], SealingConfig);
         this.aws = this.convertValues(source["aws"], AwsConfig);
-        this.images = this.convertValues(source["images"], FixedImage);
+        this.images = this.convertValues(source["images"], UpdatableFixedImage);
         this.discriminator = source["discriminator"];
     }
 
@@ -741,6 +987,8 @@ export class CommandResult {
     errors?: DeploymentError[];
     warnings?: DeploymentError[];
     seenImages?: FixedImage[];
+    helmLookups?: HelmLookup[];
+    sensitiveFields?: SensitiveField[];
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
@@ -760,6 +1008,8 @@ export class CommandResult {
         this.errors = this.convertValues(source["errors"], DeploymentError);
         this.warnings = this.convertValues(source["warnings"], DeploymentError);
         this.seenImages = this.convertValues(source["seenImages"], FixedImage);
+        this.helmLookups = this.convertValues(source["helmLookups"], HelmLookup);
+        this.sensitiveFields = this.convertValues(source["sensitiveFields"], SensitiveField);
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -802,6 +1052,7 @@ export class CommandResultSummary {
     errors: DeploymentError[];
     warnings: DeploymentError[];
     totalChanges: number;
+    pinned?: boolean;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
@@ -826,6 +1077,189 @@ export class CommandResultSummary {
         this.errors = this.convertValues(source["errors"], DeploymentError);
         this.warnings = this.convertValues(source["warnings"], DeploymentError);
         this.totalChanges = source["totalChanges"];
+        this.pinned = source["pinned"];
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
+export class ChangedObject {
+    ref: ObjectRef;
+    changes?: Change[];
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.ref = this.convertValues(source["ref"], ObjectRef);
+        this.changes = this.convertValues(source["changes"], Change);
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
+export class ImageChange {
+    image: string;
+    object?: ObjectRef;
+    container?: string;
+    oldResultImage?: string;
+    newResultImage?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.image = source["image"];
+        this.object = this.convertValues(source["object"], ObjectRef);
+        this.container = source["container"];
+        this.oldResultImage = source["oldResultImage"];
+        this.newResultImage = source["newResultImage"];
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
+export class CommandResultComparison {
+    oldResultId: string;
+    newResultId: string;
+    oldGitInfo: GitInfo;
+    newGitInfo: GitInfo;
+    varsChanges?: Change[];
+    imageChanges?: ImageChange[];
+    addedObjects?: ObjectRef[];
+    removedObjects?: ObjectRef[];
+    changedObjects?: ChangedObject[];
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.oldResultId = source["oldResultId"];
+        this.newResultId = source["newResultId"];
+        this.oldGitInfo = this.convertValues(source["oldGitInfo"], GitInfo);
+        this.newGitInfo = this.convertValues(source["newGitInfo"], GitInfo);
+        this.varsChanges = this.convertValues(source["varsChanges"], Change);
+        this.imageChanges = this.convertValues(source["imageChanges"], ImageChange);
+        this.addedObjects = this.convertValues(source["addedObjects"], ObjectRef);
+        this.removedObjects = this.convertValues(source["removedObjects"], ObjectRef);
+        this.changedObjects = this.convertValues(source["changedObjects"], ChangedObject);
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
+export class ObjectHistoryEntry {
+    resultId: string;
+    projectKey: ProjectKey;
+    targetKey: TargetKey;
+    kluctlDeployment?: KluctlDeploymentInfo;
+    command?: string;
+    initiator: string;
+    startTime: string;
+    gitInfo: GitInfo;
+    ref: ObjectRef;
+    event: string;
+    changes?: Change[];
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.resultId = source["resultId"];
+        this.projectKey = this.convertValues(source["projectKey"], ProjectKey);
+        this.targetKey = this.convertValues(source["targetKey"], TargetKey);
+        this.kluctlDeployment = this.convertValues(source["kluctlDeployment"], KluctlDeploymentInfo);
+        this.command = source["command"];
+        this.initiator = source["initiator"];
+        this.startTime = source["startTime"];
+        this.gitInfo = this.convertValues(source["gitInfo"], GitInfo);
+        this.ref = this.convertValues(source["ref"], ObjectRef);
+        this.event = source["event"];
+        this.changes = this.convertValues(source["changes"], Change);
+    }
+
+	convertValues(a: any, classs: any, asMap: boolean = false): any {
+	    if (!a) {
+	        return a;
+	    }
+	    if (a.slice) {
+	        return (a as any[]).map(elem => this.convertValues(elem, classs));
+	    } else if ("object" === typeof a) {
+	        if (asMap) {
+	            for (const key of Object.keys(a)) {
+	                a[key] = new classs(a[key]);
+	            }
+	            return a;
+	        }
+	        return new classs(a);
+	    }
+	    return a;
+	}
+}
+export class HelmTestResult {
+    releaseName: string;
+    ref: ObjectRef;
+    succeeded: boolean;
+    logs?: string;
+
+    constructor(source: any = {}) {
+        if ('string' === typeof source) source = JSON.parse(source);
+        this.releaseName = source["releaseName"];
+        this.ref = this.convertValues(source["ref"], ObjectRef);
+        this.succeeded = source["succeeded"];
+        this.logs = source["logs"];
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -890,6 +1324,7 @@ export class ValidateResult {
     warnings?: DeploymentError[];
     errors?: DeploymentError[];
     results?: ValidateResultEntry[];
+    helmTests?: HelmTestResult[];
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
@@ -906,6 +1341,7 @@ export class ValidateResult {
         this.warnings = this.convertValues(source["warnings"], DeploymentError);
         this.errors = this.convertValues(source["errors"], DeploymentError);
         this.results = this.convertValues(source["results"], ValidateResultEntry);
+        this.helmTests = this.convertValues(source["helmTests"], HelmTestResult);
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -981,6 +1417,7 @@ export class DriftedObject {
     orphan?: boolean;
     deleted?: boolean;
     hook?: boolean;
+    skipped?: boolean;
     lastResourceVersion: string;
 
     constructor(source: any = {}) {
@@ -991,6 +1428,7 @@ export class DriftedObject {
         this.orphan = source["orphan"];
         this.deleted = source["deleted"];
         this.hook = source["hook"];
+        this.skipped = source["skipped"];
         this.lastResourceVersion = source["lastResourceVersion"];
     }
 
@@ -1058,14 +1496,43 @@ export class DriftDetectionResult {
 	    return a;
 	}
 }
-export class ChangedObject {
-    ref: ObjectRef;
-    changes?: Change[];
+
+export class AuditEvent {
+    id: string;
+    time: string;
+    user?: string;
+    source: string;
+    action: string;
+    claimedUser?: string;
+    claimedSource?: string;
+    fieldManager?: string;
+    kluctlDeployment: KluctlDeploymentInfo;
+    projectKey?: ProjectKey;
+    targetKey?: TargetKey;
+    requestValue?: string;
+    objectsHash?: string;
+    reconcileId?: string;
+    commandResultId?: string;
+    error?: string;
 
     constructor(source: any = {}) {
         if ('string' === typeof source) source = JSON.parse(source);
-        this.ref = this.convertValues(source["ref"], ObjectRef);
-        this.changes = this.convertValues(source["changes"], Change);
+        this.id = source["id"];
+        this.time = source["time"];
+        this.user = source["user"];
+        this.source = source["source"];
+        this.action = source["action"];
+        this.claimedUser = source["claimedUser"];
+        this.claimedSource = source["claimedSource"];
+        this.fieldManager = source["fieldManager"];
+        this.kluctlDeployment = this.convertValues(source["kluctlDeployment"], KluctlDeploymentInfo);
+        this.projectKey = this.convertValues(source["projectKey"], ProjectKey);
+        this.targetKey = this.convertValues(source["targetKey"], TargetKey);
+        this.requestValue = source["requestValue"];
+        this.objectsHash = source["objectsHash"];
+        this.reconcileId = source["reconcileId"];
+        this.commandResultId = source["commandResultId"];
+        this.error = source["error"];
     }
 
 	convertValues(a: any, classs: any, asMap: boolean = false): any {
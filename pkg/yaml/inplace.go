@@ -0,0 +1,147 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	yaml3 "gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+// ParseNode parses the first document found in raw into a yaml node tree. The resulting nodes can be passed to
+// ReplaceScalars to modify single values without re-encoding the whole document.
+func ParseNode(raw []byte) (*yaml3.Node, error) {
+	var doc yaml3.Node
+	err := yaml3.Unmarshal(raw, &doc)
+	if err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml3.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("empty yaml document")
+	}
+	return doc.Content[0], nil
+}
+
+// GetMappingValue returns the value node of key if n is a mapping node and contains the key. Otherwise nil is returned.
+func GetMappingValue(n *yaml3.Node, key string) *yaml3.Node {
+	if n == nil || n.Kind != yaml3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// ReplaceScalars replaces the values of the given scalar nodes inside raw, keeping comments, key order and formatting
+// of the rest of the document intact. The nodes must have been parsed from raw via ParseNode. Only single-line plain,
+// single-quoted and double-quoted scalars are supported, an error is returned for all other nodes.
+func ReplaceScalars(raw []byte, replacements map[*yaml3.Node]string) ([]byte, error) {
+	lineStarts := []int{0}
+	for i, c := range raw {
+		if c == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+
+	type edit struct {
+		offset int
+		oldLen int
+		repr   string
+	}
+	var edits []edit
+	for n, v := range replacements {
+		if n.Kind != yaml3.ScalarNode {
+			return nil, fmt.Errorf("line %d: node is not a scalar", n.Line)
+		}
+		if n.Line < 1 || n.Line > len(lineStarts) {
+			return nil, fmt.Errorf("line %d: invalid node position", n.Line)
+		}
+		lineStart := lineStarts[n.Line-1]
+		line := raw[lineStart:]
+		if i := bytes.IndexByte(line, '\n'); i != -1 {
+			line = line[:i]
+		}
+
+		// columns are counted in characters, not bytes
+		offset := -1
+		col := 1
+		for i := range string(line) {
+			if col == n.Column {
+				offset = lineStart + i
+				break
+			}
+			col++
+		}
+		if offset == -1 {
+			return nil, fmt.Errorf("line %d: invalid node position", n.Line)
+		}
+
+		oldRepr, err := scalarRepr(n, n.Value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		if !bytes.HasPrefix(raw[offset:], []byte(oldRepr)) {
+			return nil, fmt.Errorf("line %d: value '%s' can not be replaced in-place", n.Line, n.Value)
+		}
+		newRepr, err := scalarRepr(n, v)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		edits = append(edits, edit{offset: offset, oldLen: len(oldRepr), repr: newRepr})
+	}
+
+	// apply edits from the end to the beginning, so that offsets stay valid
+	sort.Slice(edits, func(i, j int) bool {
+		return edits[i].offset > edits[j].offset
+	})
+	ret := append([]byte{}, raw...)
+	for _, e := range edits {
+		ret = append(ret[:e.offset], append([]byte(e.repr), ret[e.offset+e.oldLen:]...)...)
+	}
+	return ret, nil
+}
+
+// scalarRepr returns the raw representation of v when written with the same style as n
+func scalarRepr(n *yaml3.Node, v string) (string, error) {
+	if strings.ContainsAny(v, "\r\n") {
+		return "", fmt.Errorf("multi-line values are not supported")
+	}
+
+	var repr string
+	switch n.Style {
+	case 0:
+		repr = v
+	case yaml3.DoubleQuotedStyle:
+		if strings.ContainsAny(v, "\"\\") {
+			return "", fmt.Errorf("value '%s' can not be written as double-quoted string", v)
+		}
+		repr = `"` + v + `"`
+	case yaml3.SingleQuotedStyle:
+		repr = `'` + strings.ReplaceAll(v, `'`, `''`) + `'`
+	default:
+		return "", fmt.Errorf("unsupported scalar style")
+	}
+
+	// ensure that the representation is parsed back into the same value, e.g. plain scalars must not contain
+	// characters with special meaning
+	var parsed string
+	err := yaml3.Unmarshal([]byte(repr), &parsed)
+	if err != nil || parsed != v {
+		return "", fmt.Errorf("value '%s' can not be written as %s", v, styleName(n.Style))
+	}
+	return repr, nil
+}
+
+func styleName(s yaml3.Style) string {
+	switch s {
+	case yaml3.DoubleQuotedStyle:
+		return "double-quoted string"
+	case yaml3.SingleQuotedStyle:
+		return "single-quoted string"
+	default:
+		return "plain string"
+	}
+}
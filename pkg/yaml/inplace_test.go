@@ -0,0 +1,55 @@
+package yaml
+
+import (
+	"github.com/stretchr/testify/assert"
+	yaml3 "gopkg.in/yaml.v3"
+	"testing"
+)
+
+func TestReplaceScalars(t *testing.T) {
+	raw := []byte(`# comment
+b: 1
+a:
+  # nested comment
+  - x: "ä/old:1.0" # trailing comment
+  - x: 'old:1.0'
+  - x: old:1.0
+`)
+	root, err := ParseNode(raw)
+	assert.NoError(t, err)
+
+	l := GetMappingValue(root, "a")
+	assert.Equal(t, yaml3.SequenceNode, l.Kind)
+
+	r := map[*yaml3.Node]string{}
+	for _, x := range l.Content {
+		n := GetMappingValue(x, "x")
+		r[n] = n.Value + ".1"
+	}
+	ret, err := ReplaceScalars(raw, r)
+	assert.NoError(t, err)
+	assert.Equal(t, `# comment
+b: 1
+a:
+  # nested comment
+  - x: "ä/old:1.0.1" # trailing comment
+  - x: 'old:1.0.1'
+  - x: old:1.0.1
+`, string(ret))
+}
+
+func TestReplaceScalarsErrors(t *testing.T) {
+	raw := []byte(`
+a: |
+  multi
+b: x
+`)
+	root, err := ParseNode(raw)
+	assert.NoError(t, err)
+
+	_, err = ReplaceScalars(raw, map[*yaml3.Node]string{GetMappingValue(root, "a"): "x"})
+	assert.ErrorContains(t, err, "multi-line values are not supported")
+
+	_, err = ReplaceScalars(raw, map[*yaml3.Node]string{GetMappingValue(root, "b"): "x # y"})
+	assert.ErrorContains(t, err, "can not be written as plain string")
+}